@@ -3,12 +3,17 @@ package application
 import (
 	"bytes"
 	"fmt"
+	"github.com/duck8823/duci/domain/model/docker"
+	"github.com/duck8823/duci/domain/model/job"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -22,6 +27,9 @@ const (
 var (
 	// Config is a application configuration.
 	Config *Configuration
+
+	// ErrRepositoryNotAllowed represents a error of repository not configured.
+	ErrRepositoryNotAllowed = errors.New("repository not allowed")
 )
 
 type maskString string
@@ -38,28 +46,137 @@ func (s maskString) String() string {
 
 // Configuration of application.
 type Configuration struct {
-	Server *Server `yaml:"server" json:"server"`
-	GitHub *GitHub `yaml:"github" json:"github"`
-	Job    *Job    `yaml:"job" json:"job"`
+	Server       *Server      `yaml:"server" json:"server"`
+	GitHub       *GitHub      `yaml:"github" json:"github"`
+	Job          *Job         `yaml:"job" json:"job"`
+	Repositories Repositories `yaml:"repositories" json:"repositories"`
+	Retention    *Retention   `yaml:"retention" json:"retention"`
 }
 
 // Server describes a configuration of server.
 type Server struct {
-	WorkDir      string `yaml:"workdir" json:"workdir"`
-	Port         int    `yaml:"port" json:"port"`
-	DatabasePath string `yaml:"database_path" json:"databasePath"`
+	WorkDir      string     `yaml:"workdir" json:"workdir"`
+	Port         int        `yaml:"port" json:"port"`
+	DatabasePath string     `yaml:"database_path" json:"databasePath"`
+	GracePeriod  int64      `yaml:"grace_period" json:"gracePeriod"`
+	AdminToken   maskString `yaml:"admin_token" json:"adminToken"`
 }
 
 // GitHub describes a configuration of github.
 type GitHub struct {
-	SSHKeyPath string     `yaml:"ssh_key_path" json:"sshKeyPath"`
-	APIToken   maskString `yaml:"api_token" json:"apiToken"`
+	SSHKeyPath    string      `yaml:"ssh_key_path" json:"sshKeyPath"`
+	APIToken      maskString  `yaml:"api_token" json:"apiToken"`
+	WebhookSecret maskString  `yaml:"webhook_secret" json:"webhookSecret"`
+	Commenters    *Commenters `yaml:"commenters" json:"commenters"`
+}
+
+// Commenters describes who can trigger builds with pull request comment.
+type Commenters struct {
+	Users      []string `yaml:"users" json:"users"`
+	Teams      []string `yaml:"teams" json:"teams"`
+	Permission string   `yaml:"permission" json:"permission"`
+	Reply      string   `yaml:"reply" json:"reply"`
+}
+
+const (
+	// ReplyReason is a value of Commenters.Reply to reply to ignored comments with the reason. It is the default.
+	ReplyReason = "reason"
+	// ReplyComment is a value of Commenters.Reply to reply to ignored comments without the reason, not to disclose the policy.
+	ReplyComment = "comment"
+	// ReplyReaction is a value of Commenters.Reply to react to ignored comments instead of replying with a comment.
+	ReplyReaction = "reaction"
+)
+
+// ReactsOnly returns whether ignored comments are answered with only a reaction or not.
+func (c *Commenters) ReactsOnly() bool {
+	return c != nil && c.Reply == ReplyReaction
+}
+
+// RepliesReason returns whether replies to ignored comments include the reason or not.
+func (c *Commenters) RepliesReason() bool {
+	return c == nil || (c.Reply != ReplyComment && c.Reply != ReplyReaction)
+}
+
+// IsRestricted returns whether any policy is configured or not.
+func (c *Commenters) IsRestricted() bool {
+	if c == nil {
+		return false
+	}
+	return len(c.Users) > 0 || len(c.Teams) > 0 || len(c.Permission) > 0
 }
 
 // Job describes a configuration of each jobs.
 type Job struct {
-	Timeout     int64 `yaml:"timeout" json:"timeout"`
-	Concurrency int   `yaml:"concurrency" json:"concurrency"`
+	Timeout        int64            `yaml:"timeout" json:"timeout"`
+	Concurrency    int              `yaml:"concurrency" json:"concurrency"`
+	AutoCancel     bool             `yaml:"auto_cancel" json:"autoCancel"`
+	Secrets        []string         `yaml:"secrets" json:"secrets"`
+	MaxLogSize     int64            `yaml:"max_log_size" json:"maxLogSize"`
+	MaxLineLength  int              `yaml:"max_line_length" json:"maxLineLength"`
+	FailOnLogLimit bool             `yaml:"fail_on_log_limit" json:"failOnLogLimit"`
+	Resources      docker.Resources `yaml:"resources" json:"resources"`
+	MaxResources   docker.Limits    `yaml:"max_resources" json:"maxResources"`
+}
+
+// Retention describes limits to keep finished jobs in the database. Zero means no limit.
+type Retention struct {
+	MaxAge   int64 `yaml:"max_age" json:"maxAge"`
+	MaxJobs  int   `yaml:"max_jobs" json:"maxJobs"`
+	MaxSize  int64 `yaml:"max_size" json:"maxSize"`
+	Interval int64 `yaml:"interval" json:"interval"`
+}
+
+// Repositories describes configurations of repositories keyed by full name or glob pattern.
+type Repositories map[string]*Repository
+
+// Repository describes overrides of configuration for specific repositories.
+type Repository struct {
+	Timeout       int64      `yaml:"timeout" json:"timeout"`
+	Concurrency   int        `yaml:"concurrency" json:"concurrency"`
+	SSHKeyPath    string     `yaml:"ssh_key_path" json:"sshKeyPath"`
+	APIToken      maskString `yaml:"api_token" json:"apiToken"`
+	ContextPrefix string     `yaml:"context_prefix" json:"contextPrefix"`
+	AutoCancel    *bool      `yaml:"auto_cancel" json:"autoCancel,omitempty"`
+}
+
+// Match returns the most specific pattern matches the full name.
+// Exact name takes precedence over glob patterns, and pattern with fewer wildcards takes precedence.
+func (r Repositories) Match(fullName string) (pattern string, ok bool) {
+	if _, ok := r[fullName]; ok {
+		return fullName, true
+	}
+
+	var patterns []string
+	for pattern := range r {
+		if matched, err := path.Match(pattern, fullName); err == nil && matched {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if len(patterns) == 0 {
+		return "", false
+	}
+
+	sort.Slice(patterns, func(i, j int) bool {
+		wi, wj := strings.Count(patterns[i], "*"), strings.Count(patterns[j], "*")
+		if wi != wj {
+			return wi < wj
+		}
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	return patterns[0], true
+}
+
+// IsAutoCancel returns whether a new job cancels jobs for older commits on the same ref.
+func (r *Repository) IsAutoCancel() bool {
+	return r.AutoCancel != nil && *r.AutoCancel
+}
+
+// TimeoutDuration returns timeout duration.
+func (r *Repository) TimeoutDuration() time.Duration {
+	return time.Duration(r.Timeout) * time.Second
 }
 
 func init() {
@@ -68,15 +185,21 @@ func init() {
 			WorkDir:      filepath.Join(os.TempDir(), Name),
 			Port:         8080,
 			DatabasePath: filepath.Join(os.Getenv("HOME"), ".duci/db"),
+			GracePeriod:  30,
+			AdminToken:   maskString(os.Getenv("DUCI_ADMIN_TOKEN")),
 		},
 		GitHub: &GitHub{
-			SSHKeyPath: os.Getenv("SSH_KEY_PATH"),
-			APIToken:   maskString(os.Getenv("GITHUB_API_TOKEN")),
+			SSHKeyPath:    os.Getenv("SSH_KEY_PATH"),
+			APIToken:      maskString(os.Getenv("GITHUB_API_TOKEN")),
+			WebhookSecret: maskString(os.Getenv("GITHUB_WEBHOOK_SECRET")),
 		},
 		Job: &Job{
 			Timeout:     600,
 			Concurrency: runtime.NumCPU(),
 		},
+		Retention: &Retention{
+			Interval: 60,
+		},
 	}
 }
 
@@ -110,3 +233,98 @@ func (c *Configuration) Addr() string {
 func (c *Configuration) Timeout() time.Duration {
 	return time.Duration(c.Job.Timeout) * time.Second
 }
+
+// GracePeriod returns duration to wait for running jobs on shutdown.
+func (c *Configuration) GracePeriod() time.Duration {
+	return time.Duration(c.Server.GracePeriod) * time.Second
+}
+
+// RetentionPolicy returns limits to keep jobs. Max age is in days and max size is in megabytes.
+func (c *Configuration) RetentionPolicy() job.Retention {
+	return job.Retention{
+		MaxAge:  time.Duration(c.Retention.MaxAge) * 24 * time.Hour,
+		MaxJobs: c.Retention.MaxJobs,
+		MaxSize: c.Retention.MaxSize * 1024 * 1024,
+	}
+}
+
+// GCInterval returns interval of garbage collection. Interval is in minutes.
+func (c *Configuration) GCInterval() time.Duration {
+	return time.Duration(c.Retention.Interval) * time.Minute
+}
+
+// LogLimit returns caps of log per job. Max log size is in kilobytes and max line length is in bytes.
+func (c *Configuration) LogLimit() job.LogLimit {
+	return job.LogLimit{
+		MaxSize:       c.Job.MaxLogSize * 1024,
+		MaxLineLength: c.Job.MaxLineLength,
+	}
+}
+
+// Secrets returns tokens in the configuration and values of environment variables named in job secrets.
+func (c *Configuration) Secrets() []string {
+	secrets := []string{c.GitHub.APIToken.String(), c.GitHub.WebhookSecret.String()}
+	for _, repo := range c.Repositories {
+		if repo != nil {
+			secrets = append(secrets, repo.APIToken.String())
+		}
+	}
+	for _, name := range c.Job.Secrets {
+		secrets = append(secrets, os.Getenv(name))
+	}
+	return secrets
+}
+
+// Repository returns a configuration of the repository filled with default values.
+// It returns ErrRepositoryNotAllowed if repositories are configured but none of them matches.
+func (c *Configuration) Repository(fullName string) (*Repository, error) {
+	repo := c.DefaultRepository()
+	if len(c.Repositories) == 0 {
+		return repo, nil
+	}
+
+	pattern, ok := c.Repositories.Match(fullName)
+	if !ok {
+		return nil, errors.Wrapf(ErrRepositoryNotAllowed, "%s is not configured in this server", fullName)
+	}
+
+	override := c.Repositories[pattern]
+	if override == nil {
+		return repo, nil
+	}
+	if override.Timeout > 0 {
+		repo.Timeout = override.Timeout
+	}
+	if override.Concurrency > 0 {
+		repo.Concurrency = override.Concurrency
+	}
+	if len(override.SSHKeyPath) > 0 {
+		repo.SSHKeyPath = override.SSHKeyPath
+	}
+	if len(override.APIToken) > 0 {
+		repo.APIToken = override.APIToken
+	}
+	if len(override.ContextPrefix) > 0 {
+		repo.ContextPrefix = override.ContextPrefix
+	}
+	if override.AutoCancel != nil {
+		repo.AutoCancel = override.AutoCancel
+	}
+	return repo, nil
+}
+
+// DefaultRepository returns a configuration of repository with server-wide values.
+// Concurrency is zero because it is limited only by the server-wide semaphore.
+func (c *Configuration) DefaultRepository() *Repository {
+	repo := &Repository{
+		Timeout:       c.Job.Timeout,
+		SSHKeyPath:    c.GitHub.SSHKeyPath,
+		APIToken:      c.GitHub.APIToken,
+		ContextPrefix: Name,
+	}
+	if c.Job.AutoCancel {
+		autoCancel := true
+		repo.AutoCancel = &autoCancel
+	}
+	return repo
+}
@@ -2,7 +2,10 @@ package application_test
 
 import (
 	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/domain/model/docker"
+	"github.com/duck8823/duci/domain/model/job"
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
 	"os"
 	"reflect"
 	"testing"
@@ -27,14 +30,39 @@ func TestConfiguration_Set(t *testing.T) {
 				WorkDir:      "/path/to/workdir",
 				Port:         8823,
 				DatabasePath: "/path/to/database",
+				GracePeriod:  60,
+				AdminToken:   "duci_admin_token",
 			},
 			GitHub: &application.GitHub{
-				SSHKeyPath: "/path/to/ssh_key",
-				APIToken:   "github_api_token",
+				SSHKeyPath:    "/path/to/ssh_key",
+				APIToken:      "github_api_token",
+				WebhookSecret: "github_webhook_secret",
+				Commenters: &application.Commenters{
+					Users:      []string{"duck8823"},
+					Teams:      []string{"duck8823/maintainers"},
+					Permission: "write",
+				},
 			},
 			Job: &application.Job{
 				Timeout:     300,
 				Concurrency: 5,
+				Secrets:     []string{"AWS_SECRET_ACCESS_KEY"},
+				Resources: docker.Resources{
+					CPUs:    1,
+					Memory:  1024 * 1024 * 1024,
+					CapDrop: []string{"NET_RAW"},
+				},
+				MaxResources: docker.Limits{
+					CPUs:         2,
+					Memory:       2 * 1024 * 1024 * 1024,
+					NetworkModes: []string{"bridge", "none"},
+				},
+			},
+			Retention: &application.Retention{
+				MaxAge:   30,
+				MaxJobs:  100,
+				MaxSize:  1024,
+				Interval: 10,
 			},
 		}
 
@@ -115,6 +143,386 @@ func TestConfiguration_Timeout(t *testing.T) {
 	}
 }
 
+func TestConfiguration_GracePeriod(t *testing.T) {
+	// given
+	application.Config.Server.GracePeriod = 8823
+
+	// when
+	actual := application.Config.GracePeriod()
+
+	// then
+	if actual != 8823*time.Second {
+		t.Errorf("grace period should equal 8823 sec, but got %+v", actual)
+	}
+}
+
+func TestConfiguration_RetentionPolicy(t *testing.T) {
+	// given
+	application.Config.Retention = &application.Retention{MaxAge: 30, MaxJobs: 100, MaxSize: 1024}
+
+	// when
+	actual := application.Config.RetentionPolicy()
+
+	// then
+	expected := job.Retention{
+		MaxAge:  30 * 24 * time.Hour,
+		MaxJobs: 100,
+		MaxSize: 1024 * 1024 * 1024,
+	}
+	if actual != expected {
+		t.Errorf("retention should equal %+v, but got %+v", expected, actual)
+	}
+}
+
+func TestConfiguration_GCInterval(t *testing.T) {
+	// given
+	application.Config.Retention = &application.Retention{Interval: 8823}
+
+	// when
+	actual := application.Config.GCInterval()
+
+	// then
+	if actual != 8823*time.Minute {
+		t.Errorf("interval should equal 8823 min, but got %+v", actual)
+	}
+}
+
+func TestConfiguration_LogLimit(t *testing.T) {
+	// given
+	application.Config.Job = &application.Job{MaxLogSize: 10, MaxLineLength: 2000}
+
+	// when
+	actual := application.Config.LogLimit()
+
+	// then
+	expected := job.LogLimit{
+		MaxSize:       10 * 1024,
+		MaxLineLength: 2000,
+	}
+	if actual != expected {
+		t.Errorf("log limit should equal %+v, but got %+v", expected, actual)
+	}
+}
+
+func TestConfiguration_Secrets(t *testing.T) {
+	// given
+	_ = os.Setenv("TEST_SECRET_ENV", "secret_value")
+	defer os.Unsetenv("TEST_SECRET_ENV")
+
+	// and
+	sut := &application.Configuration{
+		GitHub: &application.GitHub{
+			APIToken:      "github_api_token",
+			WebhookSecret: "github_webhook_secret",
+		},
+		Job: &application.Job{
+			Secrets: []string{"TEST_SECRET_ENV"},
+		},
+		Repositories: application.Repositories{
+			"duck8823/duci": {APIToken: "repository_api_token"},
+			"duck8823/*":    nil,
+		},
+	}
+
+	// when
+	actual := sut.Secrets()
+
+	// then
+	expected := []string{"github_api_token", "github_webhook_secret", "repository_api_token", "secret_value"}
+	if !cmp.Equal(actual, expected) {
+		t.Errorf("secrets should equal, but: %+v", cmp.Diff(actual, expected))
+	}
+}
+
+func TestCommenters_IsRestricted(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		name string
+		sut  *application.Commenters
+		want bool
+	}{
+		{
+			name: "when nil",
+			sut:  nil,
+			want: false,
+		},
+		{
+			name: "when empty",
+			sut:  &application.Commenters{},
+			want: false,
+		},
+		{
+			name: "with users",
+			sut:  &application.Commenters{Users: []string{"duck8823"}},
+			want: true,
+		},
+		{
+			name: "with teams",
+			sut:  &application.Commenters{Teams: []string{"duck8823/maintainers"}},
+			want: true,
+		},
+		{
+			name: "with permission",
+			sut:  &application.Commenters{Permission: "write"},
+			want: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			got := tt.sut.IsRestricted()
+
+			// then
+			if got != tt.want {
+				t.Errorf("must be %t, but got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCommenters_ReactsOnly(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		name string
+		sut  *application.Commenters
+		want bool
+	}{
+		{
+			name: "when nil",
+			sut:  nil,
+			want: false,
+		},
+		{
+			name: "when reply is empty",
+			sut:  &application.Commenters{},
+			want: false,
+		},
+		{
+			name: "when reply is comment",
+			sut:  &application.Commenters{Reply: "comment"},
+			want: false,
+		},
+		{
+			name: "when reply is reaction",
+			sut:  &application.Commenters{Reply: application.ReplyReaction},
+			want: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			got := tt.sut.ReactsOnly()
+
+			// then
+			if got != tt.want {
+				t.Errorf("must be %t, but got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRepositories_Match(t *testing.T) {
+	// given
+	sut := application.Repositories{
+		"duck8823/duci": nil,
+		"duck8823/*":    nil,
+		"duck8823/du*":  nil,
+		"*/*":           nil,
+	}
+
+	// where
+	for _, tt := range []struct {
+		name     string
+		fullName string
+		want     string
+		ok       bool
+	}{
+		{
+			name:     "when exact match",
+			fullName: "duck8823/duci",
+			want:     "duck8823/duci",
+			ok:       true,
+		},
+		{
+			name:     "when longer glob match",
+			fullName: "duck8823/dummy",
+			want:     "duck8823/du*",
+			ok:       true,
+		},
+		{
+			name:     "when owner glob match",
+			fullName: "duck8823/hoge",
+			want:     "duck8823/*",
+			ok:       true,
+		},
+		{
+			name:     "when only wildcard match",
+			fullName: "octocat/hoge",
+			want:     "*/*",
+			ok:       true,
+		},
+		{
+			name:     "when no match",
+			fullName: "hoge",
+			want:     "",
+			ok:       false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			got, ok := sut.Match(tt.fullName)
+
+			// then
+			if ok != tt.ok {
+				t.Errorf("ok must be %t, but got %t", tt.ok, ok)
+			}
+
+			if got != tt.want {
+				t.Errorf("must be %s, but got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConfiguration_Repository(t *testing.T) {
+	// given
+	defaults := &application.Repository{
+		Timeout:       600,
+		SSHKeyPath:    "path/to/key",
+		APIToken:      application.MaskString("token"),
+		ContextPrefix: application.Name,
+	}
+
+	// and
+	newConfig := func(repos application.Repositories) *application.Configuration {
+		return &application.Configuration{
+			GitHub:       &application.GitHub{SSHKeyPath: "path/to/key", APIToken: application.MaskString("token")},
+			Job:          &application.Job{Timeout: 600, Concurrency: 4},
+			Repositories: repos,
+		}
+	}
+
+	t.Run("when repositories are not configured", func(t *testing.T) {
+		// given
+		sut := newConfig(nil)
+
+		// when
+		got, err := sut.Repository("duck8823/duci")
+
+		// then
+		if err != nil {
+			t.Errorf("error must not occur, but got %+v", err)
+		}
+
+		if !cmp.Equal(got, defaults) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, defaults))
+		}
+	})
+
+	t.Run("when repository matches", func(t *testing.T) {
+		// given
+		sut := newConfig(application.Repositories{
+			"duck8823/*": &application.Repository{
+				Timeout:       300,
+				Concurrency:   2,
+				APIToken:      application.MaskString("override"),
+				ContextPrefix: "ci",
+			},
+		})
+
+		// and
+		want := &application.Repository{
+			Timeout:       300,
+			Concurrency:   2,
+			SSHKeyPath:    "path/to/key",
+			APIToken:      application.MaskString("override"),
+			ContextPrefix: "ci",
+		}
+
+		// when
+		got, err := sut.Repository("duck8823/duci")
+
+		// then
+		if err != nil {
+			t.Errorf("error must not occur, but got %+v", err)
+		}
+
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("when repository matches without overrides", func(t *testing.T) {
+		// given
+		sut := newConfig(application.Repositories{"duck8823/duci": nil})
+
+		// when
+		got, err := sut.Repository("duck8823/duci")
+
+		// then
+		if err != nil {
+			t.Errorf("error must not occur, but got %+v", err)
+		}
+
+		if !cmp.Equal(got, defaults) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, defaults))
+		}
+	})
+
+	t.Run("with auto cancel", func(t *testing.T) {
+		// given
+		enabled, disabled := true, false
+
+		// where
+		for _, tt := range []struct {
+			name     string
+			global   bool
+			override *bool
+			want     bool
+		}{
+			{name: "when enabled globally", global: true, override: nil, want: true},
+			{name: "when disabled by repository", global: true, override: &disabled, want: false},
+			{name: "when enabled by repository", global: false, override: &enabled, want: true},
+			{name: "when not configured", global: false, override: nil, want: false},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				// given
+				sut := newConfig(application.Repositories{
+					"duck8823/duci": &application.Repository{AutoCancel: tt.override},
+				})
+				sut.Job.AutoCancel = tt.global
+
+				// when
+				got, err := sut.Repository("duck8823/duci")
+
+				// then
+				if err != nil {
+					t.Errorf("error must not occur, but got %+v", err)
+				}
+
+				if got.IsAutoCancel() != tt.want {
+					t.Errorf("must be %t, but got %t", tt.want, got.IsAutoCancel())
+				}
+			})
+		}
+	})
+
+	t.Run("when repository does not match", func(t *testing.T) {
+		// given
+		sut := newConfig(application.Repositories{"duck8823/*": nil})
+
+		// when
+		got, err := sut.Repository("octocat/hoge")
+
+		// then
+		if errors.Cause(err) != application.ErrRepositoryNotAllowed {
+			t.Errorf("error must be %+v, but got %+v", application.ErrRepositoryNotAllowed, err)
+		}
+
+		if got != nil {
+			t.Errorf("must be nil, but got %+v", got)
+		}
+	})
+}
+
 func TestMaskString_MarshalJSON(t *testing.T) {
 	// given
 	sut := application.MaskString("hoge")
@@ -147,3 +555,48 @@ func TestMaskString_String(t *testing.T) {
 		t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
 	}
 }
+
+func TestCommenters_RepliesReason(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		name string
+		sut  *application.Commenters
+		want bool
+	}{
+		{
+			name: "when nil",
+			sut:  nil,
+			want: true,
+		},
+		{
+			name: "when reply is empty",
+			sut:  &application.Commenters{},
+			want: true,
+		},
+		{
+			name: "when reply is reason",
+			sut:  &application.Commenters{Reply: application.ReplyReason},
+			want: true,
+		},
+		{
+			name: "when reply is comment",
+			sut:  &application.Commenters{Reply: application.ReplyComment},
+			want: false,
+		},
+		{
+			name: "when reply is reaction",
+			sut:  &application.Commenters{Reply: application.ReplyReaction},
+			want: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			got := tt.sut.RepliesReason()
+
+			// then
+			if got != tt.want {
+				t.Errorf("must be %t, but got %t", tt.want, got)
+			}
+		})
+	}
+}
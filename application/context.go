@@ -5,20 +5,36 @@ import (
 	"fmt"
 	"github.com/duck8823/duci/domain/model/job"
 	"github.com/duck8823/duci/domain/model/job/target/github"
+	"github.com/duck8823/duci/domain/model/task"
 	"net/url"
+	"sync"
 	"time"
 )
 
 var ctxKey = "duci_job"
 
+// logLimiterMu guards creation of limiters of log, which steps of a job can request concurrently
+var logLimiterMu sync.Mutex
+
 // BuildJob represents once of job
 type BuildJob struct {
 	ID           job.ID
 	TargetSource *github.TargetSource
 	TaskName     string
 	TargetURL    *url.URL
+	Event        string
+	Source       github.Repository
+	Command      []string
+	RerunOf      *job.ID
+	Task         string
+	Timeout      time.Duration
+	Matrix       task.Combination
+	Aggregates   []job.Aggregate
+	Needs        []job.ID
+	QueuedAt     time.Time // when the event was received, kept by reruns and resumed jobs to order them
 	beginTime    time.Time
 	endTime      time.Time
+	logLimiter   *job.LogLimiter
 }
 
 // BeginAt set a time that begin job
@@ -31,6 +47,18 @@ func (j *BuildJob) EndAt(time time.Time) {
 	j.endTime = time
 }
 
+// LogLimiter returns the limiter of log shared by all steps of the job.
+// It is safe to call from steps running concurrently.
+func (j *BuildJob) LogLimiter() *job.LogLimiter {
+	logLimiterMu.Lock()
+	defer logLimiterMu.Unlock()
+
+	if j.logLimiter == nil {
+		j.logLimiter = job.NewLogLimiter(Config.LogLimit())
+	}
+	return j.logLimiter
+}
+
 // Duration returns job duration
 func (j *BuildJob) Duration() string {
 	dur := j.endTime.Sub(j.beginTime)
@@ -40,6 +68,43 @@ func (j *BuildJob) Duration() string {
 	return fmt.Sprintf("%dsec", int(dur.Seconds()))
 }
 
+// Trigger returns metadata describes what the job was triggered by
+func (j *BuildJob) Trigger() job.Trigger {
+	trigger := job.Trigger{
+		Event:    j.Event,
+		TaskName: j.TaskName,
+	}
+	if j.TargetSource != nil {
+		if j.TargetSource.Repository != nil {
+			trigger.Repository = j.TargetSource.GetFullName()
+		}
+		trigger.Ref = j.TargetSource.GetRef()
+		trigger.SHA = j.TargetSource.GetSHA().String()
+	}
+	if j.TargetURL != nil {
+		trigger.TargetURL = j.TargetURL.String()
+	}
+	if j.Source != nil {
+		trigger.Source = &job.Source{
+			FullName: j.Source.GetFullName(),
+			SSHURL:   j.Source.GetSSHURL(),
+			CloneURL: j.Source.GetCloneURL(),
+		}
+	}
+	trigger.Command = j.Command
+	if j.RerunOf != nil {
+		trigger.RerunOf = j.RerunOf.String()
+	}
+	trigger.Task = j.Task
+	trigger.Timeout = int64(j.Timeout / time.Second)
+	trigger.Matrix = j.Matrix
+	trigger.Aggregates = j.Aggregates
+	for _, need := range j.Needs {
+		trigger.Needs = append(trigger.Needs, need.String())
+	}
+	return trigger
+}
+
 // ContextWithJob set parent context BuildJob and returns it.
 func ContextWithJob(parent context.Context, job *BuildJob) context.Context {
 	return context.WithValue(parent, &ctxKey, job)
@@ -4,8 +4,14 @@ import (
 	"context"
 	"github.com/duck8823/duci/application"
 	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/domain/model/job/target/github"
+	"github.com/duck8823/duci/domain/model/task"
 	"github.com/google/go-cmp/cmp"
+	go_github "github.com/google/go-github/github"
 	"github.com/google/uuid"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"net/url"
+	"sync"
 	"testing"
 	"time"
 )
@@ -110,6 +116,32 @@ func TestBuildJob_EndAt(t *testing.T) {
 	}
 }
 
+func TestBuildJob_LogLimiter(t *testing.T) {
+	// given
+	sut := &application.BuildJob{ID: job.ID(uuid.New())}
+
+	// when
+	limiters := make(chan *job.LogLimiter, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(limiters); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limiters <- sut.LogLimiter()
+		}()
+	}
+	wg.Wait()
+	close(limiters)
+
+	// then
+	want := sut.LogLimiter()
+	for got := range limiters {
+		if got != want {
+			t.Errorf("limiter must be shared, but got %p and %p", got, want)
+		}
+	}
+}
+
 func TestBuildJob_Duration(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -153,3 +185,134 @@ func TestBuildJob_Duration(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildJob_Trigger(t *testing.T) {
+	t.Run("with target source", func(t *testing.T) {
+		// given
+		sut := &application.BuildJob{
+			ID: job.ID(uuid.New()),
+			TargetSource: &github.TargetSource{
+				Repository: &go_github.Repository{FullName: go_github.String("duck8823/duci")},
+				Ref:        "refs/heads/master",
+				SHA:        plumbing.NewHash("aa218f56b14c9653891f9e74264a383fa43fefbd"),
+			},
+			TaskName:  "duci/push",
+			TargetURL: &url.URL{Scheme: "http", Host: "example.com", Path: "/logs/hoge"},
+			Event:     "push",
+		}
+
+		// and
+		want := job.Trigger{
+			Repository: "duck8823/duci",
+			Ref:        "refs/heads/master",
+			SHA:        "aa218f56b14c9653891f9e74264a383fa43fefbd",
+			Event:      "push",
+			TaskName:   "duci/push",
+			TargetURL:  "http://example.com/logs/hoge",
+		}
+
+		// when
+		got := sut.Trigger()
+
+		// then
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("with source, command and original job", func(t *testing.T) {
+		// given
+		rerunOf := job.ID(uuid.New())
+		sut := &application.BuildJob{
+			ID:       job.ID(uuid.New()),
+			TaskName: "duci/pr/test",
+			Source: &go_github.Repository{
+				FullName: go_github.String("octocat/duci"),
+				SSHURL:   go_github.String("git@github.com:octocat/duci.git"),
+				CloneURL: go_github.String("https://github.com/octocat/duci.git"),
+			},
+			Command: []string{"test"},
+			RerunOf: &rerunOf,
+		}
+
+		// and
+		want := job.Trigger{
+			TaskName: "duci/pr/test",
+			Source: &job.Source{
+				FullName: "octocat/duci",
+				SSHURL:   "git@github.com:octocat/duci.git",
+				CloneURL: "https://github.com/octocat/duci.git",
+			},
+			Command: []string{"test"},
+			RerunOf: rerunOf.String(),
+		}
+
+		// when
+		got := sut.Trigger()
+
+		// then
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("with task", func(t *testing.T) {
+		// given
+		sut := &application.BuildJob{
+			ID:       job.ID(uuid.New()),
+			TaskName: "duci/test",
+			Command:  []string{"make", "test"},
+			Task:     "test",
+			Timeout:  90 * time.Second,
+			Matrix:   task.Combination{"GO": "1.13"},
+			Aggregates: []job.Aggregate{{
+				Context: "duci/test",
+				Members: []string{"duci/test (GO=1.13)"},
+			}},
+			Needs: []job.ID{job.ID(uuid.MustParse("72d3162e-cc78-11e3-81ab-4c9367dc0958"))},
+		}
+
+		// and
+		want := job.Trigger{
+			TaskName: "duci/test",
+			Command:  []string{"make", "test"},
+			Task:     "test",
+			Timeout:  90,
+			Matrix:   map[string]string{"GO": "1.13"},
+			Aggregates: []job.Aggregate{{
+				Context: "duci/test",
+				Members: []string{"duci/test (GO=1.13)"},
+			}},
+			Needs: []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+		}
+
+		// when
+		got := sut.Trigger()
+
+		// then
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("without target source", func(t *testing.T) {
+		// given
+		sut := &application.BuildJob{
+			ID:       job.ID(uuid.New()),
+			TaskName: "duci/push",
+		}
+
+		// and
+		want := job.Trigger{
+			TaskName: "duci/push",
+		}
+
+		// when
+		got := sut.Trigger()
+
+		// then
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+}
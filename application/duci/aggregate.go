@@ -0,0 +1,129 @@
+package duci
+
+import (
+	"context"
+	"fmt"
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/domain/model/job/target/github"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"hash/fnv"
+	"net/url"
+	"sync"
+)
+
+// aggregateLocks serializes computing and posting of aggregate statuses.
+// Without them, a summary computed earlier can be posted after a newer one, and GitHub keeps the last.
+var aggregateLocks [64]sync.Mutex
+
+// aggregateLockOf returns the lock of the aggregate status for the commit
+func aggregateLockOf(trigger job.Trigger, name string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = fmt.Fprintf(h, "%s\x00%s\x00%s", trigger.Repository, trigger.SHA, name)
+	return &aggregateLocks[h.Sum32()%uint32(len(aggregateLocks))]
+}
+
+// reportAggregates creates the aggregate statuses of matrix and pipeline the job belongs to.
+// They are recomputed from the latest stored job of each member, so that reruns and resumed jobs are also reflected.
+func (d *duci) reportAggregates(ctx context.Context, buildJob *application.BuildJob) {
+	for _, aggregate := range buildJob.Aggregates {
+		d.reportAggregate(ctx, buildJob, aggregate)
+	}
+}
+
+// reportAggregate computes and creates the aggregate status while holding its lock.
+func (d *duci) reportAggregate(ctx context.Context, buildJob *application.BuildJob, aggregate job.Aggregate) {
+	trigger := buildJob.Trigger()
+	mu := aggregateLockOf(trigger, aggregate.Context)
+	mu.Lock()
+	defer mu.Unlock()
+
+	latest, err := d.latestJobsOf(trigger, aggregate.Members)
+	if err != nil {
+		logrus.Errorf("%+v", err)
+		return
+	}
+
+	status := github.CommitStatus{
+		TargetSource: buildJob.TargetSource,
+		Context:      aggregate.Context,
+	}
+	status.State, status.Description = summaryOf(aggregate.Members, latest)
+	if len(aggregate.TargetURL) > 0 {
+		targetURL, err := url.Parse(aggregate.TargetURL)
+		if err != nil {
+			logrus.Errorf("%+v", errors.WithStack(err))
+			return
+		}
+		status.TargetURL = targetURL
+	}
+	if err := d.github.CreateCommitStatus(ctx, status); err != nil {
+		logrus.Warn(err)
+	}
+}
+
+// latestJobsOf returns the latest stored job of each member for the commit, keyed by the task name.
+func (d *duci) latestJobsOf(trigger job.Trigger, members []string) (map[string]*job.Job, error) {
+	wanted := make(map[string]bool, len(members))
+	for _, member := range members {
+		wanted[member] = true
+	}
+
+	latest := make(map[string]*job.Job, len(members))
+	query := job.Query{Repository: trigger.Repository, SHA: trigger.SHA, Limit: 100}
+	for {
+		page, err := d.jobService.Search(query)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		// jobs are in descending order of queued time, so the first one is the latest
+		for i := range page.Jobs {
+			j := &page.Jobs[i]
+			if j.Trigger == nil || !wanted[j.Trigger.TaskName] || latest[j.Trigger.TaskName] != nil {
+				continue
+			}
+			latest[j.Trigger.TaskName] = j
+		}
+		if len(latest) == len(wanted) || len(page.Next) == 0 {
+			return latest, nil
+		}
+		query.Cursor = page.Next
+	}
+}
+
+// summaryOf returns a state and a description of the latest jobs of members.
+// It is pending until all members finish. Cancelled jobs are counted as errors rather than failures.
+func summaryOf(members []string, latest map[string]*job.Job) (github.State, github.Description) {
+	running, failed, errored, skipped := 0, 0, 0, 0
+	for _, member := range members {
+		j, ok := latest[member]
+		if !ok || !j.Finished {
+			running++
+			continue
+		}
+		switch j.State {
+		case job.SUCCESS:
+		case job.FAILURE, job.TIMEOUT:
+			failed++
+		case job.SKIPPED:
+			skipped++
+		default:
+			errored++
+		}
+	}
+
+	total := len(members)
+	switch {
+	case running > 0:
+		return github.PENDING, github.Description(fmt.Sprintf("running %d of %d jobs", running, total))
+	case failed > 0:
+		return github.FAILURE, github.Description(fmt.Sprintf("%d of %d jobs failed", failed, total))
+	case errored > 0:
+		return github.ERROR, github.Description(fmt.Sprintf("%d of %d jobs errored", errored, total))
+	case skipped > 0:
+		return github.ERROR, github.Description(fmt.Sprintf("%d of %d jobs skipped", skipped, total))
+	default:
+		return github.SUCCESS, github.Description(fmt.Sprintf("all %d jobs succeeded", total))
+	}
+}
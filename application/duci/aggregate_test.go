@@ -0,0 +1,245 @@
+package duci_test
+
+import (
+	"context"
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/application/duci"
+	"github.com/duck8823/duci/application/service/job/mock_job"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/domain/model/job/target/github"
+	"github.com/duck8823/duci/domain/model/job/target/github/mock_github"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDuci_End_WithAggregate(t *testing.T) {
+	// given
+	aggregate := job.Aggregate{
+		Context:   "duci/test",
+		TargetURL: "http://example.com/ui/?repository=duck8823%2Fduci",
+		Members:   []string{"duci/test (GO=1.12)", "duci/test (GO=1.13)"},
+	}
+	memberOf := func(name string, state job.State) job.Job {
+		return job.Job{
+			ID:       job.ID(uuid.New()),
+			Trigger:  &job.Trigger{TaskName: name},
+			State:    state,
+			Finished: state != job.QUEUED && state != job.RUNNING,
+		}
+	}
+
+	// where
+	for _, tt := range []struct {
+		name  string
+		pages []job.Page
+		want  github.CommitStatus
+	}{
+		{
+			name: "when other job is running",
+			pages: []job.Page{{Jobs: []job.Job{
+				memberOf("duci/test (GO=1.13)", job.RUNNING),
+				memberOf("duci/test (GO=1.12)", job.SUCCESS),
+			}}},
+			want: github.CommitStatus{State: github.PENDING, Description: "running 1 of 2 jobs"},
+		},
+		{
+			name: "when other job is not queued yet",
+			pages: []job.Page{{Jobs: []job.Job{
+				memberOf("duci/test (GO=1.12)", job.SUCCESS),
+			}}},
+			want: github.CommitStatus{State: github.PENDING, Description: "running 1 of 2 jobs"},
+		},
+		{
+			name: "when other job failed",
+			pages: []job.Page{{Jobs: []job.Job{
+				memberOf("duci/test (GO=1.13)", job.FAILURE),
+				memberOf("duci/test (GO=1.12)", job.SUCCESS),
+			}}},
+			want: github.CommitStatus{State: github.FAILURE, Description: "1 of 2 jobs failed"},
+		},
+		{
+			name: "when other job is cancelled",
+			pages: []job.Page{{Jobs: []job.Job{
+				memberOf("duci/test (GO=1.13)", job.CANCELLED),
+				memberOf("duci/test (GO=1.12)", job.SUCCESS),
+			}}},
+			want: github.CommitStatus{State: github.ERROR, Description: "1 of 2 jobs errored"},
+		},
+		{
+			name: "when failed job is rerun",
+			pages: []job.Page{{Jobs: []job.Job{
+				memberOf("duci/test (GO=1.13)", job.SUCCESS),
+				memberOf("duci/test (GO=1.13)", job.FAILURE),
+				memberOf("duci/test (GO=1.12)", job.SUCCESS),
+			}}},
+			want: github.CommitStatus{State: github.SUCCESS, Description: "all 2 jobs succeeded"},
+		},
+		{
+			name: "with jobs in next page",
+			pages: []job.Page{
+				{Jobs: []job.Job{memberOf("duci/test (GO=1.13)", job.SUCCESS)}, Next: "next"},
+				{Jobs: []job.Job{memberOf("duci/test (GO=1.12)", job.SUCCESS)}},
+			},
+			want: github.CommitStatus{State: github.SUCCESS, Description: "all 2 jobs succeeded"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			buildJob := &application.BuildJob{
+				ID: job.ID(uuid.New()),
+				TargetSource: &github.TargetSource{
+					Repository: &job.Source{FullName: "duck8823/duci"},
+					SHA:        plumbing.NewHash("aa218f56b14c9653891f9e74264a383fa43fefbd"),
+				},
+				TaskName:   "duci/test (GO=1.12)",
+				TargetURL:  duci.URLMust(url.Parse("http://example.com")),
+				Aggregates: []job.Aggregate{aggregate},
+			}
+			ctx := application.ContextWithJob(context.Background(), buildJob)
+
+			// and
+			defer duci.SetNowFunc(func() time.Time {
+				return time.Unix(0, 0)
+			})()
+
+			// and
+			want := tt.want
+			want.TargetSource = buildJob.TargetSource
+			want.Context = "duci/test"
+			want.TargetURL = duci.URLMust(url.Parse("http://example.com/ui/?repository=duck8823%2Fduci"))
+
+			// and
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mock_job_service.NewMockService(ctrl)
+			service.EXPECT().
+				Finish(gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(1).
+				Return(nil)
+			for i := range tt.pages {
+				cursor := ""
+				if i > 0 {
+					cursor = tt.pages[i-1].Next
+				}
+				service.EXPECT().
+					Search(gomock.Eq(job.Query{
+						Repository: "duck8823/duci",
+						SHA:        "aa218f56b14c9653891f9e74264a383fa43fefbd",
+						Cursor:     cursor,
+						Limit:      100,
+					})).
+					Times(1).
+					Return(&tt.pages[i], nil)
+			}
+
+			var got github.CommitStatus
+			hub := mock_github.NewMockGitHub(ctrl)
+			hub.EXPECT().
+				CreateCommitStatus(gomock.Any(), gomock.Any()).
+				Times(2).
+				Do(func(_ context.Context, status github.CommitStatus) {
+					if status.Context == "duci/test" {
+						got = status
+					}
+				}).
+				Return(nil)
+
+			// and
+			sut := &duci.Duci{}
+			defer sut.SetJobService(service)()
+			defer sut.SetGitHub(hub)()
+
+			// when
+			sut.End(ctx, nil)
+
+			// then
+			if !cmp.Equal(got, want) {
+				t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+			}
+		})
+	}
+}
+
+func TestDuci_End_WithAggregate_Concurrently(t *testing.T) {
+	// given
+	aggregate := job.Aggregate{
+		Context: "duci/test",
+		Members: []string{"duci/test (GO=1.12)", "duci/test (GO=1.13)"},
+	}
+	source := &job.Source{FullName: "duck8823/duci"}
+	sha := plumbing.NewHash("aa218f56b14c9653891f9e74264a383fa43fefbd")
+
+	// and
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var mu sync.Mutex
+	var events []string
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+
+	service := mock_job_service.NewMockService(ctrl)
+	service.EXPECT().
+		Finish(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(2).
+		Return(nil)
+	service.EXPECT().
+		Search(gomock.Any()).
+		Times(2).
+		DoAndReturn(func(job.Query) (*job.Page, error) {
+			record("search")
+			// gives the other job a chance to search before posting
+			time.Sleep(50 * time.Millisecond)
+			return &job.Page{}, nil
+		})
+
+	hub := mock_github.NewMockGitHub(ctrl)
+	hub.EXPECT().
+		CreateCommitStatus(gomock.Any(), gomock.Any()).
+		Times(4).
+		Do(func(_ context.Context, status github.CommitStatus) {
+			if status.Context == "duci/test" {
+				record("post")
+			}
+		}).
+		Return(nil)
+
+	// and
+	sut := &duci.Duci{}
+	defer sut.SetJobService(service)()
+	defer sut.SetGitHub(hub)()
+
+	// when
+	var wg sync.WaitGroup
+	for _, name := range aggregate.Members {
+		buildJob := &application.BuildJob{
+			ID:           job.ID(uuid.New()),
+			TargetSource: &github.TargetSource{Repository: source, SHA: sha},
+			TaskName:     name,
+			TargetURL:    duci.URLMust(url.Parse("http://example.com")),
+			Aggregates:   []job.Aggregate{aggregate},
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sut.End(application.ContextWithJob(context.Background(), buildJob), nil)
+		}()
+	}
+	wg.Wait()
+
+	// then
+	want := []string{"search", "post", "search", "post"}
+	if !cmp.Equal(events, want) {
+		t.Errorf("must be equal, but %+v", cmp.Diff(events, want))
+	}
+}
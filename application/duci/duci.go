@@ -6,6 +6,7 @@ import (
 	"github.com/duck8823/duci/application"
 	"github.com/duck8823/duci/application/service/executor"
 	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/domain/model/docker"
 	"github.com/duck8823/duci/domain/model/job"
 	"github.com/duck8823/duci/domain/model/job/target/github"
 	"github.com/duck8823/duci/domain/model/runner"
@@ -20,10 +21,19 @@ type duci struct {
 	executor.Executor
 	jobService jobService.Service
 	github     github.GitHub
+	docker     docker.Docker
 }
 
 // New returns duci instance
 func New() (executor.Executor, error) {
+	duci, err := newDuci()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return duci, nil
+}
+
+func newDuci() (*duci, error) {
 	jobService, err := jobService.GetInstance()
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -32,6 +42,10 @@ func New() (executor.Executor, error) {
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
+	docker, err := docker.New()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 	builder, err := executor.DefaultExecutorBuilder()
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -40,12 +54,15 @@ func New() (executor.Executor, error) {
 	duci := &duci{
 		jobService: jobService,
 		github:     github,
+		docker:     docker,
 	}
 	duci.Executor = builder.
 		InitFunc(duci.Init).
 		StartFunc(duci.Start).
 		EndFunc(duci.End).
 		LogFunc(duci.AppendLog).
+		Resources(application.Config.Job.Resources).
+		Limits(application.Config.Job.MaxResources).
 		Build()
 
 	return duci, nil
@@ -58,8 +75,8 @@ func (d *duci) Init(ctx context.Context) {
 		logrus.Errorf("%+v", err)
 		return
 	}
-	if err := d.jobService.Start(buildJob.ID); err != nil {
-		if err := d.jobService.Append(buildJob.ID, job.LogLine{Timestamp: now(), Message: err.Error()}); err != nil {
+	if err := d.jobService.Queue(buildJob.ID, buildJob.Trigger(), now()); err != nil {
+		if err := d.jobService.Append(buildJob.ID, job.LogLine{Timestamp: now(), Stream: job.SYSTEM, Message: err.Error()}); err != nil {
 			logrus.Errorf("%+v", err)
 		}
 		return
@@ -73,6 +90,7 @@ func (d *duci) Init(ctx context.Context) {
 	}); err != nil {
 		logrus.Warn(err)
 	}
+	d.reportAggregates(ctx, buildJob)
 }
 
 // Start represents a function of start job
@@ -83,6 +101,9 @@ func (d *duci) Start(ctx context.Context) {
 		return
 	}
 	buildJob.BeginAt(now())
+	if err := d.jobService.Start(buildJob.ID, now()); err != nil {
+		logrus.Errorf("%+v", err)
+	}
 	if err := d.github.CreateCommitStatus(ctx, github.CommitStatus{
 		TargetSource: buildJob.TargetSource,
 		State:        github.PENDING,
@@ -94,19 +115,15 @@ func (d *duci) Start(ctx context.Context) {
 	}
 }
 
-// AppendLog is a function that print and store log
+// AppendLog is a function that print and store log with secrets masked.
+// Lines over the log limit are read but not stored, so that the container is not blocked.
 func (d *duci) AppendLog(ctx context.Context, log job.Log) {
 	buildJob, err := application.BuildJobFromContext(ctx)
 	if err != nil {
 		logrus.Errorf("%+v", err)
 		return
 	}
-	for line, err := log.ReadLine(); err == nil; line, err = log.ReadLine() {
-		logrus.Info(line.Message)
-		if err := d.jobService.Append(buildJob.ID, *line); err != nil {
-			logrus.Errorf("%+v", err)
-		}
-	}
+	application.StoreLog(d.jobService, buildJob, log)
 }
 
 // End represents a function
@@ -116,15 +133,22 @@ func (d *duci) End(ctx context.Context, e error) {
 		logrus.Errorf("%+v", err)
 		return
 	}
+	if errors.Cause(e) == executor.ErrSuspended {
+		logrus.Infof("Job %s is left queued to be resumed by the next server", buildJob.ID)
+		return
+	}
+	if e == nil && application.Config.Job.FailOnLogLimit && buildJob.LogLimiter().Exceeded() {
+		e = errors.Wrap(runner.ErrFailure, "log size limit exceeded")
+	}
 	buildJob.EndAt(now())
-	if err := d.jobService.Finish(buildJob.ID); err != nil {
-		if err := d.jobService.Append(buildJob.ID, job.LogLine{Timestamp: now(), Message: err.Error()}); err != nil {
+	if err := d.jobService.Finish(buildJob.ID, result(e), now()); err != nil {
+		if err := d.jobService.Append(buildJob.ID, job.LogLine{Timestamp: now(), Stream: job.SYSTEM, Message: err.Error()}); err != nil {
 			logrus.Errorf("%+v", err)
 		}
 		return
 	}
 
-	switch e {
+	switch errors.Cause(e) {
 	case nil:
 		if err := d.github.CreateCommitStatus(ctx, github.CommitStatus{
 			TargetSource: buildJob.TargetSource,
@@ -145,6 +169,35 @@ func (d *duci) End(ctx context.Context, e error) {
 		}); err != nil {
 			logrus.Warn(err)
 		}
+	case context.Canceled:
+		description := github.Description("cancelled")
+		var superseded *executor.SupersededError
+		if errors.As(e, &superseded) {
+			description = github.Description(superseded.Error())
+		}
+		var failFast *executor.FailFastError
+		if errors.As(e, &failFast) {
+			description = github.Description(failFast.Error())
+		}
+		if err := d.github.CreateCommitStatus(ctx, github.CommitStatus{
+			TargetSource: buildJob.TargetSource,
+			State:        github.ERROR,
+			Description:  description,
+			Context:      buildJob.TaskName,
+			TargetURL:    buildJob.TargetURL,
+		}); err != nil {
+			logrus.Warn(err)
+		}
+	case executor.ErrSkipped:
+		if err := d.github.CreateCommitStatus(ctx, github.CommitStatus{
+			TargetSource: buildJob.TargetSource,
+			State:        github.ERROR,
+			Description:  github.Description(e.Error()),
+			Context:      buildJob.TaskName,
+			TargetURL:    buildJob.TargetURL,
+		}); err != nil {
+			logrus.Warn(err)
+		}
 	default:
 		if err := d.github.CreateCommitStatus(ctx, github.CommitStatus{
 			TargetSource: buildJob.TargetSource,
@@ -156,4 +209,29 @@ func (d *duci) End(ctx context.Context, e error) {
 			logrus.Warn(err)
 		}
 	}
+	d.reportAggregates(ctx, buildJob)
+}
+
+// result returns a result of job corresponding to the error
+func result(e error) job.Result {
+	switch errors.Cause(e) {
+	case nil:
+		code := int64(0)
+		return job.Result{State: job.SUCCESS, ExitCode: &code}
+	case runner.ErrFailure:
+		var fe *runner.FailureError
+		if errors.As(e, &fe) {
+			code := int64(fe.Code)
+			return job.Result{State: job.FAILURE, ExitCode: &code}
+		}
+		return job.Result{State: job.FAILURE}
+	case context.DeadlineExceeded:
+		return job.Result{State: job.TIMEOUT}
+	case context.Canceled:
+		return job.Result{State: job.CANCELLED}
+	case executor.ErrSkipped:
+		return job.Result{State: job.SKIPPED}
+	default:
+		return job.Result{State: job.ERROR}
+	}
 }
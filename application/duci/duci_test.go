@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/duck8823/duci/application"
 	"github.com/duck8823/duci/application/duci"
+	"github.com/duck8823/duci/application/service/executor"
 	jobService "github.com/duck8823/duci/application/service/job"
 	"github.com/duck8823/duci/application/service/job/mock_job"
 	"github.com/duck8823/duci/domain/model/job"
@@ -13,8 +14,10 @@ import (
 	"github.com/duck8823/duci/domain/model/runner"
 	"github.com/duck8823/duci/internal/container"
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 )
@@ -97,7 +100,7 @@ func TestDuci_Init(t *testing.T) {
 
 		service := mock_job_service.NewMockService(ctrl)
 		service.EXPECT().
-			Start(gomock.Eq(buildJob.ID)).
+			Queue(gomock.Eq(buildJob.ID), gomock.Eq(buildJob.Trigger()), gomock.Any()).
 			Times(1).
 			Return(nil)
 
@@ -134,7 +137,7 @@ func TestDuci_Init(t *testing.T) {
 
 		service := mock_job_service.NewMockService(ctrl)
 		service.EXPECT().
-			Start(gomock.Any()).
+			Queue(gomock.Any(), gomock.Any(), gomock.Any()).
 			Times(0)
 		service.EXPECT().
 			Append(gomock.Any(), gomock.Any()).
@@ -172,7 +175,7 @@ func TestDuci_Init(t *testing.T) {
 
 		service := mock_job_service.NewMockService(ctrl)
 		service.EXPECT().
-			Start(gomock.Any()).
+			Queue(gomock.Any(), gomock.Any(), gomock.Any()).
 			Times(1).
 			Return(errors.New("test error"))
 		service.EXPECT().
@@ -214,8 +217,9 @@ func TestDuci_Start(t *testing.T) {
 
 		service := mock_job_service.NewMockService(ctrl)
 		service.EXPECT().
-			Start(gomock.Eq(buildJob.ID)).
-			Times(0)
+			Start(gomock.Eq(buildJob.ID), gomock.Any()).
+			Times(1).
+			Return(nil)
 
 		hub := mock_github.NewMockGitHub(ctrl)
 		hub.EXPECT().
@@ -250,7 +254,7 @@ func TestDuci_Start(t *testing.T) {
 
 		service := mock_job_service.NewMockService(ctrl)
 		service.EXPECT().
-			Start(gomock.Any()).
+			Start(gomock.Any(), gomock.Any()).
 			Times(0)
 		service.EXPECT().
 			Append(gomock.Any(), gomock.Any()).
@@ -306,6 +310,102 @@ func TestDuci_AppendLog(t *testing.T) {
 		ctrl.Finish()
 	})
 
+	t.Run("with secrets", func(t *testing.T) {
+		// given
+		buildJob := &application.BuildJob{
+			ID:           job.ID(uuid.New()),
+			TargetSource: &github.TargetSource{},
+			TaskName:     "task/name",
+			TargetURL:    duci.URLMust(url.Parse("http://example.com")),
+		}
+		ctx := application.ContextWithJob(context.Background(), buildJob)
+
+		log := &duci.MockLog{Msgs: []string{"token is github_api_token"}}
+
+		// and
+		tmp := application.Config.GitHub.APIToken
+		application.Config.GitHub.APIToken = "github_api_token"
+		defer func() {
+			application.Config.GitHub.APIToken = tmp
+		}()
+
+		// and
+		ctrl := gomock.NewController(t)
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Append(gomock.Eq(buildJob.ID), gomock.Any()).
+			Times(1).
+			Do(func(_ job.ID, line job.LogLine) {
+				if line.Message != "token is ***" {
+					t.Errorf("message must be masked, but got %s", line.Message)
+				}
+			}).
+			Return(nil)
+
+		// and
+		sut := &duci.Duci{}
+		defer sut.SetJobService(service)()
+
+		// when
+		sut.AppendLog(ctx, log)
+
+		// then
+		ctrl.Finish()
+	})
+
+	t.Run("with log limits", func(t *testing.T) {
+		// given
+		buildJob := &application.BuildJob{
+			ID:           job.ID(uuid.New()),
+			TargetSource: &github.TargetSource{},
+			TaskName:     "task/name",
+			TargetURL:    duci.URLMust(url.Parse("http://example.com")),
+		}
+		ctx := application.ContextWithJob(context.Background(), buildJob)
+
+		log := &duci.MockLog{Msgs: []string{"Hello World", strings.Repeat("a", 600), strings.Repeat("b", 500), "never stored"}}
+
+		// and
+		tmp := *application.Config.Job
+		application.Config.Job.MaxLogSize = 1
+		application.Config.Job.MaxLineLength = 500
+		defer func() {
+			*application.Config.Job = tmp
+		}()
+
+		// and
+		var got []string
+		ctrl := gomock.NewController(t)
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Append(gomock.Eq(buildJob.ID), gomock.Any()).
+			Times(3).
+			Do(func(_ job.ID, line job.LogLine) {
+				got = append(got, line.Message)
+			}).
+			Return(nil)
+		service.EXPECT().
+			Truncate(gomock.Eq(buildJob.ID)).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := &duci.Duci{}
+		defer sut.SetJobService(service)()
+
+		// when
+		sut.AppendLog(ctx, log)
+
+		// then
+		ctrl.Finish()
+
+		// and
+		want := []string{"Hello World", strings.Repeat("a", 500) + job.LineTruncationMarker, "log truncated: exceeded 1024 bytes"}
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
 	t.Run("when invalid build job value", func(t *testing.T) {
 		// given
 		ctx := context.WithValue(context.Background(), duci.String("duci_job"), "invalid value")
@@ -362,7 +462,7 @@ func TestDuci_End(t *testing.T) {
 
 		service := mock_job_service.NewMockService(ctrl)
 		service.EXPECT().
-			Finish(gomock.Any()).
+			Finish(gomock.Any(), gomock.Eq(job.Result{State: job.SUCCESS, ExitCode: duci.Int64(0)}), gomock.Any()).
 			Times(1).
 			Return(nil)
 		hub := mock_github.NewMockGitHub(ctrl)
@@ -414,7 +514,305 @@ func TestDuci_End(t *testing.T) {
 
 		service := mock_job_service.NewMockService(ctrl)
 		service.EXPECT().
-			Finish(gomock.Any()).
+			Finish(gomock.Any(), gomock.Eq(job.Result{State: job.FAILURE}), gomock.Any()).
+			Times(1).
+			Return(nil)
+		hub := mock_github.NewMockGitHub(ctrl)
+		hub.EXPECT().
+			CreateCommitStatus(gomock.Eq(ctx), gomock.Eq(want)).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := &duci.Duci{}
+		defer sut.SetJobService(service)()
+		defer sut.SetGitHub(hub)()
+
+		// when
+		sut.End(ctx, err)
+
+		// then
+		ctrl.Finish()
+	})
+
+	t.Run("when log size limit exceeded with fail_on_log_limit", func(t *testing.T) {
+		// given
+		buildJob := &application.BuildJob{
+			ID:           job.ID(uuid.New()),
+			TargetSource: &github.TargetSource{},
+			TaskName:     "task/name",
+			TargetURL:    duci.URLMust(url.Parse("http://example.com")),
+		}
+		buildJob.BeginAt(time.Unix(0, 0))
+		ctx := application.ContextWithJob(context.Background(), buildJob)
+
+		// and
+		tmp := *application.Config.Job
+		application.Config.Job.MaxLogSize = 1
+		application.Config.Job.FailOnLogLimit = true
+		defer func() {
+			*application.Config.Job = tmp
+		}()
+
+		// and
+		buildJob.LogLimiter().Limit(job.LogLine{Message: strings.Repeat("a", 1025)})
+
+		// and
+		defer duci.SetNowFunc(func() time.Time {
+			return time.Unix(49, 1)
+		})()
+
+		// and
+		want := github.CommitStatus{
+			TargetSource: buildJob.TargetSource,
+			State:        github.FAILURE,
+			Description:  "failure in 49sec",
+			Context:      buildJob.TaskName,
+			TargetURL:    buildJob.TargetURL,
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Finish(gomock.Any(), gomock.Eq(job.Result{State: job.FAILURE}), gomock.Any()).
+			Times(1).
+			Return(nil)
+		hub := mock_github.NewMockGitHub(ctrl)
+		hub.EXPECT().
+			CreateCommitStatus(gomock.Eq(ctx), gomock.Eq(want)).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := &duci.Duci{}
+		defer sut.SetJobService(service)()
+		defer sut.SetGitHub(hub)()
+
+		// when
+		sut.End(ctx, nil)
+
+		// then
+		ctrl.Finish()
+	})
+
+	t.Run("when error is runner.FailureError", func(t *testing.T) {
+		// given
+		buildJob := &application.BuildJob{
+			ID:           job.ID(uuid.New()),
+			TargetSource: &github.TargetSource{},
+			TaskName:     "task/name",
+			TargetURL:    duci.URLMust(url.Parse("http://example.com")),
+		}
+		buildJob.BeginAt(time.Unix(0, 0))
+		ctx := application.ContextWithJob(context.Background(), buildJob)
+		err := &runner.FailureError{Code: 2}
+
+		// and
+		defer duci.SetNowFunc(func() time.Time {
+			return time.Unix(49, 1)
+		})()
+
+		// and
+		want := github.CommitStatus{
+			TargetSource: buildJob.TargetSource,
+			State:        github.FAILURE,
+			Description:  "failure in 49sec",
+			Context:      buildJob.TaskName,
+			TargetURL:    buildJob.TargetURL,
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Finish(gomock.Eq(buildJob.ID), gomock.Eq(job.Result{State: job.FAILURE, ExitCode: duci.Int64(2)}), gomock.Eq(time.Unix(49, 1))).
+			Times(1).
+			Return(nil)
+		hub := mock_github.NewMockGitHub(ctrl)
+		hub.EXPECT().
+			CreateCommitStatus(gomock.Eq(ctx), gomock.Eq(want)).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := &duci.Duci{}
+		defer sut.SetJobService(service)()
+		defer sut.SetGitHub(hub)()
+
+		// when
+		sut.End(ctx, err)
+
+		// then
+		ctrl.Finish()
+	})
+
+	t.Run("when error is timeout", func(t *testing.T) {
+		// given
+		buildJob := &application.BuildJob{
+			ID:           job.ID(uuid.New()),
+			TargetSource: &github.TargetSource{},
+			TaskName:     "task/name",
+			TargetURL:    duci.URLMust(url.Parse("http://example.com")),
+		}
+		ctx := application.ContextWithJob(context.Background(), buildJob)
+		err := context.DeadlineExceeded
+
+		// and
+		want := github.CommitStatus{
+			TargetSource: buildJob.TargetSource,
+			State:        github.ERROR,
+			Description:  github.Description("error: context deadline exceeded"),
+			Context:      buildJob.TaskName,
+			TargetURL:    buildJob.TargetURL,
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Finish(gomock.Any(), gomock.Eq(job.Result{State: job.TIMEOUT}), gomock.Any()).
+			Times(1).
+			Return(nil)
+		hub := mock_github.NewMockGitHub(ctrl)
+		hub.EXPECT().
+			CreateCommitStatus(gomock.Eq(ctx), gomock.Eq(want)).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := &duci.Duci{}
+		defer sut.SetJobService(service)()
+		defer sut.SetGitHub(hub)()
+
+		// when
+		sut.End(ctx, err)
+
+		// then
+		ctrl.Finish()
+	})
+
+	t.Run("when error is cancelled", func(t *testing.T) {
+		// given
+		buildJob := &application.BuildJob{
+			ID:           job.ID(uuid.New()),
+			TargetSource: &github.TargetSource{},
+			TaskName:     "task/name",
+			TargetURL:    duci.URLMust(url.Parse("http://example.com")),
+		}
+		ctx := application.ContextWithJob(context.Background(), buildJob)
+		err := context.Canceled
+
+		// and
+		want := github.CommitStatus{
+			TargetSource: buildJob.TargetSource,
+			State:        github.ERROR,
+			Description:  "cancelled",
+			Context:      buildJob.TaskName,
+			TargetURL:    buildJob.TargetURL,
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Finish(gomock.Any(), gomock.Eq(job.Result{State: job.CANCELLED}), gomock.Any()).
+			Times(1).
+			Return(nil)
+		hub := mock_github.NewMockGitHub(ctrl)
+		hub.EXPECT().
+			CreateCommitStatus(gomock.Eq(ctx), gomock.Eq(want)).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := &duci.Duci{}
+		defer sut.SetJobService(service)()
+		defer sut.SetGitHub(hub)()
+
+		// when
+		sut.End(ctx, err)
+
+		// then
+		ctrl.Finish()
+	})
+
+	t.Run("when error is superseded", func(t *testing.T) {
+		// given
+		buildJob := &application.BuildJob{
+			ID:           job.ID(uuid.New()),
+			TargetSource: &github.TargetSource{},
+			TaskName:     "task/name",
+			TargetURL:    duci.URLMust(url.Parse("http://example.com")),
+		}
+		ctx := application.ContextWithJob(context.Background(), buildJob)
+		err := &executor.SupersededError{SHA: "5e1f1d7"}
+
+		// and
+		want := github.CommitStatus{
+			TargetSource: buildJob.TargetSource,
+			State:        github.ERROR,
+			Description:  github.Description("superseded by 5e1f1d7"),
+			Context:      buildJob.TaskName,
+			TargetURL:    buildJob.TargetURL,
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Finish(gomock.Any(), gomock.Eq(job.Result{State: job.CANCELLED}), gomock.Any()).
+			Times(1).
+			Return(nil)
+		hub := mock_github.NewMockGitHub(ctrl)
+		hub.EXPECT().
+			CreateCommitStatus(gomock.Eq(ctx), gomock.Eq(want)).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := &duci.Duci{}
+		defer sut.SetJobService(service)()
+		defer sut.SetGitHub(hub)()
+
+		// when
+		sut.End(ctx, err)
+
+		// then
+		ctrl.Finish()
+	})
+
+	t.Run("when cancelled by failure of another job", func(t *testing.T) {
+		// given
+		buildJob := &application.BuildJob{
+			ID:           job.ID(uuid.New()),
+			TargetSource: &github.TargetSource{},
+			TaskName:     "duci/test (go=1.13)",
+			TargetURL:    duci.URLMust(url.Parse("http://example.com")),
+		}
+		ctx := application.ContextWithJob(context.Background(), buildJob)
+		err := &executor.FailFastError{TaskName: "duci/test (go=1.12)"}
+
+		// and
+		want := github.CommitStatus{
+			TargetSource: buildJob.TargetSource,
+			State:        github.ERROR,
+			Description:  github.Description("cancelled by failure of duci/test (go=1.12)"),
+			Context:      buildJob.TaskName,
+			TargetURL:    buildJob.TargetURL,
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Finish(gomock.Any(), gomock.Eq(job.Result{State: job.CANCELLED}), gomock.Any()).
 			Times(1).
 			Return(nil)
 		hub := mock_github.NewMockGitHub(ctrl)
@@ -435,6 +833,86 @@ func TestDuci_End(t *testing.T) {
 		ctrl.Finish()
 	})
 
+	t.Run("when skipped by failure of needed stage", func(t *testing.T) {
+		// given
+		buildJob := &application.BuildJob{
+			ID:           job.ID(uuid.New()),
+			TargetSource: &github.TargetSource{},
+			TaskName:     "duci/integration",
+			TargetURL:    duci.URLMust(url.Parse("http://example.com")),
+		}
+		ctx := application.ContextWithJob(context.Background(), buildJob)
+		err := &executor.SkippedError{Stage: "build"}
+
+		// and
+		want := github.CommitStatus{
+			TargetSource: buildJob.TargetSource,
+			State:        github.ERROR,
+			Description:  github.Description("skipped by failure of build"),
+			Context:      buildJob.TaskName,
+			TargetURL:    buildJob.TargetURL,
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Finish(gomock.Any(), gomock.Eq(job.Result{State: job.SKIPPED}), gomock.Any()).
+			Times(1).
+			Return(nil)
+		hub := mock_github.NewMockGitHub(ctrl)
+		hub.EXPECT().
+			CreateCommitStatus(gomock.Eq(ctx), gomock.Eq(want)).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := &duci.Duci{}
+		defer sut.SetJobService(service)()
+		defer sut.SetGitHub(hub)()
+
+		// when
+		sut.End(ctx, err)
+
+		// then
+		ctrl.Finish()
+	})
+
+	t.Run("when job is suspended", func(t *testing.T) {
+		// given
+		buildJob := &application.BuildJob{
+			ID:           job.ID(uuid.New()),
+			TargetSource: &github.TargetSource{},
+			TaskName:     "task/name",
+			TargetURL:    duci.URLMust(url.Parse("http://example.com")),
+		}
+		ctx := application.ContextWithJob(context.Background(), buildJob)
+
+		// and
+		ctrl := gomock.NewController(t)
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Finish(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+		hub := mock_github.NewMockGitHub(ctrl)
+		hub.EXPECT().
+			CreateCommitStatus(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &duci.Duci{}
+		defer sut.SetJobService(service)()
+		defer sut.SetGitHub(hub)()
+
+		// when
+		sut.End(ctx, executor.ErrSuspended)
+
+		// then
+		ctrl.Finish()
+	})
+
 	t.Run("when error is not nil", func(t *testing.T) {
 		// given
 		buildJob := &application.BuildJob{
@@ -460,7 +938,7 @@ func TestDuci_End(t *testing.T) {
 
 		service := mock_job_service.NewMockService(ctrl)
 		service.EXPECT().
-			Finish(gomock.Any()).
+			Finish(gomock.Any(), gomock.Eq(job.Result{State: job.ERROR}), gomock.Any()).
 			Times(1).
 			Return(nil)
 		hub := mock_github.NewMockGitHub(ctrl)
@@ -490,7 +968,7 @@ func TestDuci_End(t *testing.T) {
 
 		service := mock_job_service.NewMockService(ctrl)
 		service.EXPECT().
-			Finish(gomock.Any()).
+			Finish(gomock.Any(), gomock.Any(), gomock.Any()).
 			Times(0)
 		hub := mock_github.NewMockGitHub(ctrl)
 		hub.EXPECT().
@@ -524,7 +1002,7 @@ func TestDuci_End(t *testing.T) {
 
 		service := mock_job_service.NewMockService(ctrl)
 		service.EXPECT().
-			Finish(gomock.Any()).
+			Finish(gomock.Any(), gomock.Any(), gomock.Any()).
 			Times(1).
 			Return(errors.New("test error"))
 		service.EXPECT().
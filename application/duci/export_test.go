@@ -1,7 +1,9 @@
 package duci
 
 import (
+	"github.com/duck8823/duci/application/service/executor"
 	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/domain/model/docker"
 	"github.com/duck8823/duci/domain/model/job"
 	"github.com/duck8823/duci/domain/model/job/target/github"
 	"io"
@@ -51,6 +53,10 @@ func String(val string) *string {
 	return &val
 }
 
+func Int64(val int64) *int64 {
+	return &val
+}
+
 func SetNowFunc(f func() time.Time) (reset func()) {
 	tmp := now
 	now = f
@@ -58,3 +64,19 @@ func SetNowFunc(f func() time.Time) (reset func()) {
 		now = tmp
 	}
 }
+
+func (d *Duci) SetDocker(docker docker.Docker) (reset func()) {
+	tmp := d.docker
+	d.docker = docker
+	return func() {
+		d.docker = tmp
+	}
+}
+
+func (d *Duci) SetExecutor(executor executor.Executor) (reset func()) {
+	tmp := d.Executor
+	d.Executor = executor
+	return func() {
+		d.Executor = tmp
+	}
+}
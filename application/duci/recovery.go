@@ -0,0 +1,129 @@
+package duci
+
+import (
+	"context"
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/application/service/executor"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInterrupted represents a error of job interrupted by stopping server
+var ErrInterrupted = errors.New("interrupted by server restart")
+
+// Recover reconciles jobs interrupted by the previous server and resumes queued jobs
+func Recover(ctx context.Context) error {
+	duci, err := newDuci()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return duci.Recover(ctx)
+}
+
+// Recover marks running jobs as error and executes queued jobs again
+func (d *duci) Recover(ctx context.Context) error {
+	running, err := d.jobService.Search(job.Query{State: job.RUNNING})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for i := range running.Jobs {
+		d.reconcile(ctx, &running.Jobs[i])
+	}
+
+	queued, err := d.jobService.Search(job.Query{State: job.QUEUED})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	resumed := make(map[job.ID]chan struct{}, len(queued.Jobs))
+	for _, j := range queued.Jobs {
+		resumed[j.ID] = make(chan struct{})
+	}
+	// resume in the order of queued
+	for i := len(queued.Jobs) - 1; i >= 0; i-- {
+		d.resume(ctx, &queued.Jobs[i], resumed)
+	}
+	return nil
+}
+
+// reconcile removes containers and networks left by the job, and ends the job with error
+func (d *duci) reconcile(ctx context.Context, j *job.Job) {
+	logrus.Infof("Reconcile job %s interrupted by server restart", j.ID)
+
+	tag := executor.TagOf(j.ID)
+	if err := d.docker.RemoveContainers(ctx, tag); err != nil {
+		logrus.Warnf("Failed to remove containers of job %s: %+v", j.ID, err)
+	}
+	if err := d.docker.RemoveNetworks(ctx, tag); err != nil {
+		logrus.Warnf("Failed to remove networks of job %s: %+v", j.ID, err)
+	}
+	if err := d.docker.RemoveImage(ctx, tag); err != nil {
+		logrus.Debugf("Failed to remove image of job %s: %+v", j.ID, err)
+	}
+
+	buildJob, _, err := application.RestoreJob(j)
+	if err != nil {
+		if err := d.jobService.Finish(j.ID, result(ErrInterrupted), now()); err != nil {
+			logrus.Errorf("%+v", err)
+		}
+		return
+	}
+	d.End(application.ContextWithJob(ctx, buildJob), ErrInterrupted)
+}
+
+// resume executes the queued job again under the same id.
+// The job waits for resumed jobs it needs, and closes its channel in resumed when it ends.
+func (d *duci) resume(ctx context.Context, j *job.Job, resumed map[job.ID]chan struct{}) {
+	buildJob, tgt, err := application.RestoreJob(j)
+	if err != nil {
+		logrus.Warnf("Failed to resume job %s: %+v", j.ID, err)
+		d.reconcile(ctx, j)
+		close(resumed[j.ID])
+		return
+	}
+
+	var target job.Target = tgt
+	if len(buildJob.Needs) > 0 {
+		target = &executor.WaitingTarget{Target: tgt, Wait: d.awaitNeeds(buildJob.Needs, resumed)}
+	}
+
+	logrus.Infof("Resume job %s queued before server restart", j.ID)
+	go func() {
+		defer close(resumed[j.ID])
+		if err := d.Execute(application.ContextWithJob(ctx, buildJob), target, buildJob.Command...); err != nil {
+			logrus.Errorf("%+v", err)
+		}
+	}()
+}
+
+// awaitNeeds returns a function waiting for the jobs to end, if they are resumed.
+// The function returns ErrSuspended if any of them is left queued, or SkippedError if any of them did not succeed.
+func (d *duci) awaitNeeds(needs []job.ID, resumed map[job.ID]chan struct{}) func(context.Context) error {
+	return func(ctx context.Context) error {
+		for _, id := range needs {
+			if done, ok := resumed[id]; ok {
+				select {
+				case <-done:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			need, err := d.jobService.FindBy(id)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if !need.Finished {
+				return executor.ErrSuspended
+			}
+			if need.State != job.SUCCESS {
+				stage := id.String()
+				if need.Trigger != nil {
+					stage = need.Trigger.Task
+				}
+				return &executor.SkippedError{Stage: stage}
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,333 @@
+package duci_test
+
+import (
+	"context"
+	"errors"
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/application/duci"
+	"github.com/duck8823/duci/application/service/executor"
+	"github.com/duck8823/duci/application/service/executor/mock_executor"
+	"github.com/duck8823/duci/application/service/job/mock_job"
+	"github.com/duck8823/duci/domain/model/docker/mock_docker"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/domain/model/job/target/github"
+	"github.com/duck8823/duci/domain/model/job/target/github/mock_github"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDuci_Recover(t *testing.T) {
+	t.Run("with running and queued jobs", func(t *testing.T) {
+		// given
+		trigger := &job.Trigger{
+			Repository: "duck8823/duci",
+			Ref:        "refs/heads/master",
+			SHA:        "aa218f56b14c9653891f9e74264a383fa43fefbd",
+			Event:      "push",
+			TaskName:   "duci/push",
+			TargetURL:  "http://example.com/logs/hoge",
+			Source:     &job.Source{FullName: "duck8823/duci"},
+		}
+		running := job.Job{ID: job.ID(uuid.New()), Trigger: trigger, State: job.RUNNING}
+		queued := job.Job{ID: job.ID(uuid.New()), Trigger: trigger, State: job.QUEUED}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Search(gomock.Eq(job.Query{State: job.RUNNING})).
+			Times(1).
+			Return(&job.Page{Jobs: []job.Job{running}}, nil)
+		service.EXPECT().
+			Search(gomock.Eq(job.Query{State: job.QUEUED})).
+			Times(1).
+			Return(&job.Page{Jobs: []job.Job{queued}}, nil)
+		service.EXPECT().
+			Finish(gomock.Eq(running.ID), gomock.Eq(job.Result{State: job.ERROR}), gomock.Any()).
+			Times(1).
+			Return(nil)
+
+		hub := mock_github.NewMockGitHub(ctrl)
+		hub.EXPECT().
+			CreateCommitStatus(gomock.Any(), gomock.Any()).
+			Times(1).
+			Do(func(_ context.Context, status github.CommitStatus) {
+				if status.State != github.ERROR {
+					t.Errorf("state must be %s, but got %s", github.ERROR, status.State)
+				}
+				if status.Description != "error: interrupted by server restart" {
+					t.Errorf("description must be interrupted, but got %s", status.Description)
+				}
+				if status.Context != "duci/push" {
+					t.Errorf("context must be duci/push, but got %s", status.Context)
+				}
+			}).
+			Return(nil)
+
+		docker := mock_docker.NewMockDocker(ctrl)
+		gomock.InOrder(
+			docker.EXPECT().
+				RemoveContainers(gomock.Any(), gomock.Eq(executor.TagOf(running.ID))).
+				Times(1).
+				Return(nil),
+			docker.EXPECT().
+				RemoveNetworks(gomock.Any(), gomock.Eq(executor.TagOf(running.ID))).
+				Times(1).
+				Return(nil),
+		)
+		docker.EXPECT().
+			RemoveImage(gomock.Any(), gomock.Eq(executor.TagOf(running.ID))).
+			Times(1).
+			Return(nil)
+
+		resumed := make(chan job.ID, 1)
+		exec := mock_executor.NewMockExecutor(ctrl)
+		exec.EXPECT().
+			Execute(gomock.Any(), gomock.Any()).
+			Times(1).
+			Do(func(ctx context.Context, _ job.Target) {
+				buildJob, _ := application.BuildJobFromContext(ctx)
+				resumed <- buildJob.ID
+			}).
+			Return(nil)
+
+		// and
+		sut := &duci.Duci{}
+		defer sut.SetJobService(service)()
+		defer sut.SetGitHub(hub)()
+		defer sut.SetDocker(docker)()
+		defer sut.SetExecutor(exec)()
+
+		// when
+		err := sut.Recover(context.Background())
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		select {
+		case id := <-resumed:
+			if id != queued.ID {
+				t.Errorf("must resume %s, but got %s", queued.ID, id)
+			}
+		case <-time.After(3 * time.Second):
+			t.Error("queued job must be resumed")
+		}
+	})
+
+	t.Run("with jobs stored without source", func(t *testing.T) {
+		// given
+		running := job.Job{ID: job.ID(uuid.New()), State: job.RUNNING}
+		queued := job.Job{ID: job.ID(uuid.New()), State: job.QUEUED}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Search(gomock.Eq(job.Query{State: job.RUNNING})).
+			Times(1).
+			Return(&job.Page{Jobs: []job.Job{running}}, nil)
+		service.EXPECT().
+			Search(gomock.Eq(job.Query{State: job.QUEUED})).
+			Times(1).
+			Return(&job.Page{Jobs: []job.Job{queued}}, nil)
+		service.EXPECT().
+			Finish(gomock.Any(), gomock.Eq(job.Result{State: job.ERROR}), gomock.Any()).
+			Times(2).
+			Return(nil)
+
+		hub := mock_github.NewMockGitHub(ctrl)
+		hub.EXPECT().
+			CreateCommitStatus(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		docker := mock_docker.NewMockDocker(ctrl)
+		docker.EXPECT().
+			RemoveContainers(gomock.Any(), gomock.Any()).
+			Times(2).
+			Return(nil)
+		docker.EXPECT().
+			RemoveNetworks(gomock.Any(), gomock.Any()).
+			Times(2).
+			Return(nil)
+		docker.EXPECT().
+			RemoveImage(gomock.Any(), gomock.Any()).
+			Times(2).
+			Return(errors.New("no such image"))
+
+		exec := mock_executor.NewMockExecutor(ctrl)
+		exec.EXPECT().
+			Execute(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &duci.Duci{}
+		defer sut.SetJobService(service)()
+		defer sut.SetGitHub(hub)()
+		defer sut.SetDocker(docker)()
+		defer sut.SetExecutor(exec)()
+
+		// when
+		err := sut.Recover(context.Background())
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	// where
+	for _, tt := range []struct {
+		name    string
+		resumed bool
+		need    job.Job
+		want    error
+	}{
+		{
+			name:    "with queued job needing resumed job succeeded",
+			resumed: true,
+			need:    job.Job{State: job.SUCCESS, Finished: true},
+			want:    nil,
+		},
+		{
+			name:    "with queued job needing resumed job suspended again",
+			resumed: true,
+			need:    job.Job{State: job.QUEUED},
+			want:    executor.ErrSuspended,
+		},
+		{
+			name: "with queued job needing failed job",
+			need: job.Job{State: job.FAILURE, Finished: true},
+			want: &executor.SkippedError{Stage: "build"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			trigger := &job.Trigger{
+				Repository: "duck8823/duci",
+				SHA:        "aa218f56b14c9653891f9e74264a383fa43fefbd",
+				Source:     &job.Source{FullName: "duck8823/duci"},
+			}
+			need := tt.need
+			need.ID = job.ID(uuid.New())
+			need.Trigger = &job.Trigger{Task: "build", TaskName: "duci/build", Source: trigger.Source}
+			dependent := job.Job{ID: job.ID(uuid.New()), State: job.QUEUED, Trigger: &job.Trigger{
+				Repository: trigger.Repository,
+				SHA:        trigger.SHA,
+				Source:     trigger.Source,
+				Task:       "test",
+				TaskName:   "duci/test",
+				Needs:      []string{need.ID.String()},
+			}}
+
+			// and
+			queued := []job.Job{dependent}
+			if tt.resumed {
+				queued = append(queued, job.Job{ID: need.ID, Trigger: need.Trigger, State: job.QUEUED})
+			}
+
+			// and
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mock_job_service.NewMockService(ctrl)
+			service.EXPECT().
+				Search(gomock.Eq(job.Query{State: job.RUNNING})).
+				Times(1).
+				Return(&job.Page{}, nil)
+			service.EXPECT().
+				Search(gomock.Eq(job.Query{State: job.QUEUED})).
+				Times(1).
+				Return(&job.Page{Jobs: queued}, nil)
+			service.EXPECT().
+				FindBy(gomock.Eq(need.ID)).
+				Times(1).
+				Return(&need, nil)
+
+			// and
+			needEnded := make(chan struct{})
+			waited := make(chan error, 1)
+			exec := mock_executor.NewMockExecutor(ctrl)
+			exec.EXPECT().
+				Execute(gomock.Any(), gomock.Any()).
+				AnyTimes().
+				DoAndReturn(func(ctx context.Context, target job.Target, _ ...string) error {
+					buildJob, _ := application.BuildJobFromContext(ctx)
+					if buildJob.ID == need.ID {
+						close(needEnded)
+						return nil
+					}
+					waiting, ok := target.(*executor.WaitingTarget)
+					if !ok {
+						t.Fatalf("target must wait for jobs it needs, but got %T", target)
+					}
+					err := waiting.Wait(ctx)
+					if tt.resumed {
+						select {
+						case <-needEnded:
+						default:
+							t.Error("must wait for the resumed job it needs")
+						}
+					}
+					waited <- err
+					return err
+				})
+
+			// and
+			sut := &duci.Duci{}
+			defer sut.SetJobService(service)()
+			defer sut.SetExecutor(exec)()
+
+			// when
+			err := sut.Recover(context.Background())
+
+			// then
+			if err != nil {
+				t.Errorf("error must be nil, but got %+v", err)
+			}
+
+			// and
+			select {
+			case got := <-waited:
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("must be equal, but got %+v, want %+v", got, tt.want)
+				}
+			case <-time.After(3 * time.Second):
+				t.Error("queued job must be resumed")
+			}
+		})
+	}
+
+	t.Run("when failed to search jobs", func(t *testing.T) {
+		// given
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Search(gomock.Any()).
+			Times(1).
+			Return(nil, errors.New("test error"))
+
+		// and
+		sut := &duci.Duci{}
+		defer sut.SetJobService(service)()
+
+		// when
+		err := sut.Recover(context.Background())
+
+		// then
+		if err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+}
@@ -5,7 +5,9 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"github.com/duck8823/duci/domain/model/job"
 	"github.com/tcnksm/go-latest"
+	"io"
 	"os"
 	"testing"
 	"time"
@@ -13,6 +15,21 @@ import (
 
 type MaskString = maskString
 
+var AppendLog = appendLog
+
+type MockLog struct {
+	Msgs []string
+}
+
+func (l *MockLog) ReadLine() (*job.LogLine, error) {
+	if len(l.Msgs) == 0 {
+		return nil, io.EOF
+	}
+	msg := l.Msgs[0]
+	l.Msgs = l.Msgs[1:]
+	return &job.LogLine{Timestamp: time.Now(), Message: msg}, nil
+}
+
 func SetVersion(ver string) (reset func()) {
 	tmp := version
 	version = ver
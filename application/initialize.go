@@ -12,18 +12,50 @@ import (
 
 // Initialize singleton instances that are needed by application
 func Initialize() error {
-	switch {
-	case len(Config.GitHub.SSHKeyPath) == 0:
-		if err := git.InitializeWithHTTP(Config.GitHub.APIToken.String(), appendLog); err != nil {
+	defaultGit, err := newGit(Config.GitHub.SSHKeyPath, Config.GitHub.APIToken.String())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defaultGitHub := github.New(Config.GitHub.APIToken.String())
+
+	gits := make(map[string]git.Git)
+	githubs := make(map[string]github.GitHub)
+	for pattern, override := range Config.Repositories {
+		if override == nil || (len(override.SSHKeyPath) == 0 && len(override.APIToken) == 0) {
+			continue
+		}
+		repo, err := Config.Repository(pattern)
+		if err != nil {
 			return errors.WithStack(err)
 		}
-	default:
-		if err := git.InitializeWithSSH(Config.GitHub.SSHKeyPath, appendLog); err != nil {
+
+		if gits[pattern], err = newGit(repo.SSHKeyPath, repo.APIToken.String()); err != nil {
 			return errors.WithStack(err)
 		}
+		if len(override.APIToken) > 0 {
+			githubs[pattern] = github.New(repo.APIToken.String())
+		}
+	}
+
+	if err := git.InitializeWithRouter(defaultGit, func(fullName string) (git.Git, bool) {
+		pattern, ok := Config.Repositories.Match(fullName)
+		if !ok {
+			return nil, false
+		}
+		git, ok := gits[pattern]
+		return git, ok
+	}); err != nil {
+		return errors.WithStack(err)
 	}
 
-	if err := github.Initialize(Config.GitHub.APIToken.String()); err != nil {
+	if err := github.InitializeWithRouter(defaultGitHub, func(fullName string) (github.GitHub, bool) {
+		pattern, ok := Config.Repositories.Match(fullName)
+		if !ok {
+			return nil, false
+		}
+		github, ok := githubs[pattern]
+		return github, ok
+	}); err != nil {
 		return errors.WithStack(err)
 	}
 
@@ -33,6 +65,13 @@ func Initialize() error {
 	return nil
 }
 
+func newGit(sshKeyPath string, token string) (git.Git, error) {
+	if len(sshKeyPath) == 0 {
+		return git.NewWithHTTP(token, appendLog), nil
+	}
+	return git.NewWithSSH(sshKeyPath, appendLog)
+}
+
 func printLog(_ context.Context, log job.Log) {
 	for line, err := log.ReadLine(); err == nil; line, err = log.ReadLine() {
 		logrus.Info(line.Message)
@@ -43,7 +82,7 @@ func printLog(_ context.Context, log job.Log) {
 func appendLog(ctx context.Context, log job.Log) {
 	s, err := jobService.GetInstance()
 	if err != nil {
-		printLog(ctx, log)
+		printLog(ctx, job.NewMasker(Config.Secrets()...).Log(log))
 		return
 	}
 
@@ -52,10 +91,27 @@ func appendLog(ctx context.Context, log job.Log) {
 		logrus.Errorf("%+v", err)
 		return
 	}
+	StoreLog(s, buildJob, log)
+}
+
+// StoreLog prints and stores lines of the job with secrets masked, within the limits of log shared by all steps of the job
+func StoreLog(s jobService.Service, buildJob *BuildJob, log job.Log) {
+	log = job.NewMasker(Config.Secrets()...).Log(log)
+	limiter := buildJob.LogLimiter()
 	for line, err := log.ReadLine(); err == nil; line, err = log.ReadLine() {
-		logrus.Info(line.Message)
-		if err := s.Append(buildJob.ID, *line); err != nil {
+		truncated := limiter.Truncated()
+		limited, ok := limiter.Limit(*line)
+		if !ok {
+			continue
+		}
+		logrus.Info(limited.Message)
+		if err := s.Append(buildJob.ID, limited); err != nil {
 			logrus.Errorf("%+v", err)
 		}
+		if !truncated && limiter.Truncated() {
+			if err := s.Truncate(buildJob.ID); err != nil {
+				logrus.Errorf("%+v", err)
+			}
+		}
 	}
 }
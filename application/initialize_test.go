@@ -1,14 +1,21 @@
 package application_test
 
 import (
+	"context"
 	"github.com/duck8823/duci/application"
 	"github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/application/service/job/mock_job"
+	jobModel "github.com/duck8823/duci/domain/model/job"
 	"github.com/duck8823/duci/domain/model/job/target/git"
 	"github.com/duck8823/duci/domain/model/job/target/github"
 	"github.com/duck8823/duci/internal/container"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 	"github.com/labstack/gommon/random"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -200,3 +207,87 @@ func TestInitialize(t *testing.T) {
 		}
 	})
 }
+
+func TestAppendLog(t *testing.T) {
+	t.Run("with secrets", func(t *testing.T) {
+		// given
+		buildJob := &application.BuildJob{ID: jobModel.ID(uuid.New())}
+		ctx := application.ContextWithJob(context.Background(), buildJob)
+
+		log := &application.MockLog{Msgs: []string{"token is github_api_token"}}
+
+		// and
+		tmp := application.Config.GitHub.APIToken
+		application.Config.GitHub.APIToken = "github_api_token"
+		defer func() {
+			application.Config.GitHub.APIToken = tmp
+		}()
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Append(gomock.Eq(buildJob.ID), gomock.Any()).
+			Times(1).
+			Do(func(_ jobModel.ID, line jobModel.LogLine) {
+				if line.Message != "token is ***" {
+					t.Errorf("message must be masked, but got %s", line.Message)
+				}
+			}).
+			Return(nil)
+		container.Override(service)
+		defer container.Clear()
+
+		// expect
+		application.AppendLog(ctx, log)
+	})
+
+	t.Run("with log limits shared with other steps of the job", func(t *testing.T) {
+		// given
+		tmp := *application.Config.Job
+		application.Config.Job.MaxLogSize = 1
+		defer func() {
+			*application.Config.Job = tmp
+		}()
+
+		// and
+		buildJob := &application.BuildJob{ID: jobModel.ID(uuid.New())}
+		ctx := application.ContextWithJob(context.Background(), buildJob)
+		if _, ok := buildJob.LogLimiter().Limit(jobModel.LogLine{Message: strings.Repeat("a", 1020)}); !ok {
+			t.Fatal("line must be within the limit")
+		}
+
+		log := &application.MockLog{Msgs: []string{"Hello World", "never stored"}}
+
+		// and
+		var got []string
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Append(gomock.Eq(buildJob.ID), gomock.Any()).
+			Times(1).
+			Do(func(_ jobModel.ID, line jobModel.LogLine) {
+				got = append(got, line.Message)
+			}).
+			Return(nil)
+		service.EXPECT().
+			Truncate(gomock.Eq(buildJob.ID)).
+			Times(1).
+			Return(nil)
+		container.Override(service)
+		defer container.Clear()
+
+		// when
+		application.AppendLog(ctx, log)
+
+		// then
+		want := []string{"log truncated: exceeded 1024 bytes"}
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package application
+
+import (
+	"context"
+	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// Janitor removes jobs exceeding the retention at every interval until the context is done.
+// It does nothing if no limit is configured.
+func Janitor(ctx context.Context) {
+	retention := Config.RetentionPolicy()
+	if retention.IsZero() || Config.GCInterval() <= 0 {
+		return
+	}
+
+	service, err := jobService.GetInstance()
+	if err != nil {
+		logrus.Errorf("Failed to start janitor.\n%+v", err)
+		return
+	}
+
+	ticker := time.NewTicker(Config.GCInterval())
+	defer ticker.Stop()
+
+	for {
+		garbage, err := service.Collect(retention, time.Now(), false)
+		if err != nil {
+			logrus.Errorf("Failed to collect garbage.\n%+v", err)
+		} else if len(garbage) > 0 {
+			logrus.Infof("Removed %d jobs by retention.", len(garbage))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package application_test
+
+import (
+	"context"
+	"github.com/duck8823/duci/application"
+	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/application/service/job/mock_job"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/internal/container"
+	"github.com/golang/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestJanitor(t *testing.T) {
+	t.Run("with retention", func(t *testing.T) {
+		// given
+		tmp := application.Config.Retention
+		application.Config.Retention = &application.Retention{MaxJobs: 10, Interval: 60}
+		defer func() {
+			application.Config.Retention = tmp
+		}()
+
+		// and
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Collect(gomock.Eq(job.Retention{MaxJobs: 10}), gomock.Any(), gomock.Eq(false)).
+			Times(1).
+			Do(func(_ job.Retention, _ time.Time, _ bool) {
+				cancel()
+			}).
+			Return([]job.Garbage{{}}, nil)
+
+		// and
+		var ins jobService.Service = service
+		container.Override(&ins)
+		defer container.Clear()
+
+		// when
+		done := make(chan struct{})
+		go func() {
+			application.Janitor(ctx)
+			close(done)
+		}()
+
+		// then
+		select {
+		case <-done:
+		case <-time.After(3 * time.Second):
+			t.Error("janitor must stop when context is done")
+		}
+	})
+
+	t.Run("without retention", func(t *testing.T) {
+		// given
+		tmp := application.Config.Retention
+		application.Config.Retention = &application.Retention{Interval: 60}
+		defer func() {
+			application.Config.Retention = tmp
+		}()
+
+		// and
+		container.Clear()
+
+		// when
+		done := make(chan struct{})
+		go func() {
+			application.Janitor(context.Background())
+			close(done)
+		}()
+
+		// then
+		select {
+		case <-done:
+		case <-time.After(3 * time.Second):
+			t.Error("janitor must return immediately")
+		}
+	})
+}
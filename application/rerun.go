@@ -0,0 +1,93 @@
+package application
+
+import (
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/domain/model/job/target"
+	"github.com/duck8823/duci/domain/model/job/target/github"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"net/url"
+	"time"
+)
+
+// ErrNotRerunnable represents a error of job not finished or stored without enough metadata
+var ErrNotRerunnable = errors.New("job is not rerunnable")
+
+// RerunJob returns a BuildJob and target to rebuild the same source and command as the original job.
+// The rerun does not wait for jobs the original needed.
+func RerunJob(id job.ID, original *job.Job, targetURL *url.URL) (*BuildJob, job.Target, error) {
+	if !original.IsRerunnable() {
+		return nil, nil, ErrNotRerunnable
+	}
+
+	buildJob, tgt := restore(id, original.Trigger)
+	if original.QueuedAt != nil {
+		buildJob.QueuedAt = *original.QueuedAt
+	}
+	rerunOf := original.ID
+	buildJob.TargetURL = targetURL
+	buildJob.RerunOf = &rerunOf
+	return buildJob, tgt, nil
+}
+
+// RestoreJob returns a BuildJob and target to build the stored job again under the same id, which needs the same jobs
+func RestoreJob(stored *job.Job) (*BuildJob, job.Target, error) {
+	if stored.Trigger == nil || stored.Trigger.Source == nil {
+		return nil, nil, ErrNotRerunnable
+	}
+
+	buildJob, tgt := restore(stored.ID, stored.Trigger)
+	if stored.QueuedAt != nil {
+		buildJob.QueuedAt = *stored.QueuedAt
+	}
+	if len(stored.Trigger.TargetURL) > 0 {
+		targetURL, err := url.Parse(stored.Trigger.TargetURL)
+		if err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		buildJob.TargetURL = targetURL
+	}
+	if len(stored.Trigger.RerunOf) > 0 {
+		rerunOf, err := uuid.Parse(stored.Trigger.RerunOf)
+		if err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		buildJob.RerunOf = (*job.ID)(&rerunOf)
+	}
+	for _, need := range stored.Trigger.Needs {
+		id, err := uuid.Parse(need)
+		if err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		buildJob.Needs = append(buildJob.Needs, job.ID(id))
+	}
+	return buildJob, tgt, nil
+}
+
+func restore(id job.ID, trigger *job.Trigger) (*BuildJob, *target.GitHub) {
+	buildJob := &BuildJob{
+		ID: id,
+		TargetSource: &github.TargetSource{
+			Repository: &job.Source{FullName: trigger.Repository},
+			Ref:        trigger.Ref,
+			SHA:        plumbing.NewHash(trigger.SHA),
+		},
+		TaskName:   trigger.TaskName,
+		Event:      trigger.Event,
+		Source:     trigger.Source,
+		Command:    trigger.Command,
+		Task:       trigger.Task,
+		Timeout:    time.Duration(trigger.Timeout) * time.Second,
+		Matrix:     trigger.Matrix,
+		Aggregates: trigger.Aggregates,
+	}
+	tgt := &target.GitHub{
+		Repo: trigger.Source,
+		Point: &github.SimpleTargetPoint{
+			Ref: trigger.Ref,
+			SHA: trigger.SHA,
+		},
+	}
+	return buildJob, tgt
+}
@@ -0,0 +1,180 @@
+package application_test
+
+import (
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/domain/model/job/target"
+	"github.com/duck8823/duci/domain/model/job/target/github"
+	"github.com/duck8823/duci/domain/model/task"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRerunJob(t *testing.T) {
+	t.Run("when original job is rerunnable", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+		targetURL := &url.URL{Scheme: "http", Host: "example.com", Path: "/logs/hoge"}
+
+		// and
+		source := &job.Source{FullName: "octocat/duci", CloneURL: "https://github.com/octocat/duci.git"}
+		queuedAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		original := &job.Job{
+			ID:       job.ID(uuid.New()),
+			QueuedAt: &queuedAt,
+			Trigger: &job.Trigger{
+				Repository: "duck8823/duci",
+				Ref:        "refs/heads/feature",
+				SHA:        "aa218f56b14c9653891f9e74264a383fa43fefbd",
+				Event:      "issue_comment",
+				TaskName:   "duci/pr/test",
+				Source:     source,
+				Command:    []string{"test"},
+				Needs:      []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+			},
+			Finished: true,
+		}
+
+		// and
+		wantJob := &application.BuildJob{
+			ID: id,
+			TargetSource: &github.TargetSource{
+				Repository: &job.Source{FullName: "duck8823/duci"},
+				Ref:        "refs/heads/feature",
+				SHA:        plumbing.NewHash("aa218f56b14c9653891f9e74264a383fa43fefbd"),
+			},
+			TaskName:  "duci/pr/test",
+			TargetURL: targetURL,
+			Event:     "issue_comment",
+			Source:    source,
+			Command:   []string{"test"},
+			RerunOf:   &original.ID,
+			QueuedAt:  queuedAt,
+		}
+		wantTarget := &target.GitHub{
+			Repo: source,
+			Point: &github.SimpleTargetPoint{
+				Ref: "refs/heads/feature",
+				SHA: "aa218f56b14c9653891f9e74264a383fa43fefbd",
+			},
+		}
+
+		// when
+		gotJob, gotTarget, err := application.RerunJob(id, original, targetURL)
+
+		// then
+		if err != nil {
+			t.Errorf("error must not occur, but got %+v", err)
+		}
+
+		opt := cmp.AllowUnexported(application.BuildJob{})
+		if !cmp.Equal(gotJob, wantJob, opt) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(gotJob, wantJob, opt))
+		}
+
+		if !cmp.Equal(gotTarget, wantTarget) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(gotTarget, wantTarget))
+		}
+	})
+
+	t.Run("when original job is not rerunnable", func(t *testing.T) {
+		// given
+		original := &job.Job{ID: job.ID(uuid.New()), Finished: true}
+
+		// when
+		gotJob, gotTarget, err := application.RerunJob(job.ID(uuid.New()), original, &url.URL{})
+
+		// then
+		if err != application.ErrNotRerunnable {
+			t.Errorf("error must be %+v, but got %+v", application.ErrNotRerunnable, err)
+		}
+
+		if gotJob != nil || gotTarget != nil {
+			t.Errorf("must be nil, but got %+v and %+v", gotJob, gotTarget)
+		}
+	})
+}
+
+func TestRestoreJob(t *testing.T) {
+	t.Run("when stored job has source", func(t *testing.T) {
+		// given
+		rerunOf := job.ID(uuid.New())
+		stored := &job.Job{
+			ID: job.ID(uuid.New()),
+			Trigger: &job.Trigger{
+				Repository: "duck8823/duci",
+				Ref:        "refs/heads/master",
+				SHA:        "aa218f56b14c9653891f9e74264a383fa43fefbd",
+				Event:      "push",
+				TaskName:   "duci/push",
+				TargetURL:  "http://example.com/logs/hoge",
+				Source:     &job.Source{FullName: "duck8823/duci"},
+				RerunOf:    rerunOf.String(),
+				Task:       "test",
+				Timeout:    60,
+				Matrix:     map[string]string{"GO": "1.13"},
+				Aggregates: []job.Aggregate{{Context: "duci/test", Members: []string{"duci/test (GO=1.13)"}}},
+				Needs:      []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+			},
+			State: job.QUEUED,
+		}
+
+		// and
+		want := &application.BuildJob{
+			ID: stored.ID,
+			TargetSource: &github.TargetSource{
+				Repository: &job.Source{FullName: "duck8823/duci"},
+				Ref:        "refs/heads/master",
+				SHA:        plumbing.NewHash("aa218f56b14c9653891f9e74264a383fa43fefbd"),
+			},
+			TaskName:   "duci/push",
+			TargetURL:  &url.URL{Scheme: "http", Host: "example.com", Path: "/logs/hoge"},
+			Event:      "push",
+			Source:     &job.Source{FullName: "duck8823/duci"},
+			RerunOf:    &rerunOf,
+			Task:       "test",
+			Timeout:    60 * time.Second,
+			Matrix:     task.Combination{"GO": "1.13"},
+			Aggregates: []job.Aggregate{{Context: "duci/test", Members: []string{"duci/test (GO=1.13)"}}},
+			Needs:      []job.ID{job.ID(uuid.MustParse("72d3162e-cc78-11e3-81ab-4c9367dc0958"))},
+		}
+
+		// when
+		got, tgt, err := application.RestoreJob(stored)
+
+		// then
+		if err != nil {
+			t.Errorf("error must not occur, but got %+v", err)
+		}
+
+		opt := cmp.AllowUnexported(application.BuildJob{})
+		if !cmp.Equal(got, want, opt) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want, opt))
+		}
+
+		if tgt == nil {
+			t.Error("target must not be nil")
+		}
+	})
+
+	t.Run("when stored job has no source", func(t *testing.T) {
+		// given
+		stored := &job.Job{ID: job.ID(uuid.New()), Trigger: &job.Trigger{Repository: "duck8823/duci"}}
+
+		// when
+		got, tgt, err := application.RestoreJob(stored)
+
+		// then
+		if err != application.ErrNotRerunnable {
+			t.Errorf("error must be %+v, but got %+v", application.ErrNotRerunnable, err)
+		}
+
+		if got != nil || tgt != nil {
+			t.Errorf("must be nil, but got %+v and %+v", got, tgt)
+		}
+	})
+}
@@ -1,14 +1,19 @@
 package semaphore
 
 import (
+	"context"
 	"github.com/duck8823/duci/application"
 	"github.com/pkg/errors"
 	"runtime"
+	"sync"
 )
 
 var (
 	sem         = make(chan struct{}, runtime.NumCPU()) // default concurrency
 	initialized = false
+
+	keyed = make(map[string]chan struct{})
+	mu    sync.Mutex
 )
 
 // Make create semaphore with configuration
@@ -21,12 +26,53 @@ func Make() error {
 	return nil
 }
 
-// Acquire is a function to acquire and block permit
-func Acquire() {
-	sem <- struct{}{}
+// Acquire is a function to acquire and block permit until the context is done
+func Acquire(ctx context.Context) error {
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // Release is a function to release permit
 func Release() {
 	<-sem
 }
+
+// AcquireFor is a function to acquire and block permit for the key limited by concurrency.
+// Zero or negative concurrency means no limit for the key.
+func AcquireFor(ctx context.Context, key string, concurrency int) error {
+	if concurrency <= 0 {
+		return nil
+	}
+
+	mu.Lock()
+	ch, ok := keyed[key]
+	if !ok {
+		ch = make(chan struct{}, concurrency)
+		keyed[key] = ch
+	}
+	mu.Unlock()
+
+	select {
+	case ch <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ReleaseFor is a function to release permit for the key
+func ReleaseFor(key string, concurrency int) {
+	if concurrency <= 0 {
+		return
+	}
+
+	mu.Lock()
+	ch := keyed[key]
+	mu.Unlock()
+
+	<-ch
+}
@@ -28,7 +28,9 @@ func TestSemaphore(t *testing.T) {
 
 	// when
 	go func() {
-		semaphore.Acquire()
+		if err := semaphore.Acquire(context.Background()); err != nil {
+			t.Errorf("error must not occur, but got %+v", err)
+		}
 		semaphore.Release()
 		end <- struct{}{}
 	}()
@@ -41,3 +43,90 @@ func TestSemaphore(t *testing.T) {
 		// nothing to do
 	}
 }
+
+func TestAcquireFor(t *testing.T) {
+	t.Run("when concurrency is limited", func(t *testing.T) {
+		// given
+		key := "duck8823/duci"
+		_ = semaphore.AcquireFor(context.Background(), key, 1)
+
+		// and
+		acquired := make(chan struct{}, 1)
+
+		// when
+		go func() {
+			_ = semaphore.AcquireFor(context.Background(), key, 1)
+			acquired <- struct{}{}
+			semaphore.ReleaseFor(key, 1)
+		}()
+
+		// then
+		select {
+		case <-acquired:
+			t.Error("must not acquire permit before release")
+		case <-time.After(100 * time.Millisecond):
+			// nothing to do
+		}
+
+		// when
+		semaphore.ReleaseFor(key, 1)
+
+		// then
+		select {
+		case <-acquired:
+			// nothing to do
+		case <-time.After(3 * time.Second):
+			t.Error("must acquire permit after release")
+		}
+	})
+
+	t.Run("when concurrency is not limited", func(t *testing.T) {
+		// given
+		end := make(chan struct{}, 1)
+
+		// when
+		go func() {
+			_ = semaphore.AcquireFor(context.Background(), "duck8823/duci", 0)
+			_ = semaphore.AcquireFor(context.Background(), "duck8823/duci", 0)
+			semaphore.ReleaseFor("duck8823/duci", 0)
+			semaphore.ReleaseFor("duck8823/duci", 0)
+			end <- struct{}{}
+		}()
+
+		// then
+		select {
+		case <-end:
+			// nothing to do
+		case <-time.After(3 * time.Second):
+			t.Error("must not block")
+		}
+	})
+	t.Run("when context is done while waiting", func(t *testing.T) {
+		// given
+		key := "duck8823/cancel"
+		_ = semaphore.AcquireFor(context.Background(), key, 1)
+		defer semaphore.ReleaseFor(key, 1)
+
+		// and
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// and
+		errs := make(chan error, 1)
+		go func() {
+			errs <- semaphore.AcquireFor(ctx, key, 1)
+		}()
+
+		// when
+		cancel()
+
+		// then
+		select {
+		case err := <-errs:
+			if err != context.Canceled {
+				t.Errorf("error must be %+v, but got %+v", context.Canceled, err)
+			}
+		case <-time.After(3 * time.Second):
+			t.Error("must not block after cancel")
+		}
+	})
+}
@@ -11,6 +11,8 @@ import (
 type Builder struct {
 	docker    docker.Docker
 	logFunc   runner.LogFunc
+	resources docker.Resources
+	limits    docker.Limits
 	initFunc  func(context.Context)
 	startFunc func(context.Context)
 	endFunc   func(context.Context, error)
@@ -37,6 +39,18 @@ func (b *Builder) LogFunc(f runner.LogFunc) *Builder {
 	return b
 }
 
+// Resources set default resources of containers
+func (b *Builder) Resources(resources docker.Resources) *Builder {
+	b.resources = resources
+	return b
+}
+
+// Limits set maximums of resources requested by repositories
+func (b *Builder) Limits(limits docker.Limits) *Builder {
+	b.limits = limits
+	return b
+}
+
 // InitFunc set a initFunc
 func (b *Builder) InitFunc(f func(context.Context)) *Builder {
 	b.initFunc = f
@@ -59,6 +73,8 @@ func (b *Builder) EndFunc(f func(context.Context, error)) *Builder {
 func (b *Builder) Build() Executor {
 	r := runner.DefaultDockerRunnerBuilder().
 		LogFunc(b.logFunc).
+		Resources(b.resources).
+		Limits(b.limits).
 		Build()
 
 	return &jobExecutor{
@@ -2,13 +2,16 @@ package executor
 
 import (
 	"context"
+	"fmt"
 	"github.com/duck8823/duci/application"
 	"github.com/duck8823/duci/application/semaphore"
 	"github.com/duck8823/duci/domain/model/docker"
 	"github.com/duck8823/duci/domain/model/job"
 	"github.com/duck8823/duci/domain/model/runner"
+	"github.com/duck8823/duci/domain/model/task"
 	"github.com/labstack/gommon/random"
 	"github.com/pkg/errors"
+	"time"
 )
 
 // Executor is job executor
@@ -25,10 +28,53 @@ type jobExecutor struct {
 
 // Execute job
 func (r *jobExecutor) Execute(ctx context.Context, target job.Target, cmd ...string) error {
+	defer begin()()
+
+	name, repo := repository(ctx)
+
+	jobCtx, cancelJob := context.WithCancel(ctx)
+	defer cancelJob()
+
+	var id job.ID
+	tag := docker.Tag(random.String(16, random.Lowercase))
+	runCtx := jobCtx
+	duration := repo.TimeoutDuration()
+	if buildJob, err := application.BuildJobFromContext(ctx); err == nil {
+		id = buildJob.ID
+		tag = TagOf(id)
+		if len(buildJob.Task) > 0 {
+			runCtx = task.ContextWithName(runCtx, buildJob.Task)
+		}
+		if len(buildJob.Matrix) > 0 {
+			runCtx = task.ContextWithCombination(runCtx, buildJob.Matrix)
+		}
+		// a task can shorten the timeout of repository, but can not extend it.
+		if buildJob.Timeout > 0 && buildJob.Timeout < duration {
+			duration = buildJob.Timeout
+		}
+		trigger := buildJob.Trigger()
+		entry := &runningJob{
+			group:    fmt.Sprintf("%s/%s/%s", trigger.Repository, trigger.TaskName, trigger.Ref),
+			sha:      trigger.SHA,
+			queuedAt: buildJob.QueuedAt,
+			cancel:   cancelJob,
+		}
+		if entry.queuedAt.IsZero() {
+			entry.queuedAt = time.Now()
+		}
+		defer register(id, entry)()
+		if repo.IsAutoCancel() {
+			supersede(id, entry)
+		}
+	}
+
 	r.InitFunc(ctx)
 
-	workDir, cleanup, err := target.Prepare(ctx)
+	workDir, cleanup, err := target.Prepare(jobCtx)
 	if err != nil {
+		if jobCtx.Err() == context.Canceled {
+			err = cancelCause(id, context.Canceled)
+		}
 		r.EndFunc(ctx, err)
 		return errors.WithStack(err)
 	}
@@ -36,22 +82,60 @@ func (r *jobExecutor) Execute(ctx context.Context, target job.Target, cmd ...str
 
 	errs := make(chan error, 1)
 
-	timeout, cancel := context.WithTimeout(ctx, application.Config.Timeout())
+	timeout, cancel := context.WithTimeout(runCtx, duration)
 	defer cancel()
 
 	go func() {
-		semaphore.Acquire()
+		if err := semaphore.AcquireFor(timeout, name, repo.Concurrency); err != nil {
+			errs <- err
+			return
+		}
+		defer semaphore.ReleaseFor(name, repo.Concurrency)
+		if err := semaphore.Acquire(timeout); err != nil {
+			errs <- err
+			return
+		}
+		defer semaphore.Release()
+
+		if !start(id) {
+			errs <- context.Canceled
+			return
+		}
 		r.StartFunc(ctx)
-		errs <- r.DockerRunner.Run(timeout, workDir, docker.Tag(random.String(16, random.Lowercase)), cmd)
-		semaphore.Release()
+		errs <- r.DockerRunner.Run(timeout, workDir, tag, cmd)
 	}()
 
 	select {
 	case <-timeout.Done():
-		r.EndFunc(ctx, timeout.Err())
-		return timeout.Err()
+		err := cancelCause(id, timeout.Err())
+		r.EndFunc(ctx, err)
+		<-errs // wait for the runner to remove the container
+		return err
 	case err := <-errs:
+		if timeout.Err() != nil {
+			err = cancelCause(id, timeout.Err())
+		}
 		r.EndFunc(ctx, err)
 		return err
 	}
 }
+
+// TagOf returns a tag of docker image for the job, which is also used to find containers left by the job
+func TagOf(id job.ID) docker.Tag {
+	return docker.Tag(fmt.Sprintf("%s-%s", application.Name, id))
+}
+
+// repository returns full name and configuration of the target repository of job
+func repository(ctx context.Context) (string, *application.Repository) {
+	buildJob, err := application.BuildJobFromContext(ctx)
+	if err != nil || buildJob.TargetSource == nil || buildJob.TargetSource.Repository == nil {
+		return "", application.Config.DefaultRepository()
+	}
+
+	name := buildJob.TargetSource.GetFullName()
+	repo, err := application.Config.Repository(name)
+	if err != nil {
+		return name, application.Config.DefaultRepository()
+	}
+	return name, repo
+}
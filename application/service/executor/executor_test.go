@@ -5,10 +5,16 @@ import (
 	"github.com/duck8823/duci/application"
 	"github.com/duck8823/duci/application/service/executor"
 	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/domain/model/job/target/github"
 	"github.com/duck8823/duci/domain/model/runner/mock_runner"
+	"github.com/duck8823/duci/domain/model/task"
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	go_github "github.com/google/go-github/github"
+	"github.com/google/uuid"
 	"github.com/labstack/gommon/random"
 	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4/plumbing"
 	"os"
 	"path/filepath"
 	"testing"
@@ -206,6 +212,107 @@ func TestJobExecutor_Execute(t *testing.T) {
 		}
 	})
 
+	t.Run("with task", func(t *testing.T) {
+		// given
+		ctx := application.ContextWithJob(context.Background(), &application.BuildJob{
+			ID:       job.ID(uuid.New()),
+			TaskName: "duci/test",
+			Task:     "test",
+			Timeout:  time.Second,
+		})
+		target := &executor.StubTarget{
+			Dir:     job.WorkDir(filepath.Join(os.TempDir(), random.String(16))),
+			Cleanup: func() {},
+			Err:     nil,
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		runner := mock_runner.NewMockDockerRunner(ctrl)
+		runner.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(ctx context.Context, _, _, _ interface{}) error {
+				if name, _ := task.NameFromContext(ctx); name != "test" {
+					t.Errorf("task name must be test, but got %s", name)
+				}
+				<-ctx.Done()
+				return ctx.Err()
+			})
+
+		// and
+		sut := &executor.JobExecutor{}
+		defer sut.SetDockerRunner(runner)()
+		defer sut.SetInitFunc(func(context.Context) {})()
+		defer sut.SetStartFunc(func(context.Context) {})()
+		defer sut.SetEndFunc(func(context.Context, error) {})()
+
+		// when
+		err := sut.Execute(ctx, target)
+
+		// then
+		if err != context.DeadlineExceeded {
+			t.Errorf("must be equal. want %+v, but got %+v", context.DeadlineExceeded, err)
+		}
+	})
+
+	t.Run("with task timeout longer than repository", func(t *testing.T) {
+		// given
+		timeout := application.Config.Timeout()
+		application.Config.Job.Timeout = 1
+		defer func() {
+			application.Config.Job.Timeout = int64(timeout / time.Second)
+		}()
+
+		// and
+		ctx := application.ContextWithJob(context.Background(), &application.BuildJob{
+			ID:       job.ID(uuid.New()),
+			TaskName: "duci/test",
+			Task:     "test",
+			Timeout:  time.Hour,
+		})
+		target := &executor.StubTarget{
+			Dir:     job.WorkDir(filepath.Join(os.TempDir(), random.String(16))),
+			Cleanup: func() {},
+			Err:     nil,
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		runner := mock_runner.NewMockDockerRunner(ctrl)
+		runner.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(ctx context.Context, _, _, _ interface{}) error {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(5 * time.Second):
+					t.Error("must time out by the timeout of repository")
+					return nil
+				}
+			})
+
+		// and
+		sut := &executor.JobExecutor{}
+		defer sut.SetDockerRunner(runner)()
+		defer sut.SetInitFunc(func(context.Context) {})()
+		defer sut.SetStartFunc(func(context.Context) {})()
+		defer sut.SetEndFunc(func(context.Context, error) {})()
+
+		// when
+		err := sut.Execute(ctx, target)
+
+		// then
+		if err != context.DeadlineExceeded {
+			t.Errorf("must be equal. want %+v, but got %+v", context.DeadlineExceeded, err)
+		}
+	})
+
 	t.Run("when prepare returns error", func(t *testing.T) {
 		// given
 		ctx := context.Background()
@@ -263,4 +370,367 @@ func TestJobExecutor_Execute(t *testing.T) {
 			t.Errorf("must be called endFunc")
 		}
 	})
+	t.Run("when cancelled", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+		ctx := application.ContextWithJob(context.Background(), &application.BuildJob{ID: id})
+		target := &executor.StubTarget{
+			Dir:     job.WorkDir(filepath.Join(os.TempDir(), random.String(16))),
+			Cleanup: func() {},
+			Err:     nil,
+		}
+
+		// and
+		var endErr, endCtxErr error
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		runner := mock_runner.NewMockDockerRunner(ctrl)
+		runner.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(ctx context.Context, _, _, _ interface{}) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+
+		// and
+		sut := &executor.JobExecutor{}
+		defer sut.SetDockerRunner(runner)()
+		defer sut.SetInitFunc(func(context.Context) {})()
+		defer sut.SetStartFunc(func(context.Context) {
+			go func() {
+				if err := executor.Cancel(id); err != nil {
+					t.Errorf("error must be nil, but got %+v", err)
+				}
+			}()
+		})()
+		defer sut.SetEndFunc(func(ctx context.Context, err error) {
+			endErr = err
+			endCtxErr = ctx.Err()
+		})()
+
+		// when
+		err := sut.Execute(ctx, target)
+
+		// then
+		if err != context.Canceled {
+			t.Errorf("must be equal. want %+v, but got %+v", context.Canceled, err)
+		}
+
+		// and
+		if endErr != context.Canceled {
+			t.Errorf("endFunc must be called with %+v, but got %+v", context.Canceled, endErr)
+		}
+
+		// and
+		if endCtxErr != nil {
+			t.Errorf("context of endFunc must not be done, but got %+v", endCtxErr)
+		}
+
+		// and
+		if err := executor.Cancel(id); err != executor.ErrNotRunning {
+			t.Errorf("must be %+v after end, but got %+v", executor.ErrNotRunning, err)
+		}
+	})
+	t.Run("when superseded by newer commit", func(t *testing.T) {
+		// given
+		application.Config.Job.AutoCancel = true
+		defer func() {
+			application.Config.Job.AutoCancel = false
+		}()
+
+		// and
+		newJob := func(sha string) context.Context {
+			return application.ContextWithJob(context.Background(), &application.BuildJob{
+				ID: job.ID(uuid.New()),
+				TargetSource: &github.TargetSource{
+					Repository: &go_github.Repository{FullName: go_github.String("duck8823/duci")},
+					Ref:        "refs/heads/master",
+					SHA:        plumbing.NewHash(sha),
+				},
+				TaskName: "duci/push",
+			})
+		}
+		oldSHA := "1f1d7a8f0a3fae2a7c8aebd6a1b1f0e2a2a4b5c6"
+		newSHA := "5e1f1d7a8f0a3fae2a7c8aebd6a1b1f0e2a2a4b5"
+
+		// and
+		target := &executor.StubTarget{
+			Dir:     job.WorkDir(filepath.Join(os.TempDir(), random.String(16))),
+			Cleanup: func() {},
+			Err:     nil,
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		runner := mock_runner.NewMockDockerRunner(ctrl)
+		runner.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(2).
+			DoAndReturn(func(ctx context.Context, _, _, _ interface{}) error {
+				buildJob, _ := application.BuildJobFromContext(ctx)
+				if buildJob.TargetSource.GetSHA().String() == oldSHA {
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				return nil
+			})
+
+		// and
+		started := make(chan struct{}, 1)
+
+		sut := &executor.JobExecutor{}
+		defer sut.SetDockerRunner(runner)()
+		defer sut.SetInitFunc(func(context.Context) {})()
+		defer sut.SetStartFunc(func(context.Context) {
+			started <- struct{}{}
+		})()
+		defer sut.SetEndFunc(func(context.Context, error) {})()
+
+		// and
+		errs := make(chan error, 1)
+		go func() {
+			errs <- sut.Execute(newJob(oldSHA), target)
+		}()
+		<-started
+
+		// when
+		if err := sut.Execute(newJob(newSHA), target); err != nil {
+			t.Errorf("error must not occur, but got %+v", err)
+		}
+
+		// then
+		select {
+		case err := <-errs:
+			want := &executor.SupersededError{SHA: newSHA}
+			if !cmp.Equal(err, want) {
+				t.Errorf("must be equal, but %+v", cmp.Diff(err, want))
+			}
+		case <-time.After(3 * time.Second):
+			t.Error("older job must be cancelled")
+		}
+	})
+
+	t.Run("when older commit is queued after newer commit", func(t *testing.T) {
+		// given
+		application.Config.Job.AutoCancel = true
+		defer func() {
+			application.Config.Job.AutoCancel = false
+		}()
+
+		// and
+		queuedAt := time.Now()
+		newJob := func(sha string, queuedAt time.Time) context.Context {
+			return application.ContextWithJob(context.Background(), &application.BuildJob{
+				ID: job.ID(uuid.New()),
+				TargetSource: &github.TargetSource{
+					Repository: &go_github.Repository{FullName: go_github.String("duck8823/duci")},
+					Ref:        "refs/heads/master",
+					SHA:        plumbing.NewHash(sha),
+				},
+				TaskName: "duci/push",
+				QueuedAt: queuedAt,
+			})
+		}
+		oldSHA := "1f1d7a8f0a3fae2a7c8aebd6a1b1f0e2a2a4b5c6"
+		newSHA := "5e1f1d7a8f0a3fae2a7c8aebd6a1b1f0e2a2a4b5"
+
+		// and
+		target := &executor.StubTarget{
+			Dir:     job.WorkDir(filepath.Join(os.TempDir(), random.String(16))),
+			Cleanup: func() {},
+			Err:     nil,
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		release := make(chan struct{})
+		runner := mock_runner.NewMockDockerRunner(ctrl)
+		runner.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(ctx context.Context, _, _, _ interface{}) error {
+				<-release
+				return ctx.Err()
+			})
+
+		// and
+		started := make(chan struct{}, 1)
+
+		sut := &executor.JobExecutor{}
+		defer sut.SetDockerRunner(runner)()
+		defer sut.SetInitFunc(func(context.Context) {})()
+		defer sut.SetStartFunc(func(context.Context) {
+			started <- struct{}{}
+		})()
+		defer sut.SetEndFunc(func(context.Context, error) {})()
+
+		// and
+		errs := make(chan error, 1)
+		go func() {
+			errs <- sut.Execute(newJob(newSHA, queuedAt.Add(time.Minute)), target)
+		}()
+		<-started
+
+		// when
+		err := sut.Execute(newJob(oldSHA, queuedAt), target)
+		close(release)
+
+		// then
+		want := &executor.SupersededError{SHA: newSHA}
+		if !cmp.Equal(err, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(err, want))
+		}
+
+		// and
+		if err := <-errs; err != nil {
+			t.Errorf("newer job must not be cancelled, but got %+v", err)
+		}
+	})
+}
+
+func TestCancel(t *testing.T) {
+	t.Run("when job is not running", func(t *testing.T) {
+		// when
+		err := executor.Cancel(job.ID(uuid.New()))
+
+		// then
+		if err != executor.ErrNotRunning {
+			t.Errorf("must be %+v, but got %+v", executor.ErrNotRunning, err)
+		}
+	})
+}
+
+type blockingTarget struct {
+	prepared chan struct{}
+}
+
+func (t *blockingTarget) Prepare(ctx context.Context) (job.WorkDir, job.Cleanup, error) {
+	t.prepared <- struct{}{}
+	<-ctx.Done()
+	return "", func() {}, ctx.Err()
+}
+
+func TestShutdown(t *testing.T) {
+	t.Run("when there are no jobs", func(t *testing.T) {
+		// given
+		defer executor.SetClosing(false)()
+
+		// when
+		err := executor.Shutdown(context.Background())
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("with queued job", func(t *testing.T) {
+		// given
+		defer executor.SetClosing(false)()
+
+		// and
+		ctx := application.ContextWithJob(context.Background(), &application.BuildJob{ID: job.ID(uuid.New())})
+		target := &blockingTarget{prepared: make(chan struct{}, 1)}
+
+		// and
+		ended := make(chan error, 1)
+		sut := &executor.JobExecutor{}
+		defer sut.SetInitFunc(func(context.Context) {})()
+		defer sut.SetEndFunc(func(_ context.Context, err error) {
+			ended <- err
+		})()
+
+		// and
+		go func() {
+			_ = sut.Execute(ctx, target)
+		}()
+		<-target.prepared
+
+		// and
+		timeout, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		// when
+		err := executor.Shutdown(timeout)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if got := <-ended; got != executor.ErrSuspended {
+			t.Errorf("endFunc must be called with %+v, but got %+v", executor.ErrSuspended, got)
+		}
+	})
+
+	t.Run("with running job longer than grace period", func(t *testing.T) {
+		// given
+		defer executor.SetClosing(false)()
+
+		// and
+		ctx := application.ContextWithJob(context.Background(), &application.BuildJob{ID: job.ID(uuid.New())})
+		target := &executor.StubTarget{
+			Dir:     job.WorkDir(filepath.Join(os.TempDir(), random.String(16))),
+			Cleanup: func() {},
+			Err:     nil,
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		runner := mock_runner.NewMockDockerRunner(ctrl)
+		runner.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(ctx context.Context, _, _, _ interface{}) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+
+		// and
+		started := make(chan struct{}, 1)
+		ended := make(chan error, 1)
+		sut := &executor.JobExecutor{}
+		defer sut.SetDockerRunner(runner)()
+		defer sut.SetInitFunc(func(context.Context) {})()
+		defer sut.SetStartFunc(func(context.Context) {
+			started <- struct{}{}
+		})()
+		defer sut.SetEndFunc(func(_ context.Context, err error) {
+			ended <- err
+		})()
+
+		// and
+		go func() {
+			_ = sut.Execute(ctx, target)
+		}()
+		<-started
+
+		// and
+		timeout, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		// when
+		err := executor.Shutdown(timeout)
+
+		// then
+		if err != context.DeadlineExceeded {
+			t.Errorf("error must be %+v, but got %+v", context.DeadlineExceeded, err)
+		}
+
+		// and
+		if got := <-ended; got != executor.ErrShutdown {
+			t.Errorf("endFunc must be called with %+v, but got %+v", executor.ErrShutdown, got)
+		}
+	})
 }
@@ -93,3 +93,15 @@ type StubTarget struct {
 func (t *StubTarget) Prepare(context.Context) (dir job.WorkDir, cleanup job.Cleanup, err error) {
 	return t.Dir, t.Cleanup, t.Err
 }
+
+func SetClosing(b bool) (reset func()) {
+	mu.Lock()
+	tmp := closing
+	closing = b
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		closing = tmp
+		mu.Unlock()
+	}
+}
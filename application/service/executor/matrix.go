@@ -0,0 +1,50 @@
+package executor
+
+import (
+	"context"
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// ExecuteAll executes the jobs concurrently, and returns their errors in the same order.
+// If failFast is true, the first failure cancels the other jobs. Cancelled, suspended or skipped jobs do not.
+func ExecuteAll(executor Executor, target job.Target, jobs []*application.BuildJob, failFast bool) []error {
+	errs := make([]error, len(jobs))
+
+	var once sync.Once
+	var wg sync.WaitGroup
+	for i, buildJob := range jobs {
+		wg.Add(1)
+		go func(i int, buildJob *application.BuildJob) {
+			defer wg.Done()
+
+			ctx := application.ContextWithJob(context.Background(), buildJob)
+			errs[i] = executor.Execute(ctx, target, buildJob.Command...)
+			if !failFast || !failed(errs[i]) {
+				return
+			}
+			once.Do(func() {
+				for _, other := range jobs {
+					if other.ID != buildJob.ID {
+						abort(other.ID, &FailFastError{TaskName: buildJob.TaskName})
+					}
+				}
+			})
+		}(i, buildJob)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// failed returns whether the job failed, timed out or errored, rather than cancelled, suspended or skipped
+func failed(err error) bool {
+	switch errors.Cause(err) {
+	case nil, context.Canceled, ErrSuspended, ErrShutdown, ErrSkipped:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,153 @@
+package executor_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/application/service/executor"
+	"github.com/duck8823/duci/application/service/executor/mock_executor"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"testing"
+	"time"
+)
+
+func TestExecuteAll(t *testing.T) {
+	t.Run("with fail fast", func(t *testing.T) {
+		// given
+		failing := &application.BuildJob{ID: job.ID(uuid.New()), TaskName: "duci/test (go=1.12)"}
+		other := &application.BuildJob{ID: job.ID(uuid.New()), TaskName: "duci/test (go=1.13)"}
+
+		// and
+		target := &failingTarget{
+			failing:  failing.ID,
+			err:      errors.New("test error"),
+			prepared: make(chan struct{}, 1),
+		}
+
+		// and
+		sut := &executor.JobExecutor{}
+		defer sut.SetInitFunc(func(context.Context) {})()
+		defer sut.SetEndFunc(func(context.Context, error) {})()
+
+		// when
+		got := executor.ExecuteAll(sut, target, []*application.BuildJob{failing, other}, true)
+
+		// then
+		if errors.Cause(got[0]).Error() != "test error" {
+			t.Errorf("must be test error, but got %+v", got[0])
+		}
+
+		// and
+		var failFast *executor.FailFastError
+		if !errors.As(got[1], &failFast) {
+			t.Fatalf("must be fail fast error, but got %+v", got[1])
+		}
+		want := &executor.FailFastError{TaskName: "duci/test (go=1.12)"}
+		if !cmp.Equal(failFast, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(failFast, want))
+		}
+	})
+
+	// where
+	for _, tt := range []struct {
+		name string
+		err  error
+	}{
+		{name: "when job is cancelled", err: context.Canceled},
+		{name: "when job is superseded", err: &executor.SupersededError{SHA: "abcdef"}},
+		{name: "when job is cancelled by fail fast", err: &executor.FailFastError{TaskName: "duci/test (go=1.11)"}},
+		{name: "when job is suspended", err: executor.ErrSuspended},
+		{name: "when job is skipped", err: &executor.SkippedError{Stage: "build"}},
+	} {
+		t.Run(fmt.Sprintf("with fail fast %s", tt.name), func(t *testing.T) {
+			// given
+			cancelled := &application.BuildJob{ID: job.ID(uuid.New()), TaskName: "duci/test (go=1.12)"}
+			other := &application.BuildJob{ID: job.ID(uuid.New()), TaskName: "duci/test (go=1.13)"}
+
+			// and
+			target := &failingTarget{
+				failing:  cancelled.ID,
+				err:      tt.err,
+				prepared: make(chan struct{}, 1),
+			}
+
+			// and
+			sut := &executor.JobExecutor{}
+			defer sut.SetInitFunc(func(context.Context) {})()
+			defer sut.SetEndFunc(func(context.Context, error) {})()
+
+			// when
+			got := executor.ExecuteAll(sut, target, []*application.BuildJob{cancelled, other}, true)
+
+			// then
+			if errors.Cause(got[0]) != errors.Cause(tt.err) {
+				t.Errorf("must be %+v, but got %+v", tt.err, got[0])
+			}
+
+			// and
+			if errors.Cause(got[1]) == context.Canceled {
+				t.Errorf("other job must not be cancelled, but got %+v", got[1])
+			}
+		})
+	}
+
+	t.Run("without fail fast", func(t *testing.T) {
+		// given
+		jobs := []*application.BuildJob{
+			{ID: job.ID(uuid.New()), TaskName: "duci/test (go=1.12)", Command: []string{"make", "test"}},
+			{ID: job.ID(uuid.New()), TaskName: "duci/test (go=1.13)", Command: []string{"make", "test"}},
+		}
+		wantErr := errors.New("test error")
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sut := mock_executor.NewMockExecutor(ctrl)
+		sut.EXPECT().
+			Execute(gomock.Any(), gomock.Any(), gomock.Eq("make"), gomock.Eq("test")).
+			Times(2).
+			DoAndReturn(func(ctx context.Context, _ job.Target, _ ...string) error {
+				buildJob, _ := application.BuildJobFromContext(ctx)
+				if buildJob.ID == jobs[0].ID {
+					return wantErr
+				}
+				return nil
+			})
+
+		// when
+		got := executor.ExecuteAll(sut, &executor.StubTarget{}, jobs, false)
+
+		// then
+		if len(got) != 2 || got[0] != wantErr || got[1] != nil {
+			t.Errorf("errors must be in order of jobs, but got %+v", got)
+		}
+	})
+}
+
+type failingTarget struct {
+	failing  job.ID
+	err      error
+	prepared chan struct{}
+}
+
+// Prepare returns the error for the failing job after the other is prepared.
+// The other job waits for cancel, and succeeds to prepare if it is not cancelled in a while.
+func (t *failingTarget) Prepare(ctx context.Context) (job.WorkDir, job.Cleanup, error) {
+	buildJob, _ := application.BuildJobFromContext(ctx)
+	if buildJob.ID == t.failing {
+		<-t.prepared
+		return "", func() {}, t.err
+	}
+	t.prepared <- struct{}{}
+	select {
+	case <-ctx.Done():
+		return "", func() {}, ctx.Err()
+	case <-time.After(300 * time.Millisecond):
+		return "", func() {}, errors.New("not cancelled")
+	}
+}
@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// ErrSkipped represents a error of job not executed because a stage it needs failed
+var ErrSkipped = errors.New("skipped")
+
+// SkippedError represents a error of job skipped by failure of the stage it needs
+type SkippedError struct {
+	Stage string
+}
+
+// Error returns a message with the name of failed stage
+func (e *SkippedError) Error() string {
+	return fmt.Sprintf("skipped by failure of %s", e.Stage)
+}
+
+// Cause returns ErrSkipped
+func (e *SkippedError) Cause() error {
+	return ErrSkipped
+}
+
+// Stage represents jobs of a task in a pipeline, and names of stages to wait for.
+type Stage struct {
+	Name     string
+	Needs    []string
+	Jobs     []*application.BuildJob
+	FailFast bool
+}
+
+// ExecutePipeline executes each stage after all stages it needs succeed, and returns their errors in the same order.
+// Jobs of all stages are queued at once and wait for the stages they need, so that waiting jobs are suspended by shutdown.
+// Jobs record ids of jobs they need, to wait for them again after resumed.
+// Jobs of a stage are skipped if a stage it needs fails, or suspended if it is suspended. Stages must not need each other circularly.
+func ExecutePipeline(executor Executor, target job.Target, stages []Stage) []error {
+	defer begin()()
+
+	errs := make([]error, len(stages))
+	done := make(map[string]chan struct{}, len(stages))
+	index := make(map[string]int, len(stages))
+	for i, stage := range stages {
+		done[stage.Name] = make(chan struct{})
+		index[stage.Name] = i
+	}
+
+	for _, stage := range stages {
+		var needs []job.ID
+		for _, need := range stage.Needs {
+			if i, ok := index[need]; ok {
+				for _, needed := range stages[i].Jobs {
+					needs = append(needs, needed.ID)
+				}
+			}
+		}
+		for _, buildJob := range stage.Jobs {
+			buildJob.Needs = needs
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, stage := range stages {
+		wg.Add(1)
+		go func(i int, stage Stage) {
+			defer wg.Done()
+			defer close(done[stage.Name])
+
+			waiting := &WaitingTarget{Target: target, Wait: func(ctx context.Context) error {
+				for _, need := range stage.Needs {
+					ch, ok := done[need]
+					if !ok {
+						continue
+					}
+					select {
+					case <-ch:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+					if err := errs[index[need]]; errors.Cause(err) == ErrSuspended {
+						return ErrSuspended
+					} else if err != nil {
+						return &SkippedError{Stage: need}
+					}
+				}
+				return nil
+			}}
+			errs[i] = stageError(ExecuteAll(executor, waiting, stage.Jobs, stage.FailFast))
+		}(i, stage)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// stageError returns the first error of jobs in the stage.
+// Suspension is returned only if no other error, because the stage can not succeed after resumed.
+func stageError(errs []error) error {
+	var suspended error
+	for _, err := range errs {
+		if errors.Cause(err) == ErrSuspended {
+			suspended = err
+		} else if err != nil {
+			return err
+		}
+	}
+	return suspended
+}
+
+// WaitingTarget is a target prepared after Wait returns nil, so that the job is queued while waiting for jobs it needs.
+// The job ends with the error of Wait without being prepared.
+type WaitingTarget struct {
+	job.Target
+	Wait func(ctx context.Context) error
+}
+
+// Prepare waits and prepares the target
+func (t *WaitingTarget) Prepare(ctx context.Context) (job.WorkDir, job.Cleanup, error) {
+	if err := t.Wait(ctx); err != nil {
+		return "", func() {}, err
+	}
+	return t.Target.Prepare(ctx)
+}
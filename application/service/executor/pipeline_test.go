@@ -0,0 +1,183 @@
+package executor_test
+
+import (
+	"context"
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/application/service/executor"
+	"github.com/duck8823/duci/application/service/executor/mock_executor"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExecutePipeline(t *testing.T) {
+	t.Run("when all stages succeed", func(t *testing.T) {
+		// given
+		stages := []executor.Stage{
+			{Name: "integration", Needs: []string{"lint", "unit"}, Jobs: jobsOf("duci/integration")},
+			{Name: "lint", Needs: []string{"build"}, Jobs: jobsOf("duci/lint")},
+			{Name: "unit", Needs: []string{"build"}, Jobs: jobsOf("duci/unit")},
+			{Name: "build", Jobs: jobsOf("duci/build")},
+		}
+
+		// and
+		var mu sync.Mutex
+		var executed []string
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sut := mock_executor.NewMockExecutor(ctrl)
+		sut.EXPECT().
+			Execute(gomock.Any(), gomock.Any()).
+			Times(4).
+			DoAndReturn(func(ctx context.Context, target job.Target, _ ...string) error {
+				if _, _, err := target.Prepare(ctx); err != nil {
+					return err
+				}
+				buildJob, _ := application.BuildJobFromContext(ctx)
+				mu.Lock()
+				executed = append(executed, buildJob.TaskName)
+				mu.Unlock()
+				return nil
+			})
+
+		// when
+		got := executor.ExecutePipeline(sut, &executor.StubTarget{}, stages)
+
+		// then
+		if !cmp.Equal(got, []error{nil, nil, nil, nil}) {
+			t.Errorf("errors must be nil, but got %+v", got)
+		}
+
+		// and
+		if executed[0] != "duci/build" || executed[3] != "duci/integration" {
+			t.Errorf("stages must be executed in order of dependencies, but got %+v", executed)
+		}
+
+		// and
+		want := []job.ID{stages[1].Jobs[0].ID, stages[2].Jobs[0].ID}
+		if !cmp.Equal(stages[0].Jobs[0].Needs, want) {
+			t.Errorf("job must record ids of jobs it needs, but %+v", cmp.Diff(stages[0].Jobs[0].Needs, want))
+		}
+	})
+
+	t.Run("when a stage fails", func(t *testing.T) {
+		// given
+		stages := []executor.Stage{
+			{Name: "build", Jobs: jobsOf("duci/build")},
+			{Name: "unit", Needs: []string{"build"}, Jobs: jobsOf("duci/unit")},
+			{Name: "lint", Jobs: jobsOf("duci/lint")},
+		}
+		wantErr := errors.New("test error")
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sut := mock_executor.NewMockExecutor(ctrl)
+		sut.EXPECT().
+			Execute(gomock.Any(), gomock.Any()).
+			Times(3).
+			DoAndReturn(func(ctx context.Context, target job.Target, _ ...string) error {
+				if _, _, err := target.Prepare(ctx); err != nil {
+					return err
+				}
+				buildJob, _ := application.BuildJobFromContext(ctx)
+				if buildJob.TaskName == "duci/build" {
+					return wantErr
+				}
+				return nil
+			})
+
+		// when
+		got := executor.ExecutePipeline(sut, &executor.StubTarget{}, stages)
+
+		// then
+		if got[0] != wantErr {
+			t.Errorf("must be %+v, but got %+v", wantErr, got[0])
+		}
+
+		// and
+		want := &executor.SkippedError{Stage: "build"}
+		if !cmp.Equal(got[1], want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got[1], want))
+		}
+
+		// and
+		if got[2] != nil {
+			t.Errorf("stage not depending on failed stage must succeed, but got %+v", got[2])
+		}
+	})
+
+	t.Run("when server shuts down while stages wait", func(t *testing.T) {
+		// given
+		defer executor.SetClosing(false)()
+
+		// and
+		stages := []executor.Stage{
+			{Name: "build", Jobs: jobsOf("duci/build")},
+			{Name: "unit", Needs: []string{"build"}, Jobs: jobsOf("duci/unit")},
+		}
+		target := &blockingTarget{prepared: make(chan struct{}, 2)}
+
+		// and
+		queued := make(chan string, 2)
+		ended := make(chan error, 2)
+		sut := &executor.JobExecutor{}
+		defer sut.SetInitFunc(func(ctx context.Context) {
+			buildJob, _ := application.BuildJobFromContext(ctx)
+			queued <- buildJob.TaskName
+		})()
+		defer sut.SetEndFunc(func(_ context.Context, err error) {
+			ended <- err
+		})()
+
+		// and
+		result := make(chan []error, 1)
+		go func() {
+			result <- executor.ExecutePipeline(sut, target, stages)
+		}()
+		<-target.prepared
+
+		// and
+		for i := 0; i < 2; i++ {
+			<-queued
+		}
+
+		// and
+		timeout, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		// when
+		err := executor.Shutdown(timeout)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		for i := 0; i < 2; i++ {
+			if got := <-ended; errors.Cause(got) != executor.ErrSuspended {
+				t.Errorf("endFunc must be called with %+v, but got %+v", executor.ErrSuspended, got)
+			}
+		}
+
+		// and
+		for i, got := range <-result {
+			if errors.Cause(got) != executor.ErrSuspended {
+				t.Errorf("stage %s must be suspended, but got %+v", stages[i].Name, got)
+			}
+		}
+	})
+}
+
+func jobsOf(taskName string) []*application.BuildJob {
+	return []*application.BuildJob{{ID: job.ID(uuid.New()), TaskName: taskName}}
+}
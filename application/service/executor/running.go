@@ -0,0 +1,216 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotRunning represents a error of job neither queued nor running in this server
+	ErrNotRunning = errors.New("job is not running")
+	// ErrSuspended represents a error of queued job suspended to be resumed by the next server
+	ErrSuspended = errors.New("suspended by server shutdown")
+	// ErrShutdown represents a error of running job cancelled after grace period of shutdown
+	ErrShutdown = errors.New("interrupted by server shutdown")
+)
+
+// SupersededError represents a error of job cancelled by a newer job on the same ref
+type SupersededError struct {
+	SHA string
+}
+
+// Error returns a message with the newer SHA
+func (e *SupersededError) Error() string {
+	return fmt.Sprintf("superseded by %s", e.SHA)
+}
+
+// Cause returns context.Canceled
+func (e *SupersededError) Cause() error {
+	return context.Canceled
+}
+
+// FailFastError represents a error of job cancelled by failure of another job in the same matrix
+type FailFastError struct {
+	TaskName string
+}
+
+// Error returns a message with the name of failed job
+func (e *FailFastError) Error() string {
+	return fmt.Sprintf("cancelled by failure of %s", e.TaskName)
+}
+
+// Cause returns context.Canceled
+func (e *FailFastError) Cause() error {
+	return context.Canceled
+}
+
+type runningJob struct {
+	group    string
+	sha      string
+	queuedAt time.Time
+	cancel   context.CancelFunc
+	cause    error
+	started  bool
+}
+
+var (
+	running  = make(map[job.ID]*runningJob)
+	inflight int
+	closing  bool
+	mu       sync.Mutex
+	idle     = sync.NewCond(&mu)
+)
+
+// Cancel cancels the context of queued or running job
+func Cancel(id job.ID) error {
+	mu.Lock()
+	entry, ok := running[id]
+	mu.Unlock()
+
+	if !ok {
+		return ErrNotRunning
+	}
+	entry.cancel()
+	return nil
+}
+
+// abort cancels the queued or running job with the reason, unless it has already been cancelled
+func abort(id job.ID, cause error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if entry, ok := running[id]; ok && entry.cause == nil {
+		entry.cause = cause
+		entry.cancel()
+	}
+}
+
+// register stores a function to cancel the job, and returns a function to remove it
+func register(id job.ID, entry *runningJob) (unregister func()) {
+	mu.Lock()
+	running[id] = entry
+	if closing {
+		entry.cause = ErrSuspended
+		entry.cancel()
+	}
+	mu.Unlock()
+
+	return func() {
+		mu.Lock()
+		delete(running, id)
+		mu.Unlock()
+	}
+}
+
+// supersede cancels jobs queued earlier in the same group for different commits.
+// The job itself is cancelled instead if a job queued later exists, such as when webhooks arrive out of order.
+func supersede(id job.ID, entry *runningJob) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for other, old := range running {
+		if other == id || old.group != entry.group || old.sha == entry.sha || old.cause != nil {
+			continue
+		}
+		if old.queuedAt.Before(entry.queuedAt) {
+			old.cause = &SupersededError{SHA: entry.sha}
+			old.cancel()
+		} else if old.queuedAt.After(entry.queuedAt) && entry.cause == nil {
+			entry.cause = &SupersededError{SHA: old.sha}
+			entry.cancel()
+		}
+	}
+}
+
+// cancelCause returns the reason why the job was cancelled, or err if not known
+func cancelCause(id job.ID, err error) error {
+	if err != context.Canceled {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if entry, ok := running[id]; ok && entry.cause != nil {
+		return entry.cause
+	}
+	return err
+}
+
+// begin counts the job in flight, and returns a function to uncount it
+func begin() (end func()) {
+	mu.Lock()
+	inflight++
+	mu.Unlock()
+
+	return func() {
+		mu.Lock()
+		inflight--
+		if inflight == 0 {
+			idle.Broadcast()
+		}
+		mu.Unlock()
+	}
+}
+
+// start marks the job running, and returns false if the job has already been cancelled with a reason
+func start(id job.ID) bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	entry, ok := running[id]
+	if !ok {
+		return true
+	}
+	if entry.cause != nil {
+		return false
+	}
+	entry.started = true
+	return true
+}
+
+// Shutdown suspends queued jobs to be resumed by the next server, and waits for running jobs.
+// If the context is done before they finish, it cancels them and returns the error of the context.
+func Shutdown(ctx context.Context) error {
+	mu.Lock()
+	closing = true
+	for _, entry := range running {
+		if !entry.started && entry.cause == nil {
+			entry.cause = ErrSuspended
+			entry.cancel()
+		}
+	}
+	mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		mu.Lock()
+		for inflight > 0 {
+			idle.Wait()
+		}
+		mu.Unlock()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	mu.Lock()
+	for _, entry := range running {
+		if entry.cause == nil {
+			entry.cause = ErrShutdown
+		}
+		entry.cancel()
+	}
+	mu.Unlock()
+
+	<-done
+	return ctx.Err()
+}
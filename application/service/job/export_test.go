@@ -1,6 +1,9 @@
 package job
 
-import "github.com/duck8823/duci/domain/model/job"
+import (
+	"github.com/duck8823/duci/domain/model/job"
+	"time"
+)
 
 type StubService struct {
 	ID string
@@ -10,7 +13,23 @@ func (s *StubService) FindBy(_ job.ID) (*job.Job, error) {
 	return nil, nil
 }
 
-func (s *StubService) Start(_ job.ID) error {
+func (s *StubService) FindLogs(_ job.ID, _ int, _ int) ([]job.LogLine, error) {
+	return nil, nil
+}
+
+func (s *StubService) ReverseLogs(_ job.ID, _ int, _ int, _ func(int, job.LogLine) bool) error {
+	return nil
+}
+
+func (s *StubService) Search(_ job.Query) (*job.Page, error) {
+	return nil, nil
+}
+
+func (s *StubService) Queue(_ job.ID, _ job.Trigger, _ time.Time) error {
+	return nil
+}
+
+func (s *StubService) Start(_ job.ID, _ time.Time) error {
 	return nil
 }
 
@@ -18,10 +37,22 @@ func (s *StubService) Append(_ job.ID, _ job.LogLine) error {
 	return nil
 }
 
-func (s *StubService) Finish(_ job.ID) error {
+func (s *StubService) Truncate(_ job.ID) error {
 	return nil
 }
 
+func (s *StubService) Finish(_ job.ID, _ job.Result, _ time.Time) error {
+	return nil
+}
+
+func (s *StubService) Subscribe(_ job.ID) (<-chan job.LogEvent, func()) {
+	return nil, func() {}
+}
+
+func (s *StubService) Collect(_ job.Retention, _ time.Time, _ bool) ([]job.Garbage, error) {
+	return nil, nil
+}
+
 type ServiceImpl = serviceImpl
 
 func (s *ServiceImpl) SetRepo(repo job.Repository) (reset func()) {
@@ -31,3 +62,5 @@ func (s *ServiceImpl) SetRepo(repo job.Repository) (reset func()) {
 		s.repo = tmp
 	}
 }
+
+const BufferSize = bufferSize
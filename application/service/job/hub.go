@@ -0,0 +1,73 @@
+package job
+
+import (
+	"github.com/duck8823/duci/domain/model/job"
+	"sync"
+)
+
+// bufferSize is a number of events kept for a subscriber not receiving yet
+const bufferSize = 256
+
+// hub delivers events of jobs to subscribers in process. The zero value is ready to use.
+type hub struct {
+	mu   sync.Mutex
+	subs map[job.ID]map[chan job.LogEvent]struct{}
+}
+
+// subscribe returns a channel of events of the job, and a function to stop receiving.
+// The channel is closed if the subscriber is too slow to receive events.
+func (h *hub) subscribe(id job.ID) (<-chan job.LogEvent, func()) {
+	events := make(chan job.LogEvent, bufferSize)
+
+	h.mu.Lock()
+	if h.subs == nil {
+		h.subs = make(map[job.ID]map[chan job.LogEvent]struct{})
+	}
+	if h.subs[id] == nil {
+		h.subs[id] = make(map[chan job.LogEvent]struct{})
+	}
+	h.subs[id][events] = struct{}{}
+	h.mu.Unlock()
+
+	return events, func() {
+		h.mu.Lock()
+		h.remove(id, events)
+		h.mu.Unlock()
+	}
+}
+
+// publish sends the event to subscribers of the job without blocking
+func (h *hub) publish(id job.ID, event job.LogEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for events := range h.subs[id] {
+		select {
+		case events <- event:
+		default:
+			h.remove(id, events)
+		}
+	}
+}
+
+// remove closes the channel and stops delivering to it. It must be called with lock.
+func (h *hub) remove(id job.ID, events chan job.LogEvent) {
+	if _, ok := h.subs[id][events]; !ok {
+		return
+	}
+	delete(h.subs[id], events)
+	close(events)
+	if len(h.subs[id]) == 0 {
+		delete(h.subs, id)
+	}
+}
+
+// publishLine sends the log line with sequence number
+func (h *hub) publishLine(id job.ID, seq int, line job.LogLine) {
+	h.publish(id, job.LogEvent{Seq: seq, Line: line})
+}
+
+// publishFinished sends end of the job
+func (h *hub) publishFinished(id job.ID) {
+	h.publish(id, job.LogEvent{Finished: true})
+}
@@ -8,6 +8,7 @@ import (
 	job "github.com/duck8823/duci/domain/model/job"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockService is a mock of Service interface
@@ -46,16 +47,66 @@ func (mr *MockServiceMockRecorder) FindBy(id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBy", reflect.TypeOf((*MockService)(nil).FindBy), id)
 }
 
+// FindLogs mocks base method
+func (m *MockService) FindLogs(id job.ID, from, to int) ([]job.LogLine, error) {
+	ret := m.ctrl.Call(m, "FindLogs", id, from, to)
+	ret0, _ := ret[0].([]job.LogLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLogs indicates an expected call of FindLogs
+func (mr *MockServiceMockRecorder) FindLogs(id, from, to interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLogs", reflect.TypeOf((*MockService)(nil).FindLogs), id, from, to)
+}
+
+// ReverseLogs mocks base method
+func (m *MockService) ReverseLogs(id job.ID, from, to int, visit func(int, job.LogLine) bool) error {
+	ret := m.ctrl.Call(m, "ReverseLogs", id, from, to, visit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReverseLogs indicates an expected call of ReverseLogs
+func (mr *MockServiceMockRecorder) ReverseLogs(id, from, to, visit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseLogs", reflect.TypeOf((*MockService)(nil).ReverseLogs), id, from, to, visit)
+}
+
+// Search mocks base method
+func (m *MockService) Search(query job.Query) (*job.Page, error) {
+	ret := m.ctrl.Call(m, "Search", query)
+	ret0, _ := ret[0].(*job.Page)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search
+func (mr *MockServiceMockRecorder) Search(query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockService)(nil).Search), query)
+}
+
+// Queue mocks base method
+func (m *MockService) Queue(id job.ID, trigger job.Trigger, at time.Time) error {
+	ret := m.ctrl.Call(m, "Queue", id, trigger, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Queue indicates an expected call of Queue
+func (mr *MockServiceMockRecorder) Queue(id, trigger, at interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Queue", reflect.TypeOf((*MockService)(nil).Queue), id, trigger, at)
+}
+
 // Start mocks base method
-func (m *MockService) Start(id job.ID) error {
-	ret := m.ctrl.Call(m, "Start", id)
+func (m *MockService) Start(id job.ID, at time.Time) error {
+	ret := m.ctrl.Call(m, "Start", id, at)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Start indicates an expected call of Start
-func (mr *MockServiceMockRecorder) Start(id interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockService)(nil).Start), id)
+func (mr *MockServiceMockRecorder) Start(id, at interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockService)(nil).Start), id, at)
 }
 
 // Append mocks base method
@@ -70,14 +121,52 @@ func (mr *MockServiceMockRecorder) Append(id, line interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockService)(nil).Append), id, line)
 }
 
+// Truncate mocks base method
+func (m *MockService) Truncate(id job.ID) error {
+	ret := m.ctrl.Call(m, "Truncate", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Truncate indicates an expected call of Truncate
+func (mr *MockServiceMockRecorder) Truncate(id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Truncate", reflect.TypeOf((*MockService)(nil).Truncate), id)
+}
+
 // Finish mocks base method
-func (m *MockService) Finish(id job.ID) error {
-	ret := m.ctrl.Call(m, "Finish", id)
+func (m *MockService) Finish(id job.ID, result job.Result, at time.Time) error {
+	ret := m.ctrl.Call(m, "Finish", id, result, at)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Finish indicates an expected call of Finish
-func (mr *MockServiceMockRecorder) Finish(id interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockService)(nil).Finish), id)
+func (mr *MockServiceMockRecorder) Finish(id, result, at interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finish", reflect.TypeOf((*MockService)(nil).Finish), id, result, at)
+}
+
+// Subscribe mocks base method
+func (m *MockService) Subscribe(id job.ID) (<-chan job.LogEvent, func()) {
+	ret := m.ctrl.Call(m, "Subscribe", id)
+	ret0, _ := ret[0].(<-chan job.LogEvent)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe
+func (mr *MockServiceMockRecorder) Subscribe(id interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockService)(nil).Subscribe), id)
+}
+
+// Collect mocks base method
+func (m *MockService) Collect(retention job.Retention, now time.Time, dryRun bool) ([]job.Garbage, error) {
+	ret := m.ctrl.Call(m, "Collect", retention, now, dryRun)
+	ret0, _ := ret[0].([]job.Garbage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Collect indicates an expected call of Collect
+func (mr *MockServiceMockRecorder) Collect(retention, now, dryRun interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Collect", reflect.TypeOf((*MockService)(nil).Collect), retention, now, dryRun)
 }
@@ -0,0 +1,93 @@
+package job
+
+import (
+	"fmt"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// Collect removes finished jobs exceeding the retention and compacts the data source.
+// Jobs exceeding the max size are removed from the oldest one. With dry run, it only returns jobs to be removed.
+func (s *serviceImpl) Collect(retention job.Retention, now time.Time, dryRun bool) ([]job.Garbage, error) {
+	jobs, err := s.repo.All()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	garbage := []job.Garbage{}
+	var rest []job.Job
+	counts := make(map[string]int)
+	for _, j := range jobs {
+		repo := repositoryOf(j)
+		counts[repo]++
+		if !j.Finished {
+			continue
+		}
+
+		switch {
+		case retention.MaxAge > 0 && timeOf(j).Before(now.Add(-retention.MaxAge)):
+			garbage = append(garbage, job.Garbage{Job: j, Reason: fmt.Sprintf("older than %s", retention.MaxAge)})
+		case retention.MaxJobs > 0 && counts[repo] > retention.MaxJobs:
+			garbage = append(garbage, job.Garbage{Job: j, Reason: fmt.Sprintf("more than %d jobs in %s", retention.MaxJobs, repo)})
+		default:
+			rest = append(rest, j)
+		}
+	}
+
+	for i := range garbage {
+		if garbage[i].Size, err = s.repo.SizeOf(garbage[i].Job.ID); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	if retention.MaxSize > 0 {
+		total, err := s.repo.Size()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for _, g := range garbage {
+			total -= g.Size
+		}
+		for i := len(rest) - 1; i >= 0 && total > retention.MaxSize; i-- {
+			size, err := s.repo.SizeOf(rest[i].ID)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			garbage = append(garbage, job.Garbage{Job: rest[i], Size: size, Reason: fmt.Sprintf("database larger than %d bytes", retention.MaxSize)})
+			total -= size
+		}
+	}
+
+	if dryRun || len(garbage) == 0 {
+		return garbage, nil
+	}
+
+	for _, g := range garbage {
+		if err := s.repo.Delete(g.Job.ID); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	if err := s.repo.Compact(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return garbage, nil
+}
+
+func repositoryOf(j job.Job) string {
+	if j.Trigger == nil {
+		return ""
+	}
+	return j.Trigger.Repository
+}
+
+// timeOf returns the time the job finished, or queued if unknown
+func timeOf(j job.Job) time.Time {
+	if j.FinishedAt != nil {
+		return *j.FinishedAt
+	}
+	if j.QueuedAt != nil {
+		return *j.QueuedAt
+	}
+	return time.Time{}
+}
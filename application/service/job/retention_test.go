@@ -0,0 +1,284 @@
+package job_test
+
+import (
+	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/domain/model/job/mock_job"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"testing"
+	"time"
+)
+
+func TestServiceImpl_Collect(t *testing.T) {
+	// given
+	now := time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	newJob := func(repo string, daysAgo int, finished bool) job.Job {
+		at := now.Add(-time.Duration(daysAgo) * 24 * time.Hour)
+		j := job.Job{ID: job.ID(uuid.New())}
+		j.Queue(job.Trigger{Repository: repo}, at)
+		if finished {
+			j.End(job.Result{State: job.SUCCESS}, at)
+		}
+		return j
+	}
+
+	t.Run("with max age on dry run", func(t *testing.T) {
+		// given
+		recent := newJob("duck8823/duci", 1, true)
+		old := newJob("duck8823/duci", 10, true)
+		running := newJob("duck8823/duci", 20, false)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			All().
+			Times(1).
+			Return([]job.Job{recent, old, running}, nil)
+		repo.EXPECT().
+			SizeOf(gomock.Eq(old.ID)).
+			Times(1).
+			Return(int64(100), nil)
+		repo.EXPECT().
+			Delete(gomock.Any()).
+			Times(0)
+		repo.EXPECT().
+			Compact().
+			Times(0)
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// when
+		got, err := sut.Collect(job.Retention{MaxAge: 7 * 24 * time.Hour}, now, true)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		want := []job.Garbage{{Job: old, Size: 100, Reason: "older than 168h0m0s"}}
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("with max jobs", func(t *testing.T) {
+		// given
+		first := newJob("duck8823/duci", 1, true)
+		other := newJob("duck8823/other", 2, true)
+		second := newJob("duck8823/duci", 3, true)
+		third := newJob("duck8823/duci", 4, true)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			All().
+			Times(1).
+			Return([]job.Job{first, other, second, third}, nil)
+		repo.EXPECT().
+			SizeOf(gomock.Eq(third.ID)).
+			Times(1).
+			Return(int64(100), nil)
+		gomock.InOrder(
+			repo.EXPECT().
+				Delete(gomock.Eq(third.ID)).
+				Times(1).
+				Return(nil),
+			repo.EXPECT().
+				Compact().
+				Times(1).
+				Return(nil),
+		)
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// when
+		got, err := sut.Collect(job.Retention{MaxJobs: 2}, now, false)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		want := []job.Garbage{{Job: third, Size: 100, Reason: "more than 2 jobs in duck8823/duci"}}
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("with max size", func(t *testing.T) {
+		// given
+		first := newJob("duck8823/duci", 1, true)
+		second := newJob("duck8823/duci", 2, true)
+		third := newJob("duck8823/duci", 3, true)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			All().
+			Times(1).
+			Return([]job.Job{first, second, third}, nil)
+		repo.EXPECT().
+			Size().
+			Times(1).
+			Return(int64(300), nil)
+		repo.EXPECT().
+			SizeOf(gomock.Any()).
+			Times(2).
+			Return(int64(100), nil)
+		repo.EXPECT().
+			Delete(gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// when
+		got, err := sut.Collect(job.Retention{MaxSize: 150}, now, true)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		want := []job.Garbage{
+			{Job: third, Size: 100, Reason: "database larger than 150 bytes"},
+			{Job: second, Size: 100, Reason: "database larger than 150 bytes"},
+		}
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("when nothing to remove", func(t *testing.T) {
+		// given
+		recent := newJob("duck8823/duci", 1, true)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			All().
+			Times(1).
+			Return([]job.Job{recent}, nil)
+		repo.EXPECT().
+			Compact().
+			Times(0)
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// when
+		got, err := sut.Collect(job.Retention{MaxAge: 7 * 24 * time.Hour}, now, false)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if len(got) != 0 {
+			t.Errorf("must be empty, but got %+v", got)
+		}
+	})
+
+	t.Run("with job stored by older version", func(t *testing.T) {
+		// given
+		recent := newJob("duck8823/duci", 1, true)
+		legacy := job.Job{ID: job.ID(uuid.New()), Finished: true}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			All().
+			Times(1).
+			Return([]job.Job{recent, legacy}, nil)
+		repo.EXPECT().
+			SizeOf(gomock.Eq(legacy.ID)).
+			Times(1).
+			Return(int64(100), nil)
+		gomock.InOrder(
+			repo.EXPECT().
+				Delete(gomock.Eq(legacy.ID)).
+				Times(1).
+				Return(nil),
+			repo.EXPECT().
+				Compact().
+				Times(1).
+				Return(nil),
+		)
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// when
+		got, err := sut.Collect(job.Retention{MaxAge: 7 * 24 * time.Hour}, now, false)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		want := []job.Garbage{{Job: legacy, Size: 100, Reason: "older than 168h0m0s"}}
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("when repo returns error", func(t *testing.T) {
+		// given
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			All().
+			Times(1).
+			Return(nil, errors.New("test error"))
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// when
+		got, err := sut.Collect(job.Retention{MaxAge: time.Hour}, now, false)
+
+		// then
+		if err == nil {
+			t.Error("error must not be nil")
+		}
+
+		// and
+		if got != nil {
+			t.Errorf("must be nil, but got %+v", got)
+		}
+	})
+}
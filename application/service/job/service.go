@@ -1,11 +1,21 @@
 package job
 
-import "github.com/duck8823/duci/domain/model/job"
+import (
+	"github.com/duck8823/duci/domain/model/job"
+	"time"
+)
 
 // Service represents job service
 type Service interface {
 	FindBy(id job.ID) (*job.Job, error)
-	Start(id job.ID) error
+	FindLogs(id job.ID, from int, to int) ([]job.LogLine, error)
+	ReverseLogs(id job.ID, from int, to int, visit func(seq int, line job.LogLine) bool) error
+	Search(query job.Query) (*job.Page, error)
+	Queue(id job.ID, trigger job.Trigger, at time.Time) error
+	Start(id job.ID, at time.Time) error
 	Append(id job.ID, line job.LogLine) error
-	Finish(id job.ID) error
+	Truncate(id job.ID) error
+	Finish(id job.ID, result job.Result, at time.Time) error
+	Subscribe(id job.ID) (events <-chan job.LogEvent, unsubscribe func())
+	Collect(retention job.Retention, now time.Time, dryRun bool) ([]job.Garbage, error)
 }
@@ -5,10 +5,12 @@ import (
 	jobDataSource "github.com/duck8823/duci/infrastructure/job"
 	"github.com/duck8823/duci/internal/container"
 	"github.com/pkg/errors"
+	"time"
 )
 
 type serviceImpl struct {
 	repo job.Repository
+	hub  hub
 }
 
 // Initialize implementation of job service
@@ -44,27 +46,84 @@ func (s *serviceImpl) FindBy(id job.ID) (*job.Job, error) {
 	return job, nil
 }
 
-// Start store empty job
-func (s *serviceImpl) Start(id job.ID) error {
-	job := job.Job{ID: id, Finished: false}
-	if err := s.repo.Save(job); err != nil {
+// FindLogs returns log lines of the job in the range of sequence number.
+// Negative end means the last of lines.
+func (s *serviceImpl) FindLogs(id job.ID, from int, to int) ([]job.LogLine, error) {
+	lines, err := s.repo.FindLogs(id, from, to)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return lines, nil
+}
+
+// ReverseLogs visits log lines of the job from the end of the range, until visit returns false.
+// Negative end means the last of lines.
+func (s *serviceImpl) ReverseLogs(id job.ID, from int, to int, visit func(seq int, line job.LogLine) bool) error {
+	if err := s.repo.ReverseLogs(id, from, to, visit); err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
 }
 
-// Append log to job
-func (s *serviceImpl) Append(id job.ID, line job.LogLine) error {
+// Search returns jobs matching the query
+func (s *serviceImpl) Search(query job.Query) (*job.Page, error) {
+	page, err := s.repo.Search(query)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return page, nil
+}
+
+// Queue store empty job with trigger.
+// A job resumed after restart keeps the time queued at first.
+func (s *serviceImpl) Queue(id job.ID, trigger job.Trigger, at time.Time) error {
 	job, err := s.findOrInitialize(id)
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	job.AppendLog(line)
+	if job.QueuedAt != nil {
+		at = *job.QueuedAt
+	}
+	job.Queue(trigger, at)
+	if err := s.repo.Save(*job); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
 
+// Start store running job
+func (s *serviceImpl) Start(id job.ID, at time.Time) error {
+	job, err := s.findOrInitialize(id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	job.Start(at)
 	if err := s.repo.Save(*job); err != nil {
 		return errors.WithStack(err)
 	}
+	return nil
+}
 
+// Append log to job
+func (s *serviceImpl) Append(id job.ID, line job.LogLine) error {
+	seq, err := s.repo.AppendLog(id, line)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	s.hub.publishLine(id, seq, line)
+	return nil
+}
+
+// Truncate marks the job that its log was truncated
+func (s *serviceImpl) Truncate(id job.ID) error {
+	job, err := s.repo.FindBy(id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	job.Truncate()
+	if err := s.repo.Save(*job); err != nil {
+		return errors.WithStack(err)
+	}
 	return nil
 }
 
@@ -79,17 +138,21 @@ func (s *serviceImpl) findOrInitialize(id job.ID) (*job.Job, error) {
 	return j, nil
 }
 
-// Finish store finished job
-func (s *serviceImpl) Finish(id job.ID) error {
+// Finish store finished job with the result
+func (s *serviceImpl) Finish(id job.ID, result job.Result, at time.Time) error {
 	job, err := s.repo.FindBy(id)
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	job.Finish()
-
+	job.End(result, at)
 	if err := s.repo.Save(*job); err != nil {
 		return errors.WithStack(err)
 	}
-
+	s.hub.publishFinished(id)
 	return nil
 }
+
+// Subscribe returns a channel of log lines appended after subscribing and end of the job
+func (s *serviceImpl) Subscribe(id job.ID) (<-chan job.LogEvent, func()) {
+	return s.hub.subscribe(id)
+}
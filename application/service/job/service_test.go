@@ -103,12 +103,6 @@ func TestServiceImpl_FindBy(t *testing.T) {
 		want := &job.Job{
 			ID:       id,
 			Finished: true,
-			Stream: []job.LogLine{
-				{
-					Timestamp: time.Now(),
-					Message:   "hello world",
-				},
-			},
 		}
 
 		// and
@@ -172,10 +166,78 @@ func TestServiceImpl_FindBy(t *testing.T) {
 	})
 }
 
-func TestServiceImpl_Start(t *testing.T) {
+func TestServiceImpl_Search(t *testing.T) {
+	t.Run("when repo returns page", func(t *testing.T) {
+		// given
+		query := job.Query{Repository: "duck8823/duci", Limit: 10}
+
+		// and
+		want := &job.Page{Jobs: []job.Job{{ID: job.ID(uuid.New())}}, Next: "next"}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			Search(gomock.Eq(query)).
+			Times(1).
+			Return(want, nil)
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// when
+		got, err := sut.Search(query)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("when repo returns error", func(t *testing.T) {
+		// given
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			Search(gomock.Any()).
+			Times(1).
+			Return(nil, errors.New("test error"))
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// when
+		got, err := sut.Search(job.Query{})
+
+		// then
+		if err == nil {
+			t.Error("error must not be nil")
+		}
+
+		// and
+		if got != nil {
+			t.Errorf("must be nil, but got %+v", got)
+		}
+	})
+}
+
+func TestServiceImpl_Queue(t *testing.T) {
 	t.Run("when repo returns nil", func(t *testing.T) {
 		// given
 		id := job.ID(uuid.New())
+		trigger := job.Trigger{Repository: "duck8823/duci", Event: "push", TaskName: "duci/push"}
+		at := time.Unix(10, 0)
 
 		// and
 		ctrl := gomock.NewController(t)
@@ -183,7 +245,11 @@ func TestServiceImpl_Start(t *testing.T) {
 
 		repo := mock_job.NewMockRepository(ctrl)
 		repo.EXPECT().
-			Save(gomock.Eq(job.Job{ID: id, Finished: false})).
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(nil, job.ErrNotFound)
+		repo.EXPECT().
+			Save(gomock.Eq(job.Job{ID: id, Trigger: &trigger, State: job.QUEUED, QueuedAt: &at, Finished: false})).
 			Times(1).
 			Return(nil)
 
@@ -192,7 +258,7 @@ func TestServiceImpl_Start(t *testing.T) {
 		defer sut.SetRepo(repo)()
 
 		// when
-		err := sut.Start(id)
+		err := sut.Queue(id, trigger, at)
 
 		// then
 		if err != nil {
@@ -210,7 +276,11 @@ func TestServiceImpl_Start(t *testing.T) {
 
 		repo := mock_job.NewMockRepository(ctrl)
 		repo.EXPECT().
-			Save(gomock.Eq(job.Job{ID: id, Finished: false})).
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(nil, job.ErrNotFound)
+		repo.EXPECT().
+			Save(gomock.Any()).
 			Times(1).
 			Return(errors.New("test error"))
 
@@ -219,20 +289,50 @@ func TestServiceImpl_Start(t *testing.T) {
 		defer sut.SetRepo(repo)()
 
 		// when
-		err := sut.Start(id)
+		err := sut.Queue(id, job.Trigger{}, time.Now())
 
 		// then
 		if err == nil {
 			t.Error("error must not be nil")
 		}
 	})
-}
+	t.Run("when job is resumed", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+		trigger := job.Trigger{Repository: "duck8823/duci", Event: "push", TaskName: "duci/push"}
+		queuedAt := time.Unix(10, 0)
+		at := time.Unix(20, 0)
 
-func TestServiceImpl_Append(t *testing.T) {
-	t.Run("when failure find job", func(t *testing.T) {
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(&job.Job{ID: id, Trigger: &trigger, State: job.QUEUED, QueuedAt: &queuedAt}, nil)
+		repo.EXPECT().
+			Save(gomock.Eq(job.Job{ID: id, Trigger: &trigger, State: job.QUEUED, QueuedAt: &queuedAt, Finished: false})).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// when
+		err := sut.Queue(id, trigger, at)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("when failed to find job", func(t *testing.T) {
 		// given
 		id := job.ID(uuid.New())
-		line := job.LogLine{Timestamp: time.Now(), Message: "Hello Test"}
 
 		// and
 		ctrl := gomock.NewController(t)
@@ -243,24 +343,30 @@ func TestServiceImpl_Append(t *testing.T) {
 			FindBy(gomock.Eq(id)).
 			Times(1).
 			Return(nil, errors.New("test error"))
+		repo.EXPECT().
+			Save(gomock.Any()).
+			Times(0)
 
 		// and
 		sut := &jobService.ServiceImpl{}
 		defer sut.SetRepo(repo)()
 
 		// when
-		err := sut.Append(id, line)
+		err := sut.Queue(id, job.Trigger{}, time.Now())
 
 		// then
 		if err == nil {
 			t.Error("error must not be nil")
 		}
 	})
+}
 
-	t.Run("when failure save job", func(t *testing.T) {
+func TestServiceImpl_Start(t *testing.T) {
+	t.Run("when repo returns nil", func(t *testing.T) {
 		// given
 		id := job.ID(uuid.New())
-		line := job.LogLine{Timestamp: time.Now(), Message: "Hello Test"}
+		queuedAt := time.Unix(10, 0)
+		at := time.Unix(20, 0)
 
 		// and
 		ctrl := gomock.NewController(t)
@@ -270,18 +376,48 @@ func TestServiceImpl_Append(t *testing.T) {
 		repo.EXPECT().
 			FindBy(gomock.Eq(id)).
 			Times(1).
-			Return(&job.Job{ID: id, Finished: false}, nil)
+			Return(&job.Job{ID: id, State: job.QUEUED, QueuedAt: &queuedAt}, nil)
 		repo.EXPECT().
-			Save(gomock.Eq(job.Job{ID: id, Finished: false, Stream: []job.LogLine{line}})).
+			Save(gomock.Eq(job.Job{ID: id, State: job.RUNNING, QueuedAt: &queuedAt, StartedAt: &at})).
 			Times(1).
-			Return(errors.New("test error"))
+			Return(nil)
 
 		// and
 		sut := &jobService.ServiceImpl{}
 		defer sut.SetRepo(repo)()
 
 		// when
-		err := sut.Append(id, line)
+		err := sut.Start(id, at)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("when find job, returns error", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(nil, errors.New("test error"))
+		repo.EXPECT().
+			Save(gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// when
+		err := sut.Start(id, time.Now())
 
 		// then
 		if err == nil {
@@ -289,10 +425,9 @@ func TestServiceImpl_Append(t *testing.T) {
 		}
 	})
 
-	t.Run("when job not found", func(t *testing.T) {
+	t.Run("when repo returns error", func(t *testing.T) {
 		// given
 		id := job.ID(uuid.New())
-		line := job.LogLine{Timestamp: time.Now(), Message: "Hello Test"}
 
 		// and
 		ctrl := gomock.NewController(t)
@@ -304,7 +439,7 @@ func TestServiceImpl_Append(t *testing.T) {
 			Times(1).
 			Return(nil, job.ErrNotFound)
 		repo.EXPECT().
-			Save(gomock.Eq(job.Job{ID: id, Finished: false, Stream: []job.LogLine{line}})).
+			Save(gomock.Any()).
 			Times(1).
 			Return(errors.New("test error"))
 
@@ -312,6 +447,105 @@ func TestServiceImpl_Append(t *testing.T) {
 		sut := &jobService.ServiceImpl{}
 		defer sut.SetRepo(repo)()
 
+		// when
+		err := sut.Start(id, time.Now())
+
+		// then
+		if err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+}
+
+func TestServiceImpl_FindLogs(t *testing.T) {
+	t.Run("when repo returns lines", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+
+		// and
+		want := []job.LogLine{{Timestamp: time.Now(), Message: "Hello Test"}}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			FindLogs(gomock.Eq(id), gomock.Eq(5000), gomock.Eq(6000)).
+			Times(1).
+			Return(want, nil)
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// when
+		got, err := sut.FindLogs(id, 5000, 6000)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("when repo returns error", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			FindLogs(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(nil, errors.New("test error"))
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// when
+		got, err := sut.FindLogs(id, 0, -1)
+
+		// then
+		if err == nil {
+			t.Error("error must not be nil")
+		}
+
+		// and
+		if got != nil {
+			t.Errorf("must be nil, but got %+v", got)
+		}
+	})
+}
+
+func TestServiceImpl_Append(t *testing.T) {
+	t.Run("when failure append log", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+		line := job.LogLine{Timestamp: time.Now(), Message: "Hello Test"}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			AppendLog(gomock.Eq(id), gomock.Eq(line)).
+			Times(1).
+			Return(0, errors.New("test error"))
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
 		// when
 		err := sut.Append(id, line)
 
@@ -327,8 +561,41 @@ func TestServiceImpl_Append(t *testing.T) {
 		line := job.LogLine{Timestamp: time.Now(), Message: "Hello Test"}
 
 		// and
-		stored := job.LogLine{Timestamp: time.Now(), Message: "Stored line"}
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			AppendLog(gomock.Eq(id), gomock.Eq(line)).
+			Times(1).
+			Return(1, nil)
+		repo.EXPECT().
+			FindBy(gomock.Any()).
+			Times(0)
+		repo.EXPECT().
+			Save(gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// when
+		err := sut.Append(id, line)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+}
 
+func TestServiceImpl_Truncate(t *testing.T) {
+	t.Run("when repo returns nil", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+
+		// and
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
@@ -336,9 +603,9 @@ func TestServiceImpl_Append(t *testing.T) {
 		repo.EXPECT().
 			FindBy(gomock.Eq(id)).
 			Times(1).
-			Return(&job.Job{ID: id, Finished: false, Stream: []job.LogLine{stored}}, nil)
+			Return(&job.Job{ID: id, State: job.RUNNING}, nil)
 		repo.EXPECT().
-			Save(gomock.Eq(job.Job{ID: id, Finished: false, Stream: []job.LogLine{stored, line}})).
+			Save(gomock.Eq(job.Job{ID: id, State: job.RUNNING, Truncated: true})).
 			Times(1).
 			Return(nil)
 
@@ -347,19 +614,52 @@ func TestServiceImpl_Append(t *testing.T) {
 		defer sut.SetRepo(repo)()
 
 		// when
-		err := sut.Append(id, line)
+		err := sut.Truncate(id)
 
 		// then
 		if err != nil {
 			t.Errorf("error must be nil, but got %+v", err)
 		}
 	})
+
+	t.Run("when find job, returns error", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(nil, errors.New("test error"))
+		repo.EXPECT().
+			Save(gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// when
+		err := sut.Truncate(id)
+
+		// then
+		if err == nil {
+			t.Error("error must not be nil")
+		}
+	})
 }
 
 func TestServiceImpl_Finish(t *testing.T) {
 	t.Run("when find job, returns error", func(t *testing.T) {
 		// given
 		id := job.ID(uuid.New())
+		code := int64(1)
+		result := job.Result{State: job.FAILURE, ExitCode: &code}
+		at := time.Unix(30, 0)
 
 		// and
 		ctrl := gomock.NewController(t)
@@ -376,7 +676,7 @@ func TestServiceImpl_Finish(t *testing.T) {
 		defer sut.SetRepo(repo)()
 
 		// when
-		err := sut.Finish(id)
+		err := sut.Finish(id, result, at)
 
 		// then
 		if err == nil {
@@ -387,6 +687,9 @@ func TestServiceImpl_Finish(t *testing.T) {
 	t.Run("when save, returns error", func(t *testing.T) {
 		// given
 		id := job.ID(uuid.New())
+		code := int64(1)
+		result := job.Result{State: job.FAILURE, ExitCode: &code}
+		at := time.Unix(30, 0)
 
 		// and
 		ctrl := gomock.NewController(t)
@@ -398,7 +701,7 @@ func TestServiceImpl_Finish(t *testing.T) {
 			Times(1).
 			Return(&job.Job{ID: id, Finished: false}, nil)
 		repo.EXPECT().
-			Save(gomock.Eq(job.Job{ID: id, Finished: true})).
+			Save(gomock.Eq(job.Job{ID: id, State: job.FAILURE, ExitCode: &code, FinishedAt: &at, Finished: true})).
 			Times(1).
 			Return(errors.New("test error"))
 
@@ -407,7 +710,7 @@ func TestServiceImpl_Finish(t *testing.T) {
 		defer sut.SetRepo(repo)()
 
 		// when
-		err := sut.Finish(id)
+		err := sut.Finish(id, result, at)
 
 		// then
 		if err == nil {
@@ -418,6 +721,9 @@ func TestServiceImpl_Finish(t *testing.T) {
 	t.Run("without any error", func(t *testing.T) {
 		// given
 		id := job.ID(uuid.New())
+		code := int64(1)
+		result := job.Result{State: job.FAILURE, ExitCode: &code}
+		at := time.Unix(30, 0)
 
 		// and
 		ctrl := gomock.NewController(t)
@@ -429,7 +735,7 @@ func TestServiceImpl_Finish(t *testing.T) {
 			Times(1).
 			Return(&job.Job{ID: id, Finished: false}, nil)
 		repo.EXPECT().
-			Save(gomock.Eq(job.Job{ID: id, Finished: true})).
+			Save(gomock.Eq(job.Job{ID: id, State: job.FAILURE, ExitCode: &code, FinishedAt: &at, Finished: true})).
 			Return(nil)
 
 		// and
@@ -437,7 +743,7 @@ func TestServiceImpl_Finish(t *testing.T) {
 		defer sut.SetRepo(repo)()
 
 		// when
-		err := sut.Finish(id)
+		err := sut.Finish(id, result, at)
 
 		// then
 		if err != nil {
@@ -445,3 +751,118 @@ func TestServiceImpl_Finish(t *testing.T) {
 		}
 	})
 }
+
+func TestServiceImpl_Subscribe(t *testing.T) {
+	t.Run("with appended line and finish", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+		line := job.LogLine{Timestamp: time.Unix(10, 0), Message: "Hello Test"}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			AppendLog(gomock.Eq(id), gomock.Eq(line)).
+			Times(1).
+			Return(1, nil)
+		repo.EXPECT().
+			FindBy(gomock.Eq(id)).
+			AnyTimes().
+			Return(&job.Job{ID: id}, nil)
+		repo.EXPECT().
+			Save(gomock.Any()).
+			AnyTimes().
+			Return(nil)
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// and
+		events, unsubscribe := sut.Subscribe(id)
+		defer unsubscribe()
+
+		// when
+		_ = sut.Append(id, line)
+		_ = sut.Finish(id, job.Result{State: job.SUCCESS}, time.Now())
+
+		// then
+		want := []job.LogEvent{{Seq: 1, Line: line}, {Finished: true}}
+		for _, w := range want {
+			got := <-events
+			if !cmp.Equal(got, w) {
+				t.Errorf("must be equal, but %+v", cmp.Diff(got, w))
+			}
+		}
+	})
+
+	t.Run("when subscriber is too slow", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			AppendLog(gomock.Eq(id), gomock.Any()).
+			AnyTimes().
+			Return(0, nil)
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// and
+		events, unsubscribe := sut.Subscribe(id)
+		defer unsubscribe()
+
+		// when
+		for i := 0; i <= jobService.BufferSize; i++ {
+			_ = sut.Append(id, job.LogLine{Message: "Hello Test"})
+		}
+
+		// then
+		var received int
+		for range events {
+			received++
+		}
+		if received != jobService.BufferSize {
+			t.Errorf("must receive %d events before closed, but got %d", jobService.BufferSize, received)
+		}
+	})
+
+	t.Run("after unsubscribe", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		repo := mock_job.NewMockRepository(ctrl)
+		repo.EXPECT().
+			AppendLog(gomock.Eq(id), gomock.Any()).
+			AnyTimes().
+			Return(0, nil)
+
+		// and
+		sut := &jobService.ServiceImpl{}
+		defer sut.SetRepo(repo)()
+
+		// and
+		events, unsubscribe := sut.Subscribe(id)
+
+		// when
+		unsubscribe()
+		_ = sut.Append(id, job.LogLine{Message: "Hello Test"})
+
+		// then
+		if _, ok := <-events; ok {
+			t.Error("channel must be closed")
+		}
+	})
+}
@@ -11,7 +11,13 @@ type Docker interface {
 	Build(ctx context.Context, file io.Reader, tag Tag, dockerfile Dockerfile) (job.Log, error)
 	Run(ctx context.Context, opts RuntimeOptions, tag Tag, cmd Command) (ContainerID, job.Log, error)
 	RemoveContainer(ctx context.Context, containerID ContainerID) error
+	RemoveContainers(ctx context.Context, tag Tag) error
 	RemoveImage(ctx context.Context, tag Tag) error
 	ExitCode(ctx context.Context, containerID ContainerID) (ExitCode, error)
+	CreateNetwork(ctx context.Context, network Network, tag Tag) error
+	RemoveNetwork(ctx context.Context, network Network) error
+	RemoveNetworks(ctx context.Context, tag Tag) error
+	StartService(ctx context.Context, network Network, alias string, service Service, resources Resources, tag Tag) (ContainerID, error)
+	Health(ctx context.Context, containerID ContainerID) (Health, error)
 	Status() error
 }
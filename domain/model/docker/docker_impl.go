@@ -1,17 +1,18 @@
 package docker
 
 import (
-	"bytes"
 	"context"
+	"fmt"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
 	moby "github.com/docker/docker/client"
 	"github.com/duck8823/duci/domain/model/job"
 	"github.com/moby/buildkit/frontend/dockerfile/command"
 	"github.com/moby/buildkit/frontend/dockerfile/parser"
 	"github.com/pkg/errors"
 	"io"
-	"io/ioutil"
 	"os"
 	"strings"
 )
@@ -47,16 +48,10 @@ func (c *dockerImpl) Build(ctx context.Context, file io.Reader, tag Tag, dockerf
 		return nil, errors.WithStack(err)
 	}
 
-	// For waiting build
-	log, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-
-	return NewBuildLog(bytes.NewReader(log)), nil
+	return NewBuildLog(resp.Body), nil
 }
 
-// BuildArgs returns build args with host environment values
+// BuildArgs returns build args with values in the dockerfile or host environment
 func BuildArgs(dockerfile Dockerfile) (map[string]*string, error) {
 	args := map[string]*string{}
 
@@ -75,6 +70,10 @@ func BuildArgs(dockerfile Dockerfile) (map[string]*string, error) {
 			continue
 		}
 		key := strings.Split(node.Next.Value, "=")[0]
+		if value, ok := dockerfile.Args[key]; ok {
+			args[key] = &value
+			continue
+		}
 		hostEnv := os.Getenv(key)
 		if hostEnv == "" {
 			continue
@@ -93,8 +92,14 @@ func (c *dockerImpl) Run(ctx context.Context, opts RuntimeOptions, tag Tag, cmd
 		Env:     opts.Environments.Array(),
 		Volumes: opts.Volumes.Map(),
 		Cmd:     cmd.Slice(),
+		User:    opts.Resources.User,
+		Labels:  tag.Labels(),
 	}, &container.HostConfig{
-		Binds: opts.Volumes,
+		Binds:          opts.Volumes,
+		Resources:      limitsOf(opts.Resources),
+		NetworkMode:    container.NetworkMode(opts.Resources.NetworkMode),
+		ReadonlyRootfs: opts.Resources.IsReadOnly(),
+		CapDrop:        opts.Resources.CapDrop,
 	}, nil, "")
 	if err != nil {
 		return "", nil, errors.WithStack(err)
@@ -108,6 +113,7 @@ func (c *dockerImpl) Run(ctx context.Context, opts RuntimeOptions, tag Tag, cmd
 		ShowStdout: true,
 		ShowStderr: true,
 		Follow:     true,
+		Timestamps: true,
 	})
 	if err != nil {
 		return ContainerID(con.ID), nil, errors.WithStack(err)
@@ -116,11 +122,28 @@ func (c *dockerImpl) Run(ctx context.Context, opts RuntimeOptions, tag Tag, cmd
 	return ContainerID(con.ID), NewRunLog(logs), nil
 }
 
-// RemoveContainer remove docker container.
+// RemoveContainer stop and remove docker container.
 func (c *dockerImpl) RemoveContainer(ctx context.Context, conID ContainerID) error {
-	if err := c.moby.ContainerRemove(ctx, conID.String(), types.ContainerRemoveOptions{}); err != nil {
+	if err := c.moby.ContainerRemove(ctx, conID.String(), types.ContainerRemoveOptions{Force: true}); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// RemoveContainers stop and remove all docker containers labelled with the tag, including services.
+func (c *dockerImpl) RemoveContainers(ctx context.Context, tag Tag) error {
+	containers, err := c.moby.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: labelFilter(tag),
+	})
+	if err != nil {
 		return errors.WithStack(err)
 	}
+	for _, con := range containers {
+		if err := c.RemoveContainer(ctx, ContainerID(con.ID)); err != nil {
+			return errors.WithStack(err)
+		}
+	}
 	return nil
 }
 
@@ -143,6 +166,120 @@ func (c *dockerImpl) ExitCode(ctx context.Context, conID ContainerID) (ExitCode,
 	}
 }
 
+// CreateNetwork creates a user-defined bridge network labelled with the tag.
+func (c *dockerImpl) CreateNetwork(ctx context.Context, name Network, tag Tag) error {
+	if _, err := c.moby.NetworkCreate(ctx, name.String(), types.NetworkCreate{
+		CheckDuplicate: true,
+		Driver:         "bridge",
+		Labels:         tag.Labels(),
+	}); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// RemoveNetwork removes a network.
+func (c *dockerImpl) RemoveNetwork(ctx context.Context, name Network) error {
+	if err := c.moby.NetworkRemove(ctx, name.String()); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// RemoveNetworks removes all networks labelled with the tag.
+// Containers in the networks must be removed before.
+func (c *dockerImpl) RemoveNetworks(ctx context.Context, tag Tag) error {
+	networks, err := c.moby.NetworkList(ctx, types.NetworkListOptions{Filters: labelFilter(tag)})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, net := range networks {
+		if err := c.RemoveNetwork(ctx, Network(net.Name)); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// labelFilter returns a filter of containers and networks labelled with the tag.
+func labelFilter(tag Tag) filters.Args {
+	return filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=%s", JobLabel, tag)))
+}
+
+// StartService pulls the image and starts a container of service labelled with the tag, which is reachable with the alias in the network.
+// The service is limited by cpu, memory and pids same as the task container,
+// but the user, the root filesystem and capabilities are left to the image because services such as databases need them.
+func (c *dockerImpl) StartService(ctx context.Context, net Network, alias string, service Service, resources Resources, tag Tag) (ContainerID, error) {
+	if err := c.pull(ctx, service.Image); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	con, err := c.moby.ContainerCreate(ctx, &container.Config{
+		Image:       service.Image,
+		Env:         service.Environments.Array(),
+		Cmd:         service.Command.Slice(),
+		Healthcheck: service.Healthcheck.Config(),
+		Labels:      tag.Labels(),
+	}, &container.HostConfig{
+		Resources:   limitsOf(resources),
+		NetworkMode: container.NetworkMode(net.String()),
+	}, &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			net.String(): {Aliases: []string{alias}},
+		},
+	}, "")
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	if err := c.moby.ContainerStart(ctx, con.ID, types.ContainerStartOptions{}); err != nil {
+		return ContainerID(con.ID), errors.WithStack(err)
+	}
+	return ContainerID(con.ID), nil
+}
+
+// limitsOf returns limits of cpu, memory and pids in the resources.
+func limitsOf(resources Resources) container.Resources {
+	return container.Resources{
+		NanoCPUs:   resources.NanoCPUs(),
+		Memory:     int64(resources.Memory),
+		MemorySwap: int64(resources.MemorySwap),
+		PidsLimit:  resources.PidsLimit,
+	}
+}
+
+// pull an image and wait for completion.
+func (c *dockerImpl) pull(ctx context.Context, image string) error {
+	resp, err := c.moby.ImagePull(ctx, image, types.ImagePullOptions{})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	log := NewBuildLog(resp)
+	for {
+		if _, err := log.ReadLine(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+}
+
+// Health returns health status of container. It returns error if the container is not running.
+func (c *dockerImpl) Health(ctx context.Context, conID ContainerID) (Health, error) {
+	info, err := c.moby.ContainerInspect(ctx, conID.String())
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if info.ContainerJSONBase == nil || info.State == nil || !info.State.Running {
+		return "", errors.Errorf("container %s is not running", conID)
+	}
+	if info.State.Health == nil {
+		return NoHealthcheck, nil
+	}
+	return Health(info.State.Health.Status), nil
+}
+
 // Status returns error of docker daemon status.
 func (c *dockerImpl) Status() error {
 	if _, err := c.moby.Info(context.Background()); err != nil {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/duck8823/duci/domain/model/docker"
 	"github.com/duck8823/duci/domain/model/docker/mock_docker"
 	. "github.com/golang/mock/gomock"
@@ -206,13 +207,13 @@ func TestClient_Build(t *testing.T) {
 		got, err := sut.Build(ctx, buildContext, docker.Tag(tag), docker.Dockerfile{Dir: ".", Path: dockerfile})
 
 		// then
-		if err == nil {
-			t.Error("error must not be nil")
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
 		}
 
 		// and
-		if got != nil {
-			t.Errorf("log moust be nil, but got %+v", err)
+		if _, err := got.ReadLine(); err == nil {
+			t.Error("error must not be nil")
 		}
 	})
 
@@ -280,6 +281,7 @@ func TestClient_Run(t *testing.T) {
 				ShowStdout: true,
 				ShowStderr: true,
 				Follow:     true,
+				Timestamps: true,
 			})).
 			Times(1).
 			Return(
@@ -313,6 +315,62 @@ func TestClient_Run(t *testing.T) {
 		}
 	})
 
+	t.Run("with resources", func(t *testing.T) {
+		// given
+		ctrl := NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		ctx := context.Background()
+		readOnly := true
+		opts := docker.RuntimeOptions{
+			Resources: docker.Resources{
+				CPUs:        1.5,
+				Memory:      1024,
+				MemorySwap:  2048,
+				PidsLimit:   100,
+				NetworkMode: "none",
+				ReadOnly:    &readOnly,
+				CapDrop:     []string{"ALL"},
+				User:        "nobody",
+			},
+		}
+		tag := docker.Tag("test_tag")
+		cmd := docker.Command{"echo", "test"}
+
+		// and
+		mockMoby := mock_docker.NewMockMoby(ctrl)
+		mockMoby.EXPECT().
+			ContainerCreate(Eq(ctx), Eq(&container.Config{
+				Image:   tag.String(),
+				Volumes: map[string]struct{}{},
+				Cmd:     cmd.Slice(),
+				User:    "nobody",
+				Labels:  map[string]string{"duci.job": "test_tag"},
+			}), Eq(&container.HostConfig{
+				Resources: container.Resources{
+					NanoCPUs:   1500000000,
+					Memory:     1024,
+					MemorySwap: 2048,
+					PidsLimit:  100,
+				},
+				NetworkMode:    "none",
+				ReadonlyRootfs: true,
+				CapDrop:        []string{"ALL"},
+			}), Nil(), Eq("")).
+			Times(1).
+			Return(container.ContainerCreateCreatedBody{}, errors.New("test error"))
+
+		// and
+		sut := &docker.Client{}
+		defer sut.SetMoby(mockMoby)()
+
+		// expect
+		if _, _, err := sut.Run(ctx, opts, tag, cmd); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+
 	t.Run("non-nominal scenarios", func(t *testing.T) {
 		// where
 		for _, tt := range []struct {
@@ -464,6 +522,7 @@ func mockMobyFailedContainerLogs(
 			ShowStdout: true,
 			ShowStderr: true,
 			Follow:     true,
+			Timestamps: true,
 		})).
 		Times(1).
 		Return(nil, errors.New("test error"))
@@ -485,7 +544,7 @@ func TestClient_RemoveContainer(t *testing.T) {
 
 		mockMoby := mock_docker.NewMockMoby(ctrl)
 		mockMoby.EXPECT().
-			ContainerRemove(Eq(ctx), Eq(conID.String()), Eq(types.ContainerRemoveOptions{})).
+			ContainerRemove(Eq(ctx), Eq(conID.String()), Eq(types.ContainerRemoveOptions{Force: true})).
 			Times(1).
 			Return(nil)
 
@@ -510,7 +569,7 @@ func TestClient_RemoveContainer(t *testing.T) {
 
 		mockMoby := mock_docker.NewMockMoby(ctrl)
 		mockMoby.EXPECT().
-			ContainerRemove(Eq(ctx), Eq(conID.String()), Eq(types.ContainerRemoveOptions{})).
+			ContainerRemove(Eq(ctx), Eq(conID.String()), Eq(types.ContainerRemoveOptions{Force: true})).
 			Times(1).
 			Return(errors.New("test error"))
 
@@ -525,6 +584,101 @@ func TestClient_RemoveContainer(t *testing.T) {
 	})
 }
 
+func TestClient_RemoveContainers(t *testing.T) {
+	t.Run("without error", func(t *testing.T) {
+		// given
+		ctx := context.Background()
+		tag := docker.Tag(random.String(16, random.Lowercase))
+
+		// and
+		ctrl := NewController(t)
+		defer ctrl.Finish()
+
+		mockMoby := mock_docker.NewMockMoby(ctrl)
+		mockMoby.EXPECT().
+			ContainerList(Eq(ctx), Eq(types.ContainerListOptions{
+				All:     true,
+				Filters: filters.NewArgs(filters.Arg("label", fmt.Sprintf("duci.job=%s", tag))),
+			})).
+			Times(1).
+			Return([]types.Container{{ID: "hoge"}, {ID: "fuga"}}, nil)
+		mockMoby.EXPECT().
+			ContainerRemove(Eq(ctx), Eq("hoge"), Eq(types.ContainerRemoveOptions{Force: true})).
+			Times(1).
+			Return(nil)
+		mockMoby.EXPECT().
+			ContainerRemove(Eq(ctx), Eq("fuga"), Eq(types.ContainerRemoveOptions{Force: true})).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := &docker.Client{}
+		defer sut.SetMoby(mockMoby)()
+
+		// expect
+		if err := sut.RemoveContainers(ctx, tag); err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("when failed to list containers", func(t *testing.T) {
+		// given
+		ctx := context.Background()
+		tag := docker.Tag(random.String(16, random.Lowercase))
+
+		// and
+		ctrl := NewController(t)
+		defer ctrl.Finish()
+
+		mockMoby := mock_docker.NewMockMoby(ctrl)
+		mockMoby.EXPECT().
+			ContainerList(Any(), Any()).
+			Times(1).
+			Return(nil, errors.New("test error"))
+		mockMoby.EXPECT().
+			ContainerRemove(Any(), Any(), Any()).
+			Times(0)
+
+		// and
+		sut := &docker.Client{}
+		defer sut.SetMoby(mockMoby)()
+
+		// expect
+		if err := sut.RemoveContainers(ctx, tag); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+
+	t.Run("when failed to remove container", func(t *testing.T) {
+		// given
+		ctx := context.Background()
+		tag := docker.Tag(random.String(16, random.Lowercase))
+
+		// and
+		ctrl := NewController(t)
+		defer ctrl.Finish()
+
+		mockMoby := mock_docker.NewMockMoby(ctrl)
+		mockMoby.EXPECT().
+			ContainerList(Any(), Any()).
+			Times(1).
+			Return([]types.Container{{ID: "hoge"}}, nil)
+		mockMoby.EXPECT().
+			ContainerRemove(Any(), Any(), Any()).
+			Times(1).
+			Return(errors.New("test error"))
+
+		// and
+		sut := &docker.Client{}
+		defer sut.SetMoby(mockMoby)()
+
+		// expect
+		if err := sut.RemoveContainers(ctx, tag); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+}
+
 func TestClient_RemoveImage(t *testing.T) {
 	t.Run("without error", func(t *testing.T) {
 		// given
@@ -708,38 +862,73 @@ func TestClient_Status(t *testing.T) {
 }
 
 func TestBuildArgs(t *testing.T) {
-	// given
-	dockerfile := docker.Dockerfile{Dir: ".", Path: "testdata/Dockerfile"}
-
-	// and
-	hostArg2 := os.Getenv("ARGUMENT_2")
-	_ = os.Setenv("ARGUMENT_2", "host_arg2")
-	defer func() {
-		_ = os.Setenv("ARGUMENT_2", hostArg2)
-	}()
-
-	hostArg5 := os.Getenv("ARGUMENT_5")
-	_ = os.Setenv("ARGUMENT_5", "host_arg5")
-	defer func() {
-		_ = os.Setenv("ARGUMENT_5", hostArg5)
-	}()
-
-	// and
-	want := map[string]*string{
-		"ARGUMENT_2": github.String("host_arg2"),
-		"ARGUMENT_5": github.String("host_arg5"),
-	}
+	t.Run("with host environments", func(t *testing.T) {
+		// given
+		dockerfile := docker.Dockerfile{Dir: ".", Path: "testdata/Dockerfile"}
 
-	// when
-	got, err := docker.BuildArgs(dockerfile)
+		// and
+		hostArg2 := os.Getenv("ARGUMENT_2")
+		_ = os.Setenv("ARGUMENT_2", "host_arg2")
+		defer func() {
+			_ = os.Setenv("ARGUMENT_2", hostArg2)
+		}()
 
-	// then
-	if err != nil {
-		t.Errorf("error must be nil, but got %+v", err)
-	}
+		hostArg5 := os.Getenv("ARGUMENT_5")
+		_ = os.Setenv("ARGUMENT_5", "host_arg5")
+		defer func() {
+			_ = os.Setenv("ARGUMENT_5", hostArg5)
+		}()
 
-	// and
-	if !cmp.Equal(got, want) {
-		t.Errorf("must be equal but: %+v", cmp.Diff(got, want))
-	}
+		// and
+		want := map[string]*string{
+			"ARGUMENT_2": github.String("host_arg2"),
+			"ARGUMENT_5": github.String("host_arg5"),
+		}
+
+		// when
+		got, err := docker.BuildArgs(dockerfile)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal but: %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("with args", func(t *testing.T) {
+		// given
+		dockerfile := docker.Dockerfile{Dir: ".", Path: "testdata/Dockerfile", Args: map[string]string{
+			"ARGUMENT_2": "matrix_arg2",
+			"UNDECLARED": "undeclared",
+		}}
+
+		// and
+		hostArg2 := os.Getenv("ARGUMENT_2")
+		_ = os.Setenv("ARGUMENT_2", "host_arg2")
+		defer func() {
+			_ = os.Setenv("ARGUMENT_2", hostArg2)
+		}()
+
+		// and
+		want := map[string]*string{
+			"ARGUMENT_2": github.String("matrix_arg2"),
+		}
+
+		// when
+		got, err := docker.BuildArgs(dockerfile)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal but: %+v", cmp.Diff(got, want))
+		}
+	})
 }
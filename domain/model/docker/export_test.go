@@ -31,6 +31,14 @@ func SetNowFunc(f func() time.Time) (reset func()) {
 	}
 }
 
+func SetMaxPartialLength(n int) (reset func()) {
+	tmp := maxPartialLength
+	maxPartialLength = n
+	return func() {
+		maxPartialLength = tmp
+	}
+}
+
 type ErrorResponse struct {
 }
 
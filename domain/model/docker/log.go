@@ -3,6 +3,7 @@ package docker
 import (
 	"bufio"
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"github.com/duck8823/duci/domain/model/job"
@@ -16,83 +17,218 @@ import (
 var (
 	now               = time.Now
 	lineBreakReplacer = strings.NewReplacer("\r\n", "", "\r", "", "\n", "")
+	// maxPartialLength is bytes of a line kept until its line break. The rest of a longer line is discarded.
+	maxPartialLength = 1 << 20
 )
 
+// BuildError is a error reported by docker daemon while building image.
+type BuildError struct {
+	Message string
+}
+
+// Error returns error message
+func (e *BuildError) Error() string {
+	return e.Message
+}
+
 type buildLogger struct {
+	source io.Reader
 	reader *bufio.Reader
+	err    error
 }
 
 // NewBuildLog return a instance of Log.
+// It reads lines from the reader as soon as docker writes them, and closes the reader at the end.
 func NewBuildLog(r io.Reader) job.Log {
-	return &buildLogger{bufio.NewReader(r)}
+	return &buildLogger{source: r, reader: bufio.NewReader(r)}
 }
 
 // ReadLine returns LogLine.
+// When the build failed, it returns the error message as a line and then BuildError.
 func (l *buildLogger) ReadLine() (*job.LogLine, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+
 	for {
 		line, _, err := l.reader.ReadLine()
 		if err != nil {
+			l.close(err)
 			return nil, err
 		}
 
-		msg := extractMessage(line)
+		msg, failure := extractMessage(line)
+		if failure != nil {
+			l.close(failure)
+			return &job.LogLine{Timestamp: now(), Stream: job.BUILD, Message: failure.Message}, nil
+		}
 		if len(msg) == 0 {
 			continue
 		}
 
-		return &job.LogLine{Timestamp: now(), Message: lineBreakReplacer.Replace(msg)}, nil
+		return &job.LogLine{Timestamp: now(), Stream: job.BUILD, Message: lineBreakReplacer.Replace(msg)}, nil
+	}
+}
+
+// close keeps the error returned from next reading and closes the source.
+func (l *buildLogger) close(err error) {
+	l.err = err
+	if closer, ok := l.source.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			logrus.Warnf("Failed to close build log: %+v", err)
+		}
 	}
 }
 
 type runLogger struct {
-	reader *bufio.Reader
+	reader     *bufio.Reader
+	partial    map[job.LogStream]*bytes.Buffer
+	started    map[job.LogStream]time.Time
+	discarding map[job.LogStream]bool
+	lines      []*job.LogLine
 }
 
 // NewRunLog returns a instance of Log
 func NewRunLog(r io.Reader) job.Log {
-	return &runLogger{bufio.NewReader(r)}
+	return &runLogger{
+		reader:     bufio.NewReader(r),
+		partial:    make(map[job.LogStream]*bytes.Buffer),
+		started:    make(map[job.LogStream]time.Time),
+		discarding: make(map[job.LogStream]bool),
+	}
 }
 
 // ReadLine returns LogLine.
+// It demultiplexes frames of stdout and stderr, and a line may span frames.
+// Timestamp of the line is the time its first frame was written if frames have timestamps.
 func (l *runLogger) ReadLine() (*job.LogLine, error) {
-	for {
-		line, _, err := l.reader.ReadLine()
-		if err != nil {
-			return nil, err
+	for len(l.lines) == 0 {
+		if err := l.readFrame(); err == io.EOF {
+			l.flush()
+			if len(l.lines) == 0 {
+				return nil, io.EOF
+			}
+		} else if err != nil {
+			return nil, errors.WithStack(err)
 		}
+	}
 
-		msg, err := trimPrefix(line)
-		if err != nil {
-			return nil, errors.WithStack(err)
-		} else if len(msg) == 0 {
-			continue
+	line := l.lines[0]
+	l.lines = l.lines[1:]
+	return line, nil
+}
+
+// readFrame reads a frame of multiplexed stream and keeps lines completed by it.
+// A line longer than maxPartialLength is kept truncated without waiting for its line break.
+// see https://godoc.org/github.com/docker/docker/client#Client.ContainerLogs
+func (l *runLogger) readFrame() error {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(l.reader, header); err == io.ErrUnexpectedEOF {
+		return io.EOF
+	} else if err != nil {
+		return err
+	}
+
+	stream, ok := streams[header[0]]
+	if !ok || header[1] != 0 || header[2] != 0 || header[3] != 0 {
+		return fmt.Errorf("invalid prefix: %+v", header)
+	}
+
+	payload := new(bytes.Buffer)
+	if _, err := io.CopyN(payload, l.reader, int64(binary.BigEndian.Uint32(header[4:]))); err != nil && err != io.EOF {
+		return err
+	}
+	at, msg := splitTimestamp(payload.Bytes())
+
+	if l.discarding[stream] {
+		i := bytes.IndexByte(msg, '\n')
+		if i < 0 {
+			return nil
+		}
+		msg = msg[i+1:]
+		l.discarding[stream] = false
+	}
+
+	buf, ok := l.partial[stream]
+	if !ok {
+		buf = new(bytes.Buffer)
+		l.partial[stream] = buf
+	}
+	if buf.Len() == 0 {
+		l.started[stream] = at
+	}
+	buf.Write(msg)
+
+	for i := bytes.IndexByte(buf.Bytes(), '\n'); i >= 0; i = bytes.IndexByte(buf.Bytes(), '\n') {
+		l.push(stream, l.started[stream], buf.Next(i+1))
+		l.started[stream] = at
+	}
+
+	if buf.Len() > maxPartialLength {
+		line := append(buf.Next(maxPartialLength), job.LineTruncationMarker...)
+		l.push(stream, l.started[stream], line)
+		buf.Reset()
+		l.discarding[stream] = true
+	}
+	return nil
+}
+
+// splitTimestamp returns time at the head of payload and the rest.
+// It returns current time and the whole payload if the payload has no timestamp.
+func splitTimestamp(payload []byte) (time.Time, []byte) {
+	i := bytes.IndexByte(payload, ' ')
+	if i < 0 {
+		return now(), payload
+	}
+	at, err := time.Parse(time.RFC3339Nano, string(payload[:i]))
+	if err != nil {
+		return now(), payload
+	}
+	return at, payload[i+1:]
+}
+
+// flush keeps lines not terminated by line break at end of stream
+func (l *runLogger) flush() {
+	for _, stream := range []job.LogStream{job.STDOUT, job.STDERR, job.SYSTEM} {
+		if buf, ok := l.partial[stream]; ok && buf.Len() > 0 {
+			l.push(stream, l.started[stream], buf.Next(buf.Len()))
 		}
+	}
+}
 
-		// prevent to CR
-		progress := bytes.Split(msg, []byte{'\r'})
-		return &job.LogLine{Timestamp: now(), Message: lineBreakReplacer.Replace(string(progress[0]))}, nil
+func (l *runLogger) push(stream job.LogStream, at time.Time, line []byte) {
+	msg := job.Collapse(strings.TrimRight(string(line), "\r\n"))
+	if len(msg) == 0 {
+		return
 	}
+	l.lines = append(l.lines, &job.LogLine{Timestamp: at, Stream: stream, Message: msg})
+}
+
+// streams maps stream type in header of frame
+var streams = map[byte]job.LogStream{
+	0: job.STDOUT,
+	1: job.STDOUT,
+	2: job.STDERR,
+	3: job.SYSTEM,
 }
 
-func extractMessage(line []byte) string {
+// extractMessage returns the message in a line of build output, or BuildError if the line reports a error.
+func extractMessage(line []byte) (string, *BuildError) {
 	s := &struct {
-		Stream string `json:"stream"`
+		Stream      string `json:"stream"`
+		Error       string `json:"error"`
+		ErrorDetail struct {
+			Message string `json:"message"`
+		} `json:"errorDetail"`
 	}{}
 	if err := json.NewDecoder(bytes.NewReader(line)).Decode(s); err != nil {
 		logrus.Errorf("%+v", err)
 	}
-	return s.Stream
-}
-
-func trimPrefix(line []byte) ([]byte, error) {
-	if len(line) < 8 {
-		return []byte{}, nil
+	if len(s.ErrorDetail.Message) > 0 {
+		return "", &BuildError{Message: s.ErrorDetail.Message}
 	}
-
-	// detect prefix
-	// see https://godoc.org/github.com/docker/docker/client#Client.ContainerLogs
-	if !((line[0] == 1 || line[0] == 2) && (line[1] == 0 && line[2] == 0 && line[3] == 0)) {
-		return nil, fmt.Errorf("invalid prefix: %+v", line[:7])
+	if len(s.Error) > 0 {
+		return "", &BuildError{Message: s.Error}
 	}
-	return line[8:], nil
+	return s.Stream, nil
 }
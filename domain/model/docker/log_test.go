@@ -1,6 +1,7 @@
 package docker_test
 
 import (
+	"encoding/binary"
 	"fmt"
 	"github.com/duck8823/duci/domain/model/docker"
 	"github.com/duck8823/duci/domain/model/job"
@@ -31,6 +32,7 @@ func TestBuildLogger_ReadLine(t *testing.T) {
 	// and
 	want := &job.LogLine{
 		Timestamp: now,
+		Stream:    job.BUILD,
 		Message:   "hello test",
 	}
 
@@ -64,17 +66,7 @@ func TestBuildLogger_ReadLine(t *testing.T) {
 	}
 }
 
-func TestNewRunLog(t *testing.T) {
-	// when
-	got := docker.NewRunLog(strings.NewReader("hello world"))
-
-	// then
-	if _, ok := got.(job.Log); !ok {
-		t.Errorf("type assertion error.")
-	}
-}
-
-func TestRunLogger_ReadLine(t *testing.T) {
+func TestBuildLogger_ReadLine_WithError(t *testing.T) {
 	// given
 	now := time.Now()
 	defer docker.SetNowFunc(func() time.Time {
@@ -82,50 +74,227 @@ func TestRunLogger_ReadLine(t *testing.T) {
 	})()
 
 	// and
-	want := &job.LogLine{
-		Timestamp: now,
-		Message:   "hello test",
-	}
+	body := &closeRecorder{Reader: strings.NewReader(strings.Join([]string{
+		`{"stream":"Step 2/2 : RUN exit 1"}`,
+		`{"errorDetail":{"code":1,"message":"returned a non-zero code: 1"},"error":"returned a non-zero code: 1"}`,
+		`{"stream":"never read"}`,
+	}, "\n"))}
 
 	// and
-	sut := docker.NewRunLog(strings.NewReader(fmt.Sprintf("%shello test\rskipped line\n\n1234567890", string([]byte{1, 0, 0, 0, 9, 9, 9, 9}))))
+	sut := docker.NewBuildLog(body)
+
+	// expect
+	for _, want := range []*job.LogLine{
+		{Timestamp: now, Stream: job.BUILD, Message: "Step 2/2 : RUN exit 1"},
+		{Timestamp: now, Stream: job.BUILD, Message: "returned a non-zero code: 1"},
+	} {
+		got, err := sut.ReadLine()
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but: %+v", cmp.Diff(got, want))
+		}
+	}
 
 	// when
 	got, err := sut.ReadLine()
 
 	// then
-	if err != nil {
-		t.Errorf("error must be nil, but got %+v", err)
+	if _, ok := err.(*docker.BuildError); !ok {
+		t.Errorf("error must be BuildError, but got %+v", err)
 	}
 
 	// and
-	if !cmp.Equal(got, want) {
-		t.Errorf("must be equal, but: %+v", cmp.Diff(got, want))
+	if got != nil {
+		t.Errorf("must be nil, but got %+v", got.Message)
+	}
+
+	// and
+	if !body.closed {
+		t.Error("body must be closed")
 	}
+}
 
+func TestNewRunLog(t *testing.T) {
 	// when
-	got, err = sut.ReadLine()
+	got := docker.NewRunLog(strings.NewReader("hello world"))
 
 	// then
-	if err == nil || err == io.EOF {
-		t.Errorf("error must not be nil (invalid prefix), but got %+v", err)
+	if _, ok := got.(job.Log); !ok {
+		t.Errorf("type assertion error.")
 	}
+}
+
+func TestRunLogger_ReadLine(t *testing.T) {
+	// given
+	now := time.Now()
+	defer docker.SetNowFunc(func() time.Time {
+		return now
+	})()
 
 	// and
-	if got != nil {
-		t.Errorf("must be nil, but got %+v", got.Message)
+	frame := func(stream byte, payload string) string {
+		header := []byte{stream, 0, 0, 0, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+		return string(header) + payload
 	}
 
-	// when
-	got, err = sut.ReadLine()
+	// where
+	for _, tt := range []struct {
+		name  string
+		input string
+		want  []job.LogLine
+	}{
+		{
+			name:  "with a frame of a line",
+			input: frame(1, "hello test\n"),
+			want: []job.LogLine{
+				{Timestamp: now, Stream: job.STDOUT, Message: "hello test"},
+			},
+		},
+		{
+			name:  "with progress updated by carriage return",
+			input: frame(1, "progress  10%\rprogress 100%\r\n"),
+			want: []job.LogLine{
+				{Timestamp: now, Stream: job.STDOUT, Message: "progress 100%"},
+			},
+		},
+		{
+			name:  "with timestamps",
+			input: frame(1, "2018-09-21T13:19:42.572879Z first\n") + frame(2, "2018-09-21T13:19:43Z second\n"),
+			want: []job.LogLine{
+				{Timestamp: time.Date(2018, 9, 21, 13, 19, 42, 572879000, time.UTC), Stream: job.STDOUT, Message: "first"},
+				{Timestamp: time.Date(2018, 9, 21, 13, 19, 43, 0, time.UTC), Stream: job.STDERR, Message: "second"},
+			},
+		},
+		{
+			name: "with timestamps of a line split into frames",
+			input: frame(1, "2018-09-21T13:19:42Z hello ") +
+				frame(1, "2018-09-21T13:19:50Z test\nnext") +
+				frame(1, "2018-09-21T13:19:55Z  line\n"),
+			want: []job.LogLine{
+				{Timestamp: time.Date(2018, 9, 21, 13, 19, 42, 0, time.UTC), Stream: job.STDOUT, Message: "hello test"},
+				{Timestamp: time.Date(2018, 9, 21, 13, 19, 50, 0, time.UTC), Stream: job.STDOUT, Message: "next line"},
+			},
+		},
+		{
+			name:  "with a frame spanning lines",
+			input: frame(1, "first\n\nsecond\n"),
+			want: []job.LogLine{
+				{Timestamp: now, Stream: job.STDOUT, Message: "first"},
+				{Timestamp: now, Stream: job.STDOUT, Message: "second"},
+			},
+		},
+		{
+			name:  "with a line split into frames",
+			input: frame(1, "hello ") + frame(1, "test\n"),
+			want: []job.LogLine{
+				{Timestamp: now, Stream: job.STDOUT, Message: "hello test"},
+			},
+		},
+		{
+			name:  "with interleaved stdout and stderr",
+			input: frame(1, "out ") + frame(2, "err\n") + frame(1, "line\n") + frame(3, "daemon error\n"),
+			want: []job.LogLine{
+				{Timestamp: now, Stream: job.STDERR, Message: "err"},
+				{Timestamp: now, Stream: job.STDOUT, Message: "out line"},
+				{Timestamp: now, Stream: job.SYSTEM, Message: "daemon error"},
+			},
+		},
+		{
+			name:  "with lines not terminated at end of stream",
+			input: frame(2, "err") + frame(1, "out"),
+			want: []job.LogLine{
+				{Timestamp: now, Stream: job.STDOUT, Message: "out"},
+				{Timestamp: now, Stream: job.STDERR, Message: "err"},
+			},
+		},
+		{
+			name:  "with truncated frame",
+			input: frame(1, "hello test\n")[:12],
+			want: []job.LogLine{
+				{Timestamp: now, Stream: job.STDOUT, Message: "hell"},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			sut := docker.NewRunLog(strings.NewReader(tt.input))
 
-	// then
-	if err != io.EOF {
-		t.Errorf("error must be io.EOF, but got %+v", err)
-	}
+			// when
+			var got []job.LogLine
+			line, err := sut.ReadLine()
+			for ; err == nil; line, err = sut.ReadLine() {
+				got = append(got, *line)
+			}
 
-	// and
-	if got != nil {
-		t.Errorf("must be nil, but got %+v", got.Message)
+			// then
+			if err != io.EOF {
+				t.Errorf("error must be io.EOF, but got %+v", err)
+			}
+
+			// and
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("must be equal, but: %+v", cmp.Diff(got, tt.want))
+			}
+		})
 	}
+
+	t.Run("with a line longer than max partial length", func(t *testing.T) {
+		// given
+		defer docker.SetMaxPartialLength(8)()
+
+		// and
+		sut := docker.NewRunLog(strings.NewReader(frame(1, "0123456789") + frame(1, "abc\nnext\n")))
+
+		// when
+		var got []job.LogLine
+		line, err := sut.ReadLine()
+		for ; err == nil; line, err = sut.ReadLine() {
+			got = append(got, *line)
+		}
+
+		// then
+		if err != io.EOF {
+			t.Errorf("error must be io.EOF, but got %+v", err)
+		}
+
+		// and
+		want := []job.LogLine{
+			{Timestamp: now, Stream: job.STDOUT, Message: "01234567" + job.LineTruncationMarker},
+			{Timestamp: now, Stream: job.STDOUT, Message: "next"},
+		}
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but: %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("with invalid prefix", func(t *testing.T) {
+		// given
+		sut := docker.NewRunLog(strings.NewReader("1234567890"))
+
+		// when
+		got, err := sut.ReadLine()
+
+		// then
+		if err == nil || err == io.EOF {
+			t.Errorf("error must not be nil (invalid prefix), but got %+v", err)
+		}
+
+		// and
+		if got != nil {
+			t.Errorf("must be nil, but got %+v", got.Message)
+		}
+	})
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
 }
@@ -75,6 +75,18 @@ func (mr *MockDockerMockRecorder) RemoveContainer(ctx, containerID interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveContainer", reflect.TypeOf((*MockDocker)(nil).RemoveContainer), ctx, containerID)
 }
 
+// RemoveContainers mocks base method
+func (m *MockDocker) RemoveContainers(ctx context.Context, tag docker.Tag) error {
+	ret := m.ctrl.Call(m, "RemoveContainers", ctx, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveContainers indicates an expected call of RemoveContainers
+func (mr *MockDockerMockRecorder) RemoveContainers(ctx, tag interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveContainers", reflect.TypeOf((*MockDocker)(nil).RemoveContainers), ctx, tag)
+}
+
 // RemoveImage mocks base method
 func (m *MockDocker) RemoveImage(ctx context.Context, tag docker.Tag) error {
 	ret := m.ctrl.Call(m, "RemoveImage", ctx, tag)
@@ -100,6 +112,68 @@ func (mr *MockDockerMockRecorder) ExitCode(ctx, containerID interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExitCode", reflect.TypeOf((*MockDocker)(nil).ExitCode), ctx, containerID)
 }
 
+// CreateNetwork mocks base method
+func (m *MockDocker) CreateNetwork(ctx context.Context, network docker.Network, tag docker.Tag) error {
+	ret := m.ctrl.Call(m, "CreateNetwork", ctx, network, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNetwork indicates an expected call of CreateNetwork
+func (mr *MockDockerMockRecorder) CreateNetwork(ctx, network, tag interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNetwork", reflect.TypeOf((*MockDocker)(nil).CreateNetwork), ctx, network, tag)
+}
+
+// RemoveNetwork mocks base method
+func (m *MockDocker) RemoveNetwork(ctx context.Context, network docker.Network) error {
+	ret := m.ctrl.Call(m, "RemoveNetwork", ctx, network)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveNetwork indicates an expected call of RemoveNetwork
+func (mr *MockDockerMockRecorder) RemoveNetwork(ctx, network interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveNetwork", reflect.TypeOf((*MockDocker)(nil).RemoveNetwork), ctx, network)
+}
+
+// RemoveNetworks mocks base method
+func (m *MockDocker) RemoveNetworks(ctx context.Context, tag docker.Tag) error {
+	ret := m.ctrl.Call(m, "RemoveNetworks", ctx, tag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveNetworks indicates an expected call of RemoveNetworks
+func (mr *MockDockerMockRecorder) RemoveNetworks(ctx, tag interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveNetworks", reflect.TypeOf((*MockDocker)(nil).RemoveNetworks), ctx, tag)
+}
+
+// StartService mocks base method
+func (m *MockDocker) StartService(ctx context.Context, network docker.Network, alias string, service docker.Service, resources docker.Resources, tag docker.Tag) (docker.ContainerID, error) {
+	ret := m.ctrl.Call(m, "StartService", ctx, network, alias, service, resources, tag)
+	ret0, _ := ret[0].(docker.ContainerID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartService indicates an expected call of StartService
+func (mr *MockDockerMockRecorder) StartService(ctx, network, alias, service, resources, tag interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartService", reflect.TypeOf((*MockDocker)(nil).StartService), ctx, network, alias, service, resources, tag)
+}
+
+// Health mocks base method
+func (m *MockDocker) Health(ctx context.Context, containerID docker.ContainerID) (docker.Health, error) {
+	ret := m.ctrl.Call(m, "Health", ctx, containerID)
+	ret0, _ := ret[0].(docker.Health)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Health indicates an expected call of Health
+func (mr *MockDockerMockRecorder) Health(ctx, containerID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockDocker)(nil).Health), ctx, containerID)
+}
+
 // Status mocks base method
 func (m *MockDocker) Status() error {
 	ret := m.ctrl.Call(m, "Status")
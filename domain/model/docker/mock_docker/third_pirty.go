@@ -88,6 +88,19 @@ func (mr *MockMobyMockRecorder) ContainerLogs(ctx, container, options interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerLogs", reflect.TypeOf((*MockMoby)(nil).ContainerLogs), ctx, container, options)
 }
 
+// ContainerList mocks base method
+func (m *MockMoby) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	ret := m.ctrl.Call(m, "ContainerList", ctx, options)
+	ret0, _ := ret[0].([]types.Container)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContainerList indicates an expected call of ContainerList
+func (mr *MockMobyMockRecorder) ContainerList(ctx, options interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerList", reflect.TypeOf((*MockMoby)(nil).ContainerList), ctx, options)
+}
+
 // ContainerRemove mocks base method
 func (m *MockMoby) ContainerRemove(ctx context.Context, containerID string, options types.ContainerRemoveOptions) error {
 	ret := m.ctrl.Call(m, "ContainerRemove", ctx, containerID, options)
@@ -138,3 +151,67 @@ func (m *MockMoby) Info(ctx context.Context) (types.Info, error) {
 func (mr *MockMobyMockRecorder) Info(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockMoby)(nil).Info), ctx)
 }
+
+// ImagePull mocks base method
+func (m *MockMoby) ImagePull(ctx context.Context, refStr string, options types.ImagePullOptions) (io.ReadCloser, error) {
+	ret := m.ctrl.Call(m, "ImagePull", ctx, refStr, options)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImagePull indicates an expected call of ImagePull
+func (mr *MockMobyMockRecorder) ImagePull(ctx, refStr, options interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImagePull", reflect.TypeOf((*MockMoby)(nil).ImagePull), ctx, refStr, options)
+}
+
+// ContainerInspect mocks base method
+func (m *MockMoby) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	ret := m.ctrl.Call(m, "ContainerInspect", ctx, containerID)
+	ret0, _ := ret[0].(types.ContainerJSON)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContainerInspect indicates an expected call of ContainerInspect
+func (mr *MockMobyMockRecorder) ContainerInspect(ctx, containerID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerInspect", reflect.TypeOf((*MockMoby)(nil).ContainerInspect), ctx, containerID)
+}
+
+// NetworkCreate mocks base method
+func (m *MockMoby) NetworkCreate(ctx context.Context, name string, options types.NetworkCreate) (types.NetworkCreateResponse, error) {
+	ret := m.ctrl.Call(m, "NetworkCreate", ctx, name, options)
+	ret0, _ := ret[0].(types.NetworkCreateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NetworkCreate indicates an expected call of NetworkCreate
+func (mr *MockMobyMockRecorder) NetworkCreate(ctx, name, options interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkCreate", reflect.TypeOf((*MockMoby)(nil).NetworkCreate), ctx, name, options)
+}
+
+// NetworkRemove mocks base method
+func (m *MockMoby) NetworkRemove(ctx context.Context, networkID string) error {
+	ret := m.ctrl.Call(m, "NetworkRemove", ctx, networkID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NetworkRemove indicates an expected call of NetworkRemove
+func (mr *MockMobyMockRecorder) NetworkRemove(ctx, networkID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkRemove", reflect.TypeOf((*MockMoby)(nil).NetworkRemove), ctx, networkID)
+}
+
+// NetworkList mocks base method
+func (m *MockMoby) NetworkList(ctx context.Context, options types.NetworkListOptions) ([]types.NetworkResource, error) {
+	ret := m.ctrl.Call(m, "NetworkList", ctx, options)
+	ret0, _ := ret[0].([]types.NetworkResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NetworkList indicates an expected call of NetworkList
+func (mr *MockMobyMockRecorder) NetworkList(ctx, options interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkList", reflect.TypeOf((*MockMoby)(nil).NetworkList), ctx, options)
+}
@@ -9,6 +9,8 @@ import (
 type RuntimeOptions struct {
 	Environments Environments
 	Volumes      Volumes
+	Resources    Resources
+	Services     Services
 }
 
 // Environments represents a docker `-e` option.
@@ -23,6 +25,21 @@ func (e Environments) Array() []string {
 	return a
 }
 
+// Merge returns environments overridden with the other.
+func (e Environments) Merge(other Environments) Environments {
+	if len(other) == 0 {
+		return e
+	}
+	merged := make(Environments)
+	for key, val := range e {
+		merged[key] = val
+	}
+	for key, val := range other {
+		merged[key] = val
+	}
+	return merged
+}
+
 // Volumes represents a docker `-v` option.
 type Volumes []string
 
@@ -0,0 +1,150 @@
+package docker
+
+import (
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+// Resources represents limits of resources and isolation settings of a container.
+type Resources struct {
+	CPUs        float64     `yaml:"cpus" json:"cpus"`
+	Memory      MemoryBytes `yaml:"memory" json:"memory"`
+	MemorySwap  MemoryBytes `yaml:"memory_swap" json:"memorySwap"`
+	PidsLimit   int64       `yaml:"pids_limit" json:"pidsLimit"`
+	NetworkMode string      `yaml:"network_mode" json:"networkMode"`
+	ReadOnly    *bool       `yaml:"read_only" json:"readOnly,omitempty"`
+	CapDrop     []string    `yaml:"cap_drop" json:"capDrop"`
+	User        string      `yaml:"user" json:"user"`
+}
+
+// DefaultNetworkModes is network modes allowed if limits do not set them.
+var DefaultNetworkModes = []string{"bridge", "none"}
+
+// Limits represents maximums of resources and network modes a repository can request.
+// User pins the user of containers, so that a repository can not run them as another user.
+type Limits struct {
+	CPUs         float64     `yaml:"cpus" json:"cpus"`
+	Memory       MemoryBytes `yaml:"memory" json:"memory"`
+	MemorySwap   MemoryBytes `yaml:"memory_swap" json:"memorySwap"`
+	PidsLimit    int64       `yaml:"pids_limit" json:"pidsLimit"`
+	NetworkModes []string    `yaml:"network_modes" json:"networkModes"`
+	User         string      `yaml:"user" json:"user"`
+}
+
+// MemoryBytes represents a size of memory, written as bytes or a string such as `512m`.
+type MemoryBytes int64
+
+// UnmarshalYAML decodes a number of bytes or a human readable size.
+func (m *MemoryBytes) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var bytes int64
+	if err := unmarshal(&bytes); err == nil {
+		*m = MemoryBytes(bytes)
+		return nil
+	}
+
+	var size string
+	if err := unmarshal(&size); err != nil {
+		return errors.WithStack(err)
+	}
+	if size == "-1" {
+		*m = -1
+		return nil
+	}
+	bytes, err := units.RAMInBytes(size)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	*m = MemoryBytes(bytes)
+	return nil
+}
+
+// IsReadOnly returns whether the root filesystem is mounted as read only.
+func (r Resources) IsReadOnly() bool {
+	return r.ReadOnly != nil && *r.ReadOnly
+}
+
+// Override returns resources with values set in the other.
+// Read only root filesystem and dropped capabilities can only be added, so that a repository can not loosen them.
+func (r Resources) Override(o Resources) Resources {
+	if o.CPUs > 0 {
+		r.CPUs = o.CPUs
+	}
+	if o.Memory != 0 {
+		r.Memory = o.Memory
+	}
+	if o.MemorySwap != 0 {
+		r.MemorySwap = o.MemorySwap
+	}
+	if o.PidsLimit != 0 {
+		r.PidsLimit = o.PidsLimit
+	}
+	if len(o.NetworkMode) > 0 {
+		r.NetworkMode = o.NetworkMode
+	}
+	if o.ReadOnly != nil && !r.IsReadOnly() {
+		r.ReadOnly = o.ReadOnly
+	}
+	r.CapDrop = union(r.CapDrop, o.CapDrop)
+	if len(o.User) > 0 {
+		r.User = o.User
+	}
+	return r
+}
+
+// Cap returns resources lowered to the limits. Unlimited values are also lowered.
+// Empty network mode is set to the first one allowed, and it returns error if the network mode is not allowed.
+// Network modes default to DefaultNetworkModes. It also returns error if the user differs from the pinned one.
+func (r Resources) Cap(l Limits) (Resources, error) {
+	if l.CPUs > 0 && (r.CPUs <= 0 || r.CPUs > l.CPUs) {
+		r.CPUs = l.CPUs
+	}
+	if l.Memory > 0 && (r.Memory <= 0 || r.Memory > l.Memory) {
+		r.Memory = l.Memory
+	}
+	if l.MemorySwap > 0 && (r.MemorySwap <= 0 || r.MemorySwap > l.MemorySwap) {
+		r.MemorySwap = l.MemorySwap
+	}
+	if l.PidsLimit > 0 && (r.PidsLimit <= 0 || r.PidsLimit > l.PidsLimit) {
+		r.PidsLimit = l.PidsLimit
+	}
+
+	if len(l.User) > 0 {
+		if len(r.User) > 0 && r.User != l.User {
+			return Resources{}, errors.Errorf("user %s is not allowed, must be %s", r.User, l.User)
+		}
+		r.User = l.User
+	}
+
+	modes := l.NetworkModes
+	if len(modes) == 0 {
+		modes = DefaultNetworkModes
+	}
+	if len(r.NetworkMode) == 0 {
+		r.NetworkMode = modes[0]
+		return r, nil
+	}
+	for _, mode := range modes {
+		if r.NetworkMode == mode {
+			return r, nil
+		}
+	}
+	return Resources{}, errors.Errorf("network mode %s is not allowed, must be one of %v", r.NetworkMode, modes)
+}
+
+// NanoCPUs returns the CPU quota in units of 1e-9 CPUs.
+func (r Resources) NanoCPUs() int64 {
+	return int64(r.CPUs * 1e9)
+}
+
+func union(a, b []string) []string {
+	seen := make(map[string]bool)
+	var u []string
+	for _, s := range append(append([]string{}, a...), b...) {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		u = append(u, s)
+	}
+	return u
+}
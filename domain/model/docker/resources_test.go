@@ -0,0 +1,205 @@
+package docker_test
+
+import (
+	"github.com/duck8823/duci/domain/model/docker"
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v2"
+	"testing"
+)
+
+func TestMemoryBytes_UnmarshalYAML(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		in      string
+		want    docker.MemoryBytes
+		wantErr bool
+	}{
+		{in: "memory: 1024", want: 1024},
+		{in: "memory: 512m", want: 512 * 1024 * 1024},
+		{in: "memory: 2g", want: 2 * 1024 * 1024 * 1024},
+		{in: "memory: -1", want: -1},
+		{in: "memory: many", wantErr: true},
+	} {
+		// given
+		var got struct {
+			Memory docker.MemoryBytes
+		}
+
+		// when
+		err := yaml.Unmarshal([]byte(tt.in), &got)
+
+		// then
+		if tt.wantErr && err == nil {
+			t.Errorf("error must not be nil: %s", tt.in)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if got.Memory != tt.want {
+			t.Errorf("want: %d, but got: %d", tt.want, got.Memory)
+		}
+	}
+}
+
+func TestResources_Override(t *testing.T) {
+	// given
+	readOnly := true
+	writable := false
+
+	// where
+	for _, tt := range []struct {
+		name     string
+		defaults docker.Resources
+		in       docker.Resources
+		want     docker.Resources
+	}{
+		{
+			name:     "with empty override",
+			defaults: docker.Resources{CPUs: 1, Memory: 1024, NetworkMode: "bridge", CapDrop: []string{"NET_RAW"}},
+			in:       docker.Resources{},
+			want:     docker.Resources{CPUs: 1, Memory: 1024, NetworkMode: "bridge", CapDrop: []string{"NET_RAW"}},
+		},
+		{
+			name:     "with values",
+			defaults: docker.Resources{CPUs: 1, Memory: 1024, NetworkMode: "bridge"},
+			in:       docker.Resources{CPUs: 2, MemorySwap: 2048, PidsLimit: 100, NetworkMode: "none", ReadOnly: &readOnly, User: "nobody"},
+			want:     docker.Resources{CPUs: 2, Memory: 1024, MemorySwap: 2048, PidsLimit: 100, NetworkMode: "none", ReadOnly: &readOnly, User: "nobody"},
+		},
+		{
+			name:     "with dropped capabilities",
+			defaults: docker.Resources{CapDrop: []string{"NET_RAW", "MKNOD"}},
+			in:       docker.Resources{CapDrop: []string{"MKNOD", "CHOWN"}},
+			want:     docker.Resources{CapDrop: []string{"NET_RAW", "MKNOD", "CHOWN"}},
+		},
+		{
+			name:     "when read only is enforced",
+			defaults: docker.Resources{ReadOnly: &readOnly},
+			in:       docker.Resources{ReadOnly: &writable},
+			want:     docker.Resources{ReadOnly: &readOnly},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			got := tt.defaults.Override(tt.in)
+
+			// then
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("must be equal, but %+v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestResources_Cap(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		name    string
+		in      docker.Resources
+		limits  docker.Limits
+		want    docker.Resources
+		wantErr bool
+	}{
+		{
+			name:   "without limits",
+			in:     docker.Resources{CPUs: 8, Memory: 4096, NetworkMode: "none"},
+			limits: docker.Limits{},
+			want:   docker.Resources{CPUs: 8, Memory: 4096, NetworkMode: "none"},
+		},
+		{
+			name:   "with empty network mode without limits",
+			in:     docker.Resources{},
+			limits: docker.Limits{},
+			want:   docker.Resources{NetworkMode: "bridge"},
+		},
+		{
+			name:    "with host network mode without limits",
+			in:      docker.Resources{NetworkMode: "host"},
+			limits:  docker.Limits{},
+			want:    docker.Resources{},
+			wantErr: true,
+		},
+		{
+			name:    "with container network mode without limits",
+			in:      docker.Resources{NetworkMode: "container:abcdef"},
+			limits:  docker.Limits{},
+			want:    docker.Resources{},
+			wantErr: true,
+		},
+		{
+			name:   "with pinned user",
+			in:     docker.Resources{NetworkMode: "none"},
+			limits: docker.Limits{User: "1000:1000"},
+			want:   docker.Resources{NetworkMode: "none", User: "1000:1000"},
+		},
+		{
+			name:   "with the same user as pinned",
+			in:     docker.Resources{NetworkMode: "none", User: "1000:1000"},
+			limits: docker.Limits{User: "1000:1000"},
+			want:   docker.Resources{NetworkMode: "none", User: "1000:1000"},
+		},
+		{
+			name:    "with user other than pinned",
+			in:      docker.Resources{NetworkMode: "none", User: "root"},
+			limits:  docker.Limits{User: "1000:1000"},
+			want:    docker.Resources{},
+			wantErr: true,
+		},
+		{
+			name:   "with values over the limits",
+			in:     docker.Resources{CPUs: 8, Memory: 4096, MemorySwap: 8192, PidsLimit: 1000},
+			limits: docker.Limits{CPUs: 2, Memory: 1024, MemorySwap: 2048, PidsLimit: 100},
+			want:   docker.Resources{CPUs: 2, Memory: 1024, MemorySwap: 2048, PidsLimit: 100, NetworkMode: "bridge"},
+		},
+		{
+			name:   "with values under the limits",
+			in:     docker.Resources{CPUs: 1, Memory: 512, MemorySwap: 1024, PidsLimit: 50},
+			limits: docker.Limits{CPUs: 2, Memory: 1024, MemorySwap: 2048, PidsLimit: 100},
+			want:   docker.Resources{CPUs: 1, Memory: 512, MemorySwap: 1024, PidsLimit: 50, NetworkMode: "bridge"},
+		},
+		{
+			name:   "with unlimited values",
+			in:     docker.Resources{MemorySwap: -1, PidsLimit: -1},
+			limits: docker.Limits{CPUs: 2, Memory: 1024, MemorySwap: 2048, PidsLimit: 100},
+			want:   docker.Resources{CPUs: 2, Memory: 1024, MemorySwap: 2048, PidsLimit: 100, NetworkMode: "bridge"},
+		},
+		{
+			name:   "with empty network mode",
+			in:     docker.Resources{},
+			limits: docker.Limits{NetworkModes: []string{"none", "bridge"}},
+			want:   docker.Resources{NetworkMode: "none"},
+		},
+		{
+			name:   "with allowed network mode",
+			in:     docker.Resources{NetworkMode: "bridge"},
+			limits: docker.Limits{NetworkModes: []string{"none", "bridge"}},
+			want:   docker.Resources{NetworkMode: "bridge"},
+		},
+		{
+			name:    "with not allowed network mode",
+			in:      docker.Resources{NetworkMode: "host"},
+			limits:  docker.Limits{NetworkModes: []string{"none", "bridge"}},
+			want:    docker.Resources{},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			got, err := tt.in.Cap(tt.limits)
+
+			// then
+			if tt.wantErr && err == nil {
+				t.Error("error must not be nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("error must be nil, but got %+v", err)
+			}
+
+			// and
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("must be equal, but %+v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package docker
+
+import (
+	"github.com/docker/docker/api/types/container"
+	"sort"
+	"time"
+)
+
+// Network describes a name of user-defined network
+type Network string
+
+// ToString returns string value
+func (n Network) String() string {
+	return string(n)
+}
+
+// Services represents sidecar containers keyed by the alias in the network.
+type Services map[string]Service
+
+// Names returns sorted aliases of services.
+func (s Services) Names() []string {
+	var names []string
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Service represents a sidecar container started with the task container.
+type Service struct {
+	Image        string
+	Environments Environments
+	Command      Command
+	Healthcheck  *Healthcheck
+}
+
+// Healthcheck represents a docker `HEALTHCHECK`. Nil means the one defined in the image.
+type Healthcheck struct {
+	Test        Command
+	Interval    time.Duration
+	Timeout     time.Duration
+	StartPeriod time.Duration `yaml:"start_period"`
+	Retries     int
+}
+
+// Config returns the healthcheck as a container config.
+func (h *Healthcheck) Config() *container.HealthConfig {
+	if h == nil {
+		return nil
+	}
+	return &container.HealthConfig{
+		Test:        h.Test.Slice(),
+		Interval:    h.Interval,
+		Timeout:     h.Timeout,
+		StartPeriod: h.StartPeriod,
+		Retries:     h.Retries,
+	}
+}
+
+// Health describes a health status of container
+type Health string
+
+const (
+	// Starting is a status before healthcheck succeeds
+	Starting Health = "starting"
+	// Healthy is a status after healthcheck succeeds
+	Healthy Health = "healthy"
+	// Unhealthy is a status after healthcheck fails in all retries
+	Unhealthy Health = "unhealthy"
+	// NoHealthcheck is a status of running container without healthcheck
+	NoHealthcheck Health = "none"
+)
+
+// IsReady returns whether the container can accept connections or not.
+func (h Health) IsReady() bool {
+	return h == Healthy || h == NoHealthcheck
+}
@@ -0,0 +1,379 @@
+package docker_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/duck8823/duci/domain/model/docker"
+	"github.com/duck8823/duci/domain/model/docker/mock_docker"
+	. "github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestServices_UnmarshalYAML(t *testing.T) {
+	// given
+	in := `
+services:
+  db:
+    image: postgres:11
+    environments:
+      POSTGRES_PASSWORD: secret
+    healthcheck:
+      test: [CMD, pg_isready]
+      interval: 2s
+      timeout: 5s
+      start_period: 10s
+      retries: 3
+  cache:
+    image: redis
+    command: [redis-server, --appendonly, "yes"]
+`
+
+	// and
+	want := docker.RuntimeOptions{
+		Services: docker.Services{
+			"db": {
+				Image:        "postgres:11",
+				Environments: docker.Environments{"POSTGRES_PASSWORD": "secret"},
+				Healthcheck: &docker.Healthcheck{
+					Test:        docker.Command{"CMD", "pg_isready"},
+					Interval:    2 * time.Second,
+					Timeout:     5 * time.Second,
+					StartPeriod: 10 * time.Second,
+					Retries:     3,
+				},
+			},
+			"cache": {
+				Image:   "redis",
+				Command: docker.Command{"redis-server", "--appendonly", "yes"},
+			},
+		},
+	}
+
+	// when
+	var got docker.RuntimeOptions
+	err := yaml.Unmarshal([]byte(in), &got)
+
+	// then
+	if err != nil {
+		t.Errorf("error must be nil, but got %+v", err)
+	}
+
+	// and
+	if !cmp.Equal(got, want) {
+		t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+	}
+
+	// and
+	if !cmp.Equal(got.Services.Names(), []string{"cache", "db"}) {
+		t.Errorf("names must be sorted, but got %+v", got.Services.Names())
+	}
+}
+
+func TestHealth_IsReady(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		in   docker.Health
+		want bool
+	}{
+		{in: docker.Starting, want: false},
+		{in: docker.Healthy, want: true},
+		{in: docker.Unhealthy, want: false},
+		{in: docker.NoHealthcheck, want: true},
+	} {
+		// when
+		got := tt.in.IsReady()
+
+		// then
+		if got != tt.want {
+			t.Errorf("%s want: %t, but got: %t", tt.in, tt.want, got)
+		}
+	}
+}
+
+func TestClient_CreateNetwork(t *testing.T) {
+	// given
+	ctrl := NewController(t)
+	defer ctrl.Finish()
+
+	// and
+	ctx := context.Background()
+
+	mockMoby := mock_docker.NewMockMoby(ctrl)
+	mockMoby.EXPECT().
+		NetworkCreate(Eq(ctx), Eq("duci-test"), Eq(types.NetworkCreate{
+			CheckDuplicate: true,
+			Driver:         "bridge",
+			Labels:         map[string]string{"duci.job": "duci-test"},
+		})).
+		Times(1).
+		Return(types.NetworkCreateResponse{ID: "network-id"}, nil)
+
+	// and
+	sut := &docker.Client{}
+	defer sut.SetMoby(mockMoby)()
+
+	// expect
+	if err := sut.CreateNetwork(ctx, "duci-test", "duci-test"); err != nil {
+		t.Errorf("error must be nil, but got %+v", err)
+	}
+}
+
+func TestClient_RemoveNetwork(t *testing.T) {
+	// given
+	ctrl := NewController(t)
+	defer ctrl.Finish()
+
+	// and
+	ctx := context.Background()
+
+	mockMoby := mock_docker.NewMockMoby(ctrl)
+	mockMoby.EXPECT().
+		NetworkRemove(Eq(ctx), Eq("duci-test")).
+		Times(1).
+		Return(errors.New("test error"))
+
+	// and
+	sut := &docker.Client{}
+	defer sut.SetMoby(mockMoby)()
+
+	// expect
+	if err := sut.RemoveNetwork(ctx, "duci-test"); err == nil {
+		t.Error("error must not be nil")
+	}
+}
+
+func TestClient_RemoveNetworks(t *testing.T) {
+	t.Run("without error", func(t *testing.T) {
+		// given
+		ctx := context.Background()
+		tag := docker.Tag("duci-test")
+
+		// and
+		ctrl := NewController(t)
+		defer ctrl.Finish()
+
+		mockMoby := mock_docker.NewMockMoby(ctrl)
+		mockMoby.EXPECT().
+			NetworkList(Eq(ctx), Eq(types.NetworkListOptions{
+				Filters: filters.NewArgs(filters.Arg("label", "duci.job=duci-test")),
+			})).
+			Times(1).
+			Return([]types.NetworkResource{{Name: "duci-hoge"}, {Name: "duci-fuga"}}, nil)
+		mockMoby.EXPECT().
+			NetworkRemove(Eq(ctx), Eq("duci-hoge")).
+			Times(1).
+			Return(nil)
+		mockMoby.EXPECT().
+			NetworkRemove(Eq(ctx), Eq("duci-fuga")).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := &docker.Client{}
+		defer sut.SetMoby(mockMoby)()
+
+		// expect
+		if err := sut.RemoveNetworks(ctx, tag); err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("when failed to list networks", func(t *testing.T) {
+		// given
+		ctx := context.Background()
+
+		// and
+		ctrl := NewController(t)
+		defer ctrl.Finish()
+
+		mockMoby := mock_docker.NewMockMoby(ctrl)
+		mockMoby.EXPECT().
+			NetworkList(Any(), Any()).
+			Times(1).
+			Return(nil, errors.New("test error"))
+		mockMoby.EXPECT().
+			NetworkRemove(Any(), Any()).
+			Times(0)
+
+		// and
+		sut := &docker.Client{}
+		defer sut.SetMoby(mockMoby)()
+
+		// expect
+		if err := sut.RemoveNetworks(ctx, "duci-test"); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+}
+
+func TestClient_StartService(t *testing.T) {
+	t.Run("with no error", func(t *testing.T) {
+		// given
+		ctrl := NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		ctx := context.Background()
+		service := docker.Service{
+			Image:        "postgres",
+			Environments: docker.Environments{"POSTGRES_PASSWORD": "secret"},
+			Healthcheck:  &docker.Healthcheck{Test: docker.Command{"CMD", "pg_isready"}, Retries: 3},
+		}
+		readOnly := true
+		resources := docker.Resources{
+			CPUs:        1.5,
+			Memory:      1024,
+			PidsLimit:   100,
+			NetworkMode: "bridge",
+			ReadOnly:    &readOnly,
+			CapDrop:     []string{"NET_RAW"},
+			User:        "1000:1000",
+		}
+
+		// and
+		mockMoby := mock_docker.NewMockMoby(ctrl)
+		mockMoby.EXPECT().
+			ImagePull(Eq(ctx), Eq("postgres"), Eq(types.ImagePullOptions{})).
+			Times(1).
+			Return(ioutil.NopCloser(bytes.NewReader([]byte(`{"status":"Pulling from library/postgres"}`+"\n"))), nil)
+		mockMoby.EXPECT().
+			ContainerCreate(Eq(ctx), Eq(&container.Config{
+				Image:       "postgres",
+				Env:         []string{"POSTGRES_PASSWORD=secret"},
+				Healthcheck: &container.HealthConfig{Test: []string{"CMD", "pg_isready"}, Retries: 3},
+				Labels:      map[string]string{"duci.job": "test_tag"},
+			}), Eq(&container.HostConfig{
+				Resources: container.Resources{
+					NanoCPUs:  1500000000,
+					Memory:    1024,
+					PidsLimit: 100,
+				},
+				NetworkMode: "duci-test",
+			}), Eq(&network.NetworkingConfig{
+				EndpointsConfig: map[string]*network.EndpointSettings{
+					"duci-test": {Aliases: []string{"db"}},
+				},
+			}), Eq("")).
+			Times(1).
+			Return(container.ContainerCreateCreatedBody{ID: "container-id"}, nil)
+		mockMoby.EXPECT().
+			ContainerStart(Eq(ctx), Eq("container-id"), Eq(types.ContainerStartOptions{})).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := &docker.Client{}
+		defer sut.SetMoby(mockMoby)()
+
+		// when
+		got, err := sut.StartService(ctx, "duci-test", "db", service, resources, "test_tag")
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if got != "container-id" {
+			t.Errorf("want: container-id, but got: %s", got)
+		}
+	})
+
+	t.Run("when failure pull image", func(t *testing.T) {
+		// given
+		ctrl := NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		ctx := context.Background()
+
+		// and
+		mockMoby := mock_docker.NewMockMoby(ctrl)
+		mockMoby.EXPECT().
+			ImagePull(Any(), Any(), Any()).
+			Times(1).
+			Return(ioutil.NopCloser(bytes.NewReader([]byte(`{"errorDetail":{"message":"not found"}}`+"\n"))), nil)
+		mockMoby.EXPECT().
+			ContainerCreate(Any(), Any(), Any(), Any(), Any()).
+			Times(0)
+
+		// and
+		sut := &docker.Client{}
+		defer sut.SetMoby(mockMoby)()
+
+		// expect
+		if _, err := sut.StartService(ctx, "duci-test", "db", docker.Service{Image: "unknown"}, docker.Resources{}, "test_tag"); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+}
+
+func TestClient_Health(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		name    string
+		state   *types.ContainerState
+		want    docker.Health
+		wantErr bool
+	}{
+		{
+			name:  "with healthcheck",
+			state: &types.ContainerState{Running: true, Health: &types.Health{Status: "starting"}},
+			want:  docker.Starting,
+		},
+		{
+			name:  "without healthcheck",
+			state: &types.ContainerState{Running: true},
+			want:  docker.NoHealthcheck,
+		},
+		{
+			name:    "when container exited",
+			state:   &types.ContainerState{Running: false, ExitCode: 1},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctrl := NewController(t)
+			defer ctrl.Finish()
+
+			// and
+			ctx := context.Background()
+
+			mockMoby := mock_docker.NewMockMoby(ctrl)
+			mockMoby.EXPECT().
+				ContainerInspect(Eq(ctx), Eq("container-id")).
+				Times(1).
+				Return(types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: tt.state}}, nil)
+
+			// and
+			sut := &docker.Client{}
+			defer sut.SetMoby(mockMoby)()
+
+			// when
+			got, err := sut.Health(ctx, "container-id")
+
+			// then
+			if tt.wantErr && err == nil {
+				t.Error("error must not be nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("error must be nil, but got %+v", err)
+			}
+
+			// and
+			if got != tt.want {
+				t.Errorf("want: %s, but got: %s", tt.want, got)
+			}
+		})
+	}
+}
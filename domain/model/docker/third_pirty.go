@@ -33,6 +33,10 @@ type Moby interface {
 		container string,
 		options types.ContainerLogsOptions,
 	) (io.ReadCloser, error)
+	ContainerList(
+		ctx context.Context,
+		options types.ContainerListOptions,
+	) ([]types.Container, error)
 	ContainerRemove(
 		ctx context.Context,
 		containerID string,
@@ -51,4 +55,26 @@ type Moby interface {
 	Info(
 		ctx context.Context,
 	) (types.Info, error)
+	ImagePull(
+		ctx context.Context,
+		refStr string,
+		options types.ImagePullOptions,
+	) (io.ReadCloser, error)
+	ContainerInspect(
+		ctx context.Context,
+		containerID string,
+	) (types.ContainerJSON, error)
+	NetworkCreate(
+		ctx context.Context,
+		name string,
+		options types.NetworkCreate,
+	) (types.NetworkCreateResponse, error)
+	NetworkRemove(
+		ctx context.Context,
+		networkID string,
+	) error
+	NetworkList(
+		ctx context.Context,
+		options types.NetworkListOptions,
+	) ([]types.NetworkResource, error)
 }
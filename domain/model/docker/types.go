@@ -15,6 +15,14 @@ func (t Tag) String() string {
 	return string(t)
 }
 
+// JobLabel is a label key of containers and networks, whose value is the tag of job created them
+const JobLabel = "duci.job"
+
+// Labels returns labels to find containers and networks created for the tag
+func (t Tag) Labels() map[string]string {
+	return map[string]string{JobLabel: t.String()}
+}
+
 // Command describes a docker CMD
 type Command []string
 
@@ -23,10 +31,11 @@ func (c Command) Slice() []string {
 	return []string(c)
 }
 
-// Dockerfile represents a path to dockerfile
+// Dockerfile represents a path to dockerfile and values of build args overriding host environments
 type Dockerfile struct {
 	Dir  string
 	Path string
+	Args map[string]string
 }
 
 // Open dockerfile
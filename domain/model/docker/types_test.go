@@ -25,6 +25,22 @@ func TestTag_String(t *testing.T) {
 	}
 }
 
+func TestTag_Labels(t *testing.T) {
+	// given
+	want := map[string]string{"duci.job": "hello"}
+
+	// and
+	sut := docker.Tag("hello")
+
+	// when
+	got := sut.Labels()
+
+	// then
+	if !cmp.Equal(got, want) {
+		t.Errorf("must equal: want %+v, got %+v", want, got)
+	}
+}
+
 func TestCommand_Slice(t *testing.T) {
 	// given
 	want := []string{"test", "./..."}
@@ -4,18 +4,96 @@ import (
 	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"time"
 )
 
 // Job represents a task
 type Job struct {
-	ID       ID
-	Finished bool      `json:"finished"`
-	Stream   []LogLine `json:"stream"`
+	ID         ID
+	Trigger    *Trigger   `json:"trigger,omitempty"`
+	State      State      `json:"state,omitempty"`
+	ExitCode   *int64     `json:"exitCode,omitempty"`
+	QueuedAt   *time.Time `json:"queuedAt,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Finished   bool       `json:"finished"`
+	Truncated  bool       `json:"truncated,omitempty"`
 }
 
-// AppendLog append log line to stream
-func (j *Job) AppendLog(line LogLine) {
-	j.Stream = append(j.Stream, line)
+// Trigger represents what the job was triggered by
+type Trigger struct {
+	Repository string            `json:"repository"`
+	Ref        string            `json:"ref"`
+	SHA        string            `json:"sha"`
+	Event      string            `json:"event"`
+	TaskName   string            `json:"taskName"`
+	TargetURL  string            `json:"targetUrl"`
+	Source     *Source           `json:"source,omitempty"`
+	Command    []string          `json:"command,omitempty"`
+	RerunOf    string            `json:"rerunOf,omitempty"`
+	Task       string            `json:"task,omitempty"`
+	Timeout    int64             `json:"timeout,omitempty"`
+	Matrix     map[string]string `json:"matrix,omitempty"`
+	Aggregates []Aggregate       `json:"aggregates,omitempty"`
+	Needs      []string          `json:"needs,omitempty"`
+}
+
+// Aggregate represents a commit status summarising jobs of a matrix or a pipeline, which are found by their task names
+type Aggregate struct {
+	Context   string   `json:"context"`
+	TargetURL string   `json:"targetUrl"`
+	Members   []string `json:"members"`
+}
+
+// Source represents a repository to clone
+type Source struct {
+	FullName string `json:"fullName"`
+	SSHURL   string `json:"sshUrl"`
+	CloneURL string `json:"cloneUrl"`
+}
+
+// GetFullName returns full name of repository
+func (s *Source) GetFullName() string {
+	return s.FullName
+}
+
+// GetSSHURL returns ssh url of repository
+func (s *Source) GetSSHURL() string {
+	return s.SSHURL
+}
+
+// GetCloneURL returns clone url of repository
+func (s *Source) GetCloneURL() string {
+	return s.CloneURL
+}
+
+// Result represents outcome of job
+type Result struct {
+	State    State
+	ExitCode *int64
+}
+
+// IsRerunnable returns whether the job has enough metadata to rebuild
+func (j *Job) IsRerunnable() bool {
+	return j.Finished && j.Trigger != nil && j.Trigger.Source != nil
+}
+
+// Queue set trigger and queued time
+func (j *Job) Queue(trigger Trigger, at time.Time) {
+	j.Trigger = &trigger
+	j.State = QUEUED
+	j.QueuedAt = &at
+}
+
+// Start set running state and started time
+func (j *Job) Start(at time.Time) {
+	j.State = RUNNING
+	j.StartedAt = &at
+}
+
+// Truncate marks that a part of log was not stored
+func (j *Job) Truncate() {
+	j.Truncated = true
 }
 
 // Finish set true to Finished
@@ -23,6 +101,14 @@ func (j *Job) Finish() {
 	j.Finished = true
 }
 
+// End set the result and finished time, and finish job
+func (j *Job) End(result Result, at time.Time) {
+	j.State = result.State
+	j.ExitCode = result.ExitCode
+	j.FinishedAt = &at
+	j.Finish()
+}
+
 // ToBytes returns marshal byte slice
 func (j *Job) ToBytes() ([]byte, error) {
 	data, err := json.Marshal(j)
@@ -35,6 +121,11 @@ func (j *Job) ToBytes() ([]byte, error) {
 // ID is the identifier of job
 type ID uuid.UUID
 
+// String returns string value
+func (i ID) String() string {
+	return uuid.UUID(i).String()
+}
+
 // ToSlice returns slice value
 func (i ID) ToSlice() []byte {
 	return []byte(uuid.UUID(i).String())
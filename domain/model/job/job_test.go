@@ -5,50 +5,130 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 	"testing"
+	"time"
 )
 
-func TestJob_AppendLog(t *testing.T) {
+func TestJob_Finish(t *testing.T) {
+	// given
+	sut := job.Job{}
+
+	// when
+	sut.Finish()
+
+	// then
+	if !sut.Finished {
+		t.Errorf("must be true, but false")
+	}
+}
+
+func TestJob_Queue(t *testing.T) {
 	// given
-	want := []job.LogLine{{
-		Message: "Hello World",
-	}}
+	trigger := job.Trigger{Repository: "duck8823/duci", Event: "push"}
+	at := time.Unix(10, 0)
+
+	// and
+	want := job.Job{Trigger: &trigger, State: job.QUEUED, QueuedAt: &at}
 
 	// and
 	sut := job.Job{}
 
 	// when
-	sut.AppendLog(want[0])
+	sut.Queue(trigger, at)
 
 	// then
-	got := sut.Stream
-	if !cmp.Equal(got, want) {
-		t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+	if !cmp.Equal(sut, want) {
+		t.Errorf("must be equal, but %+v", cmp.Diff(sut, want))
 	}
 }
 
-func TestJob_Finish(t *testing.T) {
+func TestJob_Start(t *testing.T) {
 	// given
-	sut := job.Job{}
+	at := time.Unix(20, 0)
+
+	// and
+	want := job.Job{State: job.RUNNING, StartedAt: &at}
+
+	// and
+	sut := job.Job{State: job.QUEUED}
 
 	// when
-	sut.Finish()
+	sut.Start(at)
 
 	// then
-	if !sut.Finished {
-		t.Errorf("must be true, but false")
+	if !cmp.Equal(sut, want) {
+		t.Errorf("must be equal, but %+v", cmp.Diff(sut, want))
+	}
+}
+
+func TestJob_End(t *testing.T) {
+	// given
+	code := int64(1)
+	at := time.Unix(30, 0)
+
+	// and
+	want := job.Job{State: job.FAILURE, ExitCode: &code, FinishedAt: &at, Finished: true}
+
+	// and
+	sut := job.Job{State: job.RUNNING}
+
+	// when
+	sut.End(job.Result{State: job.FAILURE, ExitCode: &code}, at)
+
+	// then
+	if !cmp.Equal(sut, want) {
+		t.Errorf("must be equal, but %+v", cmp.Diff(sut, want))
+	}
+}
+
+func TestJob_IsRerunnable(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		name string
+		sut  *job.Job
+		want bool
+	}{
+		{
+			name: "when finished with source",
+			sut:  &job.Job{Finished: true, Trigger: &job.Trigger{Source: &job.Source{}}},
+			want: true,
+		},
+		{
+			name: "when not finished",
+			sut:  &job.Job{Trigger: &job.Trigger{Source: &job.Source{}}},
+			want: false,
+		},
+		{
+			name: "when stored without source",
+			sut:  &job.Job{Finished: true, Trigger: &job.Trigger{}},
+			want: false,
+		},
+		{
+			name: "when stored without trigger",
+			sut:  &job.Job{Finished: true},
+			want: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			got := tt.sut.IsRerunnable()
+
+			// then
+			if got != tt.want {
+				t.Errorf("must be %t, but got %t", tt.want, got)
+			}
+		})
 	}
 }
 
 func TestJob_ToBytes(t *testing.T) {
 	t.Run("when success marshal", func(t *testing.T) {
 		// given
-		want := []byte("{\"ID\":[0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0],\"finished\":false,\"stream\":[]}")
+		want := []byte("{\"ID\":[0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0],\"finished\":false}")
 
 		// and
 		sut := job.Job{
 			ID:       job.ID(uuid.Nil),
 			Finished: false,
-			Stream:   []job.LogLine{},
 		}
 
 		// when
@@ -0,0 +1,84 @@
+package job
+
+import (
+	"fmt"
+	"sync"
+	"unicode/utf8"
+)
+
+// LineTruncationMarker is appended to a line shortened to the max line length.
+const LineTruncationMarker = " ... (line truncated)"
+
+// LogLimit represents caps of log stored per job. Zero value means no limit.
+type LogLimit struct {
+	MaxSize       int64
+	MaxLineLength int
+}
+
+// LogLimiter applies LogLimit to lines of a job. It is safe for concurrent use.
+type LogLimiter struct {
+	mu        sync.Mutex
+	limit     LogLimit
+	size      int64
+	exceeded  bool
+	truncated bool
+}
+
+// NewLogLimiter returns a LogLimiter starting with empty log.
+func NewLogLimiter(limit LogLimit) *LogLimiter {
+	return &LogLimiter{limit: limit}
+}
+
+// Limit returns the line to store and whether to store it.
+// A line longer than max line length is shortened, and the first line over max size is replaced with a marker.
+// Any lines after that must not be stored.
+func (l *LogLimiter) Limit(line LogLine) (LogLine, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.exceeded {
+		return line, false
+	}
+
+	if l.limit.MaxLineLength > 0 && len(line.Message) > l.limit.MaxLineLength {
+		line.Message = cut(line.Message, l.limit.MaxLineLength) + LineTruncationMarker
+		l.truncated = true
+	}
+
+	size := int64(len(line.Message))
+	if l.limit.MaxSize > 0 && l.size+size > l.limit.MaxSize {
+		l.exceeded = true
+		l.truncated = true
+		return LogLine{
+			Timestamp: line.Timestamp,
+			Stream:    SYSTEM,
+			Message:   fmt.Sprintf("log truncated: exceeded %d bytes", l.limit.MaxSize),
+		}, true
+	}
+	l.size += size
+	return line, true
+}
+
+// Exceeded returns whether log reached max size.
+func (l *LogLimiter) Exceeded() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.exceeded
+}
+
+// Truncated returns whether any part of log was not stored.
+func (l *LogLimiter) Truncated() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.truncated
+}
+
+// cut returns the head of message not longer than n bytes, without breaking a character.
+func cut(message string, n int) string {
+	for n > 0 && !utf8.RuneStart(message[n]) {
+		n--
+	}
+	return message[:n]
+}
@@ -0,0 +1,129 @@
+package job_test
+
+import (
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/google/go-cmp/cmp"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLogLimiter_Limit(t *testing.T) {
+	// given
+	now := time.Now()
+
+	// where
+	for _, tt := range []struct {
+		name          string
+		limit         job.LogLimit
+		given         []string
+		want          []job.LogLine
+		wantExceeded  bool
+		wantTruncated bool
+	}{
+		{
+			name:  "with no limit",
+			limit: job.LogLimit{},
+			given: []string{"hello", "world"},
+			want: []job.LogLine{
+				{Timestamp: now, Stream: job.STDOUT, Message: "hello"},
+				{Timestamp: now, Stream: job.STDOUT, Message: "world"},
+			},
+		},
+		{
+			name:  "with long line",
+			limit: job.LogLimit{MaxLineLength: 5},
+			given: []string{"hello world", "hello"},
+			want: []job.LogLine{
+				{Timestamp: now, Stream: job.STDOUT, Message: "hello" + job.LineTruncationMarker},
+				{Timestamp: now, Stream: job.STDOUT, Message: "hello"},
+			},
+			wantTruncated: true,
+		},
+		{
+			name:  "with long line of multi-byte characters",
+			limit: job.LogLimit{MaxLineLength: 4},
+			given: []string{"ダック"},
+			want: []job.LogLine{
+				{Timestamp: now, Stream: job.STDOUT, Message: "ダ" + job.LineTruncationMarker},
+			},
+			wantTruncated: true,
+		},
+		{
+			name:  "with log over max size",
+			limit: job.LogLimit{MaxSize: 10},
+			given: []string{"hello", "world", "over", "the limit"},
+			want: []job.LogLine{
+				{Timestamp: now, Stream: job.STDOUT, Message: "hello"},
+				{Timestamp: now, Stream: job.STDOUT, Message: "world"},
+				{Timestamp: now, Stream: job.SYSTEM, Message: "log truncated: exceeded 10 bytes"},
+			},
+			wantExceeded:  true,
+			wantTruncated: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			sut := job.NewLogLimiter(tt.limit)
+
+			// when
+			var got []job.LogLine
+			for _, msg := range tt.given {
+				if line, ok := sut.Limit(job.LogLine{Timestamp: now, Stream: job.STDOUT, Message: msg}); ok {
+					got = append(got, line)
+				}
+			}
+
+			// then
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("must be equal, but %+v", cmp.Diff(got, tt.want))
+			}
+
+			// and
+			if sut.Exceeded() != tt.wantExceeded {
+				t.Errorf("exceeded must be %t, but got %t", tt.wantExceeded, sut.Exceeded())
+			}
+
+			// and
+			if sut.Truncated() != tt.wantTruncated {
+				t.Errorf("truncated must be %t, but got %t", tt.wantTruncated, sut.Truncated())
+			}
+		})
+	}
+}
+
+func TestLogLimiter_Limit_Concurrently(t *testing.T) {
+	// given
+	sut := job.NewLogLimiter(job.LogLimit{MaxSize: 100})
+
+	// when
+	stored := make(chan job.LogLine, 200)
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if line, ok := sut.Limit(job.LogLine{Stream: job.STDOUT, Message: "a"}); ok {
+				stored <- line
+			}
+		}()
+	}
+	wg.Wait()
+	close(stored)
+
+	// then
+	var size int
+	for line := range stored {
+		if line.Stream == job.STDOUT {
+			size += len(line.Message)
+		}
+	}
+	if size != 100 {
+		t.Errorf("stored size must be 100, but got %d", size)
+	}
+
+	// and
+	if !sut.Exceeded() {
+		t.Error("must be exceeded")
+	}
+}
@@ -1,14 +1,59 @@
 package job
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Log is a interface represents docker log.
 type Log interface {
 	ReadLine() (*LogLine, error)
 }
 
-// LogLine stores timestamp and message.
+// LogStream represents where a log line comes from.
+type LogStream string
+
+const (
+	// STDOUT represents standard output of container.
+	STDOUT LogStream = "stdout"
+	// STDERR represents standard error of container.
+	STDERR LogStream = "stderr"
+	// BUILD represents output of building image.
+	BUILD LogStream = "build"
+	// GIT represents output of cloning repository.
+	GIT LogStream = "git"
+	// SYSTEM represents message from duci or docker daemon.
+	SYSTEM LogStream = "system"
+)
+
+// LogLine stores timestamp, source and message.
 type LogLine struct {
 	Timestamp time.Time `json:"time"`
+	Stream    LogStream `json:"stream,omitempty"`
 	Message   string    `json:"message"`
 }
+
+// LogEvent represents a log line appended to job or end of job.
+type LogEvent struct {
+	Seq      int
+	Line     LogLine
+	Finished bool
+}
+
+// Collapse returns the final state of a message rewritten by carriage returns, as a terminal shows.
+func Collapse(message string) string {
+	if !strings.Contains(message, "\r") {
+		return message
+	}
+
+	var screen []rune
+	for _, segment := range strings.Split(message, "\r") {
+		overwrite := []rune(segment)
+		if len(overwrite) >= len(screen) {
+			screen = overwrite
+		} else {
+			copy(screen, overwrite)
+		}
+	}
+	return string(screen)
+}
@@ -0,0 +1,51 @@
+package job_test
+
+import (
+	"github.com/duck8823/duci/domain/model/job"
+	"testing"
+)
+
+func TestCollapse(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "without carriage return",
+			message: "hello world",
+			want:    "hello world",
+		},
+		{
+			name:    "with progress updates",
+			message: "Receiving objects:  10% (1/10)\rReceiving objects: 100% (10/10), done.",
+			want:    "Receiving objects: 100% (10/10), done.",
+		},
+		{
+			name:    "with shorter update",
+			message: "hello world\rHELLO",
+			want:    "HELLO world",
+		},
+		{
+			name:    "with trailing carriage return",
+			message: "50%\r100%\r",
+			want:    "100%",
+		},
+		{
+			name:    "with multibyte characters",
+			message: "あいうえお\rかき",
+			want:    "かきうえお",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			got := job.Collapse(tt.message)
+
+			// then
+			if got != tt.want {
+				t.Errorf("must be %s, but got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package job
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Mask is a replacement of secrets in log.
+const Mask = "***"
+
+// minSecretLength is the length of the shortest value to mask, to keep common words such as `true` readable.
+const minSecretLength = 6
+
+// tokenPatterns are well-known formats of credentials masked even if they are not configured.
+var tokenPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`), Mask},
+	{regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`), Mask},
+	{regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`), Mask},
+	{regexp.MustCompile(`(?i)\b(authorization:\s*(?:bearer|basic|token)\s+)\S+`), "${1}" + Mask},
+	{regexp.MustCompile(`(?i)\b([a-z][a-z0-9+.-]*://)[^/\s:@]+(?::[^/\s@]*)?@`), "${1}" + Mask + "@"},
+}
+
+// Masker replaces secrets in log messages with Mask.
+type Masker struct {
+	replacer *strings.Replacer
+}
+
+// NewMasker returns a Masker of the secrets.
+// Each line of multi-line secrets is masked, and values shorter than 6 characters are ignored.
+func NewMasker(secrets ...string) *Masker {
+	var values []string
+	for _, secret := range secrets {
+		for _, value := range strings.Split(secret, "\n") {
+			value = strings.TrimSpace(value)
+			if len(value) >= minSecretLength {
+				values = append(values, value)
+			}
+		}
+	}
+	if len(values) == 0 {
+		return &Masker{}
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	var oldnew []string
+	for _, value := range values {
+		oldnew = append(oldnew, value, Mask)
+	}
+	return &Masker{replacer: strings.NewReplacer(oldnew...)}
+}
+
+// Mask returns the message with secrets and well-known tokens replaced.
+func (m *Masker) Mask(message string) string {
+	if m.replacer != nil {
+		message = m.replacer.Replace(message)
+	}
+	for _, token := range tokenPatterns {
+		message = token.pattern.ReplaceAllString(message, token.replacement)
+	}
+	return message
+}
+
+// Log returns a Log whose lines are masked.
+func (m *Masker) Log(log Log) Log {
+	return &maskedLog{log: log, masker: m}
+}
+
+type maskedLog struct {
+	log    Log
+	masker *Masker
+}
+
+// ReadLine returns LogLine with masked message.
+func (l *maskedLog) ReadLine() (*LogLine, error) {
+	line, err := l.log.ReadLine()
+	if line != nil {
+		masked := *line
+		masked.Message = l.masker.Mask(line.Message)
+		line = &masked
+	}
+	return line, err
+}
@@ -46,6 +46,31 @@ func (mr *MockRepositoryMockRecorder) FindBy(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBy", reflect.TypeOf((*MockRepository)(nil).FindBy), arg0)
 }
 
+// FindLogs mocks base method
+func (m *MockRepository) FindLogs(id job.ID, from, to int) ([]job.LogLine, error) {
+	ret := m.ctrl.Call(m, "FindLogs", id, from, to)
+	ret0, _ := ret[0].([]job.LogLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLogs indicates an expected call of FindLogs
+func (mr *MockRepositoryMockRecorder) FindLogs(id, from, to interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLogs", reflect.TypeOf((*MockRepository)(nil).FindLogs), id, from, to)
+}
+
+// ReverseLogs mocks base method
+func (m *MockRepository) ReverseLogs(id job.ID, from, to int, visit func(int, job.LogLine) bool) error {
+	ret := m.ctrl.Call(m, "ReverseLogs", id, from, to, visit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReverseLogs indicates an expected call of ReverseLogs
+func (mr *MockRepositoryMockRecorder) ReverseLogs(id, from, to, visit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseLogs", reflect.TypeOf((*MockRepository)(nil).ReverseLogs), id, from, to, visit)
+}
+
 // Save mocks base method
 func (m *MockRepository) Save(arg0 job.Job) error {
 	ret := m.ctrl.Call(m, "Save", arg0)
@@ -57,3 +82,92 @@ func (m *MockRepository) Save(arg0 job.Job) error {
 func (mr *MockRepositoryMockRecorder) Save(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRepository)(nil).Save), arg0)
 }
+
+// AppendLog mocks base method
+func (m *MockRepository) AppendLog(arg0 job.ID, arg1 job.LogLine) (int, error) {
+	ret := m.ctrl.Call(m, "AppendLog", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppendLog indicates an expected call of AppendLog
+func (mr *MockRepositoryMockRecorder) AppendLog(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendLog", reflect.TypeOf((*MockRepository)(nil).AppendLog), arg0, arg1)
+}
+
+// Search mocks base method
+func (m *MockRepository) Search(arg0 job.Query) (*job.Page, error) {
+	ret := m.ctrl.Call(m, "Search", arg0)
+	ret0, _ := ret[0].(*job.Page)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search
+func (mr *MockRepositoryMockRecorder) Search(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockRepository)(nil).Search), arg0)
+}
+
+// All mocks base method
+func (m *MockRepository) All() ([]job.Job, error) {
+	ret := m.ctrl.Call(m, "All")
+	ret0, _ := ret[0].([]job.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// All indicates an expected call of All
+func (mr *MockRepositoryMockRecorder) All() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "All", reflect.TypeOf((*MockRepository)(nil).All))
+}
+
+// Delete mocks base method
+func (m *MockRepository) Delete(arg0 job.ID) error {
+	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockRepositoryMockRecorder) Delete(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), arg0)
+}
+
+// SizeOf mocks base method
+func (m *MockRepository) SizeOf(arg0 job.ID) (int64, error) {
+	ret := m.ctrl.Call(m, "SizeOf", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SizeOf indicates an expected call of SizeOf
+func (mr *MockRepositoryMockRecorder) SizeOf(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SizeOf", reflect.TypeOf((*MockRepository)(nil).SizeOf), arg0)
+}
+
+// Size mocks base method
+func (m *MockRepository) Size() (int64, error) {
+	ret := m.ctrl.Call(m, "Size")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Size indicates an expected call of Size
+func (mr *MockRepositoryMockRecorder) Size() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Size", reflect.TypeOf((*MockRepository)(nil).Size))
+}
+
+// Compact mocks base method
+func (m *MockRepository) Compact() error {
+	ret := m.ctrl.Call(m, "Compact")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compact indicates an expected call of Compact
+func (mr *MockRepositoryMockRecorder) Compact() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compact", reflect.TypeOf((*MockRepository)(nil).Compact))
+}
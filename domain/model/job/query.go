@@ -0,0 +1,44 @@
+package job
+
+import "time"
+
+// Query represents conditions to search jobs
+type Query struct {
+	Repository string
+	Ref        string
+	SHA        string
+	State      State
+	Since      time.Time
+	Until      time.Time
+	Cursor     string
+	Limit      int
+}
+
+// Match returns whether the job satisfies conditions other than the time range
+func (q Query) Match(j *Job) bool {
+	if len(q.State) > 0 && j.State != q.State {
+		return false
+	}
+	if len(q.Repository) == 0 && len(q.Ref) == 0 && len(q.SHA) == 0 {
+		return true
+	}
+	if j.Trigger == nil {
+		return false
+	}
+	if len(q.Repository) > 0 && j.Trigger.Repository != q.Repository {
+		return false
+	}
+	if len(q.Ref) > 0 && j.Trigger.Ref != q.Ref {
+		return false
+	}
+	if len(q.SHA) > 0 && j.Trigger.SHA != q.SHA {
+		return false
+	}
+	return true
+}
+
+// Page represents a part of search result
+type Page struct {
+	Jobs []Job
+	Next string
+}
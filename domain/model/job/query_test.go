@@ -0,0 +1,63 @@
+package job_test
+
+import (
+	"github.com/duck8823/duci/domain/model/job"
+	"testing"
+)
+
+func TestQuery_Match(t *testing.T) {
+	// given
+	j := &job.Job{
+		Trigger: &job.Trigger{Repository: "duck8823/duci", Ref: "refs/heads/master", SHA: "abc"},
+		State:   job.SUCCESS,
+	}
+
+	// where
+	for _, tt := range []struct {
+		name string
+		sut  job.Query
+		in   *job.Job
+		want bool
+	}{
+		{
+			name: "without conditions",
+			sut:  job.Query{},
+			in:   j,
+			want: true,
+		},
+		{
+			name: "with matched conditions",
+			sut:  job.Query{Repository: "duck8823/duci", Ref: "refs/heads/master", SHA: "abc", State: job.SUCCESS},
+			in:   j,
+			want: true,
+		},
+		{
+			name: "with different ref",
+			sut:  job.Query{Ref: "refs/heads/feature"},
+			in:   j,
+			want: false,
+		},
+		{
+			name: "with different state",
+			sut:  job.Query{State: job.FAILURE},
+			in:   j,
+			want: false,
+		},
+		{
+			name: "when job has no trigger",
+			sut:  job.Query{SHA: "abc"},
+			in:   &job.Job{},
+			want: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			got := tt.sut.Match(tt.in)
+
+			// then
+			if got != tt.want {
+				t.Errorf("must be %t, but got %t", tt.want, got)
+			}
+		})
+	}
+}
@@ -5,8 +5,23 @@ import "errors"
 // ErrNotFound represents a job not found error
 var ErrNotFound = errors.New("job not found")
 
-// Repository is Job Repository
+// ErrInvalidCursor represents a error of malformed cursor
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Repository is Job Repository.
+// Metadata of job and log lines are stored apart, so FindBy and Save do not read or write the stream.
+// Search finds only jobs queued by this version, and All returns every job including ones stored by older versions.
+// Sizes are approximate bytes on disk.
 type Repository interface {
 	FindBy(ID) (*Job, error)
+	FindLogs(id ID, from int, to int) ([]LogLine, error)
+	ReverseLogs(id ID, from int, to int, visit func(seq int, line LogLine) bool) error
 	Save(Job) error
+	AppendLog(ID, LogLine) (seq int, err error)
+	Search(Query) (*Page, error)
+	All() ([]Job, error)
+	Delete(ID) error
+	SizeOf(ID) (int64, error)
+	Size() (int64, error)
+	Compact() error
 }
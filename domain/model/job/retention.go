@@ -0,0 +1,22 @@
+package job
+
+import "time"
+
+// Retention represents limits to keep finished jobs. Zero value means no limit.
+type Retention struct {
+	MaxAge  time.Duration
+	MaxJobs int
+	MaxSize int64
+}
+
+// IsZero returns whether no limit is set
+func (r Retention) IsZero() bool {
+	return r.MaxAge <= 0 && r.MaxJobs <= 0 && r.MaxSize <= 0
+}
+
+// Garbage represents a job removed by retention
+type Garbage struct {
+	Job    Job
+	Size   int64
+	Reason string
+}
@@ -0,0 +1,44 @@
+package job
+
+// State represents state of job
+type State string
+
+// String returns string value
+func (s State) String() string {
+	return string(s)
+}
+
+const (
+	// QUEUED represents queued state.
+	QUEUED State = "queued"
+	// RUNNING represents running state.
+	RUNNING State = "running"
+	// SUCCESS represents success state.
+	SUCCESS State = "success"
+	// FAILURE represents failure state.
+	FAILURE State = "failure"
+	// ERROR represents error state.
+	ERROR State = "error"
+	// TIMEOUT represents timeout state.
+	TIMEOUT State = "timeout"
+	// CANCELLED represents cancelled state.
+	CANCELLED State = "cancelled"
+	// SKIPPED represents skipped state by failure of the stage the job needs.
+	SKIPPED State = "skipped"
+)
+
+var states = map[State]bool{
+	QUEUED:    true,
+	RUNNING:   true,
+	SUCCESS:   true,
+	FAILURE:   true,
+	ERROR:     true,
+	TIMEOUT:   true,
+	CANCELLED: true,
+	SKIPPED:   true,
+}
+
+// IsValid returns whether known state or not
+func (s State) IsValid() bool {
+	return states[s]
+}
@@ -0,0 +1,27 @@
+package job_test
+
+import (
+	"github.com/duck8823/duci/domain/model/job"
+	"testing"
+)
+
+func TestState_IsValid(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		in   job.State
+		want bool
+	}{
+		{in: job.QUEUED, want: true},
+		{in: job.SKIPPED, want: true},
+		{in: job.State("bogus"), want: false},
+		{in: job.State(""), want: false},
+	} {
+		// when
+		got := tt.in.IsValid()
+
+		// then
+		if got != tt.want {
+			t.Errorf("%s want: %t, but got: %t", tt.in, tt.want, got)
+		}
+	}
+}
@@ -10,8 +10,9 @@ import (
 
 var plainClone = git.PlainClone
 
-// TargetSource is a interface returns clone URLs, Ref and SHA for target
+// TargetSource is a interface returns repository name, clone URLs, Ref and SHA for target
 type TargetSource interface {
+	GetFullName() string
 	GetSSHURL() string
 	GetCloneURL() string
 	GetRef() string
@@ -19,17 +19,22 @@ type httpGitClient struct {
 // InitializeWithHTTP initialize git client with http protocol
 func InitializeWithHTTP(token string, logFunc runner.LogFunc) error {
 	git := new(Git)
-	*git = &httpGitClient{
+	*git = NewWithHTTP(token, logFunc)
+	if err := container.Submit(git); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// NewWithHTTP returns git client with http protocol
+func NewWithHTTP(token string, logFunc runner.LogFunc) Git {
+	return &httpGitClient{
 		auth: &http.BasicAuth{
 			Username: "abc123",
 			Password: token,
 		},
 		LogFunc: logFunc,
 	}
-	if err := container.Submit(git); err != nil {
-		return errors.WithStack(err)
-	}
-	return nil
 }
 
 // Clone a repository into the path with target source.
@@ -6,7 +6,8 @@ import (
 	"context"
 	"github.com/duck8823/duci/domain/model/job"
 	"github.com/duck8823/duci/domain/model/runner"
-	"regexp"
+	"io"
+	"strings"
 	"time"
 )
 
@@ -17,31 +18,35 @@ type cloneLogger struct {
 }
 
 // ReadLine returns LogLine.
+// A line rewritten by carriage returns is collapsed to the final state,
+// and a progress not terminated by line break is skipped because it will be rewritten.
 func (l *cloneLogger) ReadLine() (*job.LogLine, error) {
 	for {
-		line, _, err := l.reader.ReadLine()
-		if err != nil {
+		line, err := l.reader.ReadString('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
 			return nil, err
 		}
 
-		if len(line) == 0 || rep.Match(line) {
+		if err == io.EOF && strings.HasSuffix(line, "\r") {
 			continue
 		}
 
-		return &job.LogLine{Timestamp: now(), Message: string(line)}, nil
+		msg := job.Collapse(strings.TrimRight(line, "\r\n"))
+		if len(msg) == 0 {
+			continue
+		}
+
+		return &job.LogLine{Timestamp: now(), Stream: job.GIT, Message: msg}, nil
 	}
 }
 
-// Regexp to remove CR or later (inline progress)
-var rep = regexp.MustCompile("\r.*$")
-
 // ProgressLogger is a writer for git progress
 type ProgressLogger struct {
 	ctx context.Context
 	runner.LogFunc
 }
 
-// Write a log without CR or later.
+// Write a log collapsing carriage returns.
 func (l *ProgressLogger) Write(p []byte) (n int, err error) {
 	log := &cloneLogger{
 		reader: bufio.NewReader(bytes.NewReader(p)),
@@ -23,6 +23,7 @@ func TestCloneLogger_ReadLine(t *testing.T) {
 	// and
 	want := &job.LogLine{
 		Timestamp: now,
+		Stream:    job.GIT,
 		Message:   "Hello World",
 	}
 
@@ -84,3 +85,57 @@ func TestProgressLogger_Write(t *testing.T) {
 		t.Errorf("must be equal, but not\n%+v", cmp.Diff(got, want))
 	}
 }
+
+func TestCloneLogger_ReadLine_WithProgress(t *testing.T) {
+	// given
+	now := time.Now()
+	defer git.SetNowFunc(func() time.Time {
+		return now
+	})()
+
+	// where
+	for _, tt := range []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "with progress completed",
+			input: "Counting objects:  50% (1/2)\rCounting objects: 100% (2/2), done.\n",
+			want:  []string{"Counting objects: 100% (2/2), done."},
+		},
+		{
+			name:  "with progress in the middle",
+			input: "Enumerating objects: 2, done.\nCounting objects:  50% (1/2)\r",
+			want:  []string{"Enumerating objects: 2, done."},
+		},
+		{
+			name:  "with crlf",
+			input: "hello world\r\n",
+			want:  []string{"hello world"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			sut := &git.CloneLogger{}
+			defer sut.SetReader(bufio.NewReader(strings.NewReader(tt.input)))()
+
+			// when
+			var got []string
+			line, err := sut.ReadLine()
+			for ; err == nil; line, err = sut.ReadLine() {
+				got = append(got, line.Message)
+			}
+
+			// then
+			if err != io.EOF {
+				t.Errorf("must be equal io.EOF, but got %+v", err)
+			}
+
+			// and
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("must be equal, but %+v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
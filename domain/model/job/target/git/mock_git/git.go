@@ -35,6 +35,18 @@ func (m *MockTargetSource) EXPECT() *MockTargetSourceMockRecorder {
 	return m.recorder
 }
 
+// GetFullName mocks base method
+func (m *MockTargetSource) GetFullName() string {
+	ret := m.ctrl.Call(m, "GetFullName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetFullName indicates an expected call of GetFullName
+func (mr *MockTargetSourceMockRecorder) GetFullName() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFullName", reflect.TypeOf((*MockTargetSource)(nil).GetFullName))
+}
+
 // GetSSHURL mocks base method
 func (m *MockTargetSource) GetSSHURL() string {
 	ret := m.ctrl.Call(m, "GetSSHURL")
@@ -0,0 +1,33 @@
+package git
+
+import (
+	"context"
+	"github.com/duck8823/duci/internal/container"
+	"github.com/pkg/errors"
+)
+
+// Route returns a git client for the repository, or false to use default client.
+type Route func(fullName string) (Git, bool)
+
+type router struct {
+	defaults Git
+	route    Route
+}
+
+// InitializeWithRouter initialize git client switching clients for each repository
+func InitializeWithRouter(defaults Git, route Route) error {
+	git := new(Git)
+	*git = &router{defaults: defaults, route: route}
+	if err := container.Submit(git); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Clone a repository with the client routed by repository name.
+func (r *router) Clone(ctx context.Context, dir string, src TargetSource) error {
+	if git, ok := r.route(src.GetFullName()); ok {
+		return git.Clone(ctx, dir, src)
+	}
+	return r.defaults.Clone(ctx, dir, src)
+}
@@ -0,0 +1,126 @@
+package git_test
+
+import (
+	"context"
+	"github.com/duck8823/duci/domain/model/job/target/git"
+	"github.com/duck8823/duci/domain/model/job/target/git/mock_git"
+	"github.com/duck8823/duci/internal/container"
+	"github.com/golang/mock/gomock"
+	"testing"
+)
+
+func TestInitializeWithRouter(t *testing.T) {
+	t.Run("when instance is nil", func(t *testing.T) {
+		// given
+		container.Clear()
+
+		// when
+		err := git.InitializeWithRouter(&git.HTTPGitClient{}, func(string) (git.Git, bool) {
+			return nil, false
+		})
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("when instance is not nil", func(t *testing.T) {
+		// given
+		container.Override(&git.HTTPGitClient{})
+		defer container.Clear()
+
+		// when
+		err := git.InitializeWithRouter(&git.HTTPGitClient{}, func(string) (git.Git, bool) {
+			return nil, false
+		})
+
+		// then
+		if err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+}
+
+func TestRouter_Clone(t *testing.T) {
+	t.Run("when route found", func(t *testing.T) {
+		// given
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		src := mock_git.NewMockTargetSource(ctrl)
+		src.EXPECT().
+			GetFullName().
+			Times(1).
+			Return("duck8823/duci")
+
+		// and
+		defaults := mock_git.NewMockGit(ctrl)
+		defaults.EXPECT().
+			Clone(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		routed := mock_git.NewMockGit(ctrl)
+		routed.EXPECT().
+			Clone(gomock.Any(), gomock.Eq("/path/to/dir"), gomock.Eq(src)).
+			Times(1).
+			Return(nil)
+
+		// and
+		container.Clear()
+		defer container.Clear()
+		if err := git.InitializeWithRouter(defaults, func(fullName string) (git.Git, bool) {
+			return routed, fullName == "duck8823/duci"
+		}); err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		sut, err := git.GetInstance()
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+
+		// expect
+		if err := sut.Clone(context.Background(), "/path/to/dir", src); err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("when route not found", func(t *testing.T) {
+		// given
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		src := mock_git.NewMockTargetSource(ctrl)
+		src.EXPECT().
+			GetFullName().
+			Times(1).
+			Return("duck8823/other")
+
+		// and
+		defaults := mock_git.NewMockGit(ctrl)
+		defaults.EXPECT().
+			Clone(gomock.Any(), gomock.Eq("/path/to/dir"), gomock.Eq(src)).
+			Times(1).
+			Return(nil)
+
+		// and
+		container.Clear()
+		defer container.Clear()
+		if err := git.InitializeWithRouter(defaults, func(string) (git.Git, bool) {
+			return nil, false
+		}); err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		sut, err := git.GetInstance()
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+
+		// expect
+		if err := sut.Clone(context.Background(), "/path/to/dir", src); err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+}
@@ -18,19 +18,28 @@ type sshGitClient struct {
 
 // InitializeWithSSH returns git client with ssh protocol
 func InitializeWithSSH(path string, logFunc runner.LogFunc) error {
-	auth, err := ssh.NewPublicKeysFromFile("git", path, "")
+	cli, err := NewWithSSH(path, logFunc)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
 	git := new(Git)
-	*git = &sshGitClient{auth: auth, LogFunc: logFunc}
+	*git = cli
 	if err := container.Submit(git); err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
 }
 
+// NewWithSSH returns git client with ssh protocol
+func NewWithSSH(path string, logFunc runner.LogFunc) (Git, error) {
+	auth, err := ssh.NewPublicKeysFromFile("git", path, "")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &sshGitClient{auth: auth, LogFunc: logFunc}, nil
+}
+
 // Clone a repository into the path with target source.
 func (s *sshGitClient) Clone(ctx context.Context, dir string, src TargetSource) error {
 	gitRepository, err := plainClone(dir, false, &git.CloneOptions{
@@ -16,6 +16,26 @@ func (*StubClient) CreateCommitStatus(ctx context.Context, status CommitStatus)
 	return nil
 }
 
+func (*StubClient) GetPermissionLevel(ctx context.Context, repo Repository, user string) (Permission, error) {
+	return NONE, nil
+}
+
+func (*StubClient) IsTeamMember(ctx context.Context, team TeamName, user string) (bool, error) {
+	return false, nil
+}
+
+func (*StubClient) CreateComment(ctx context.Context, repo Repository, num int, body string) error {
+	return nil
+}
+
+func (*StubClient) CreateCommentReaction(ctx context.Context, repo Repository, id int64, content string) error {
+	return nil
+}
+
+func (*StubClient) GetContent(ctx context.Context, repo Repository, ref string, path string) ([]byte, error) {
+	return nil, nil
+}
+
 type MockRepository struct {
 	FullName string
 	URL      string
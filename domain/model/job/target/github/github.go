@@ -2,35 +2,50 @@ package github
 
 import (
 	"context"
+	"fmt"
 	"github.com/duck8823/duci/internal/container"
 	go_github "github.com/google/go-github/github"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
+	"net/http"
 )
 
 // GitHub describes a github client.
 type GitHub interface {
 	GetPullRequest(ctx context.Context, repo Repository, num int) (*go_github.PullRequest, error)
 	CreateCommitStatus(ctx context.Context, status CommitStatus) error
+	GetPermissionLevel(ctx context.Context, repo Repository, user string) (Permission, error)
+	IsTeamMember(ctx context.Context, team TeamName, user string) (bool, error)
+	CreateComment(ctx context.Context, repo Repository, num int, body string) error
+	CreateCommentReaction(ctx context.Context, repo Repository, id int64, content string) error
+	GetContent(ctx context.Context, repo Repository, ref string, path string) ([]byte, error)
 }
 
+// ErrContentNotFound represents a error of file not found in the repository.
+var ErrContentNotFound = errors.New("content not found")
+
 type client struct {
 	cli *go_github.Client
 }
 
 // Initialize create a github client.
 func Initialize(token string) error {
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(context.Background(), ts)
-
 	github := new(GitHub)
-	*github = &client{go_github.NewClient(tc)}
+	*github = New(token)
 	if err := container.Submit(github); err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
 }
 
+// New returns a github client with the token.
+func New(token string) GitHub {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	tc := oauth2.NewClient(context.Background(), ts)
+
+	return &client{go_github.NewClient(tc)}
+}
+
 // GetInstance returns a github client
 func GetInstance() (GitHub, error) {
 	github := new(GitHub)
@@ -84,3 +99,127 @@ func (c *client) CreateCommitStatus(ctx context.Context, status CommitStatus) er
 	}
 	return nil
 }
+
+// GetPermissionLevel returns a permission level of the user in the repository.
+func (c *client) GetPermissionLevel(ctx context.Context, repo Repository, user string) (Permission, error) {
+	ownerName, repoName, err := RepositoryName(repo.GetFullName()).Split()
+	if err != nil {
+		return NONE, errors.WithStack(err)
+	}
+
+	req, err := c.cli.NewRequest(
+		"GET",
+		fmt.Sprintf("repos/%s/%s/collaborators/%s/permission", ownerName, repoName, user),
+		nil,
+	)
+	if err != nil {
+		return NONE, errors.WithStack(err)
+	}
+
+	// role_name distinguishes maintain and triage from write and read
+	level := &struct {
+		Permission string `json:"permission"`
+		RoleName   string `json:"role_name"`
+	}{}
+	if _, err := c.cli.Do(ctx, req, level); err != nil {
+		return NONE, errors.WithStack(err)
+	}
+
+	if perm := Permission(level.RoleName); perm.IsValid() {
+		return perm, nil
+	}
+	return Permission(level.Permission), nil
+}
+
+// IsTeamMember returns whether the user is an active member of the team.
+func (c *client) IsTeamMember(ctx context.Context, team TeamName, user string) (bool, error) {
+	org, slug, err := team.Split()
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	req, err := c.cli.NewRequest(
+		"GET",
+		fmt.Sprintf("orgs/%s/teams/%s/memberships/%s", org, slug, user),
+		nil,
+	)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	membership := &go_github.Membership{}
+	if resp, err := c.cli.Do(ctx, req, membership); resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return membership.GetState() == "active", nil
+}
+
+// CreateComment create a comment to the issue or pull request.
+func (c *client) CreateComment(ctx context.Context, repo Repository, num int, body string) error {
+	ownerName, repoName, err := RepositoryName(repo.GetFullName()).Split()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if _, _, err := c.cli.Issues.CreateComment(
+		ctx,
+		ownerName,
+		repoName,
+		num,
+		&go_github.IssueComment{Body: go_github.String(body)},
+	); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// CreateCommentReaction create a reaction to the issue or pull request comment.
+func (c *client) CreateCommentReaction(ctx context.Context, repo Repository, id int64, content string) error {
+	ownerName, repoName, err := RepositoryName(repo.GetFullName()).Split()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if _, _, err := c.cli.Reactions.CreateIssueCommentReaction(
+		ctx,
+		ownerName,
+		repoName,
+		id,
+		content,
+	); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// GetContent returns a content of the file at the ref. It returns ErrContentNotFound if the file does not exist.
+func (c *client) GetContent(ctx context.Context, repo Repository, ref string, path string) ([]byte, error) {
+	ownerName, repoName, err := RepositoryName(repo.GetFullName()).Split()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	file, _, resp, err := c.cli.Repositories.GetContents(
+		ctx,
+		ownerName,
+		repoName,
+		path,
+		&go_github.RepositoryContentGetOptions{Ref: ref},
+	)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, ErrContentNotFound
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if file == nil {
+		return nil, errors.Errorf("%s is not a file", path)
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return []byte(content), nil
+}
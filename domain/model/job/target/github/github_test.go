@@ -264,3 +264,335 @@ func TestClient_CreateCommitStatus(t *testing.T) {
 		}
 	})
 }
+
+func TestClient_GetPermissionLevel(t *testing.T) {
+	// given
+	_ = github.Initialize("github_api_token")
+	sut, err := github.GetInstance()
+	if err != nil {
+		t.Fatalf("error occurred. %+v", err)
+	}
+
+	t.Run("when github server returns role name", func(t *testing.T) {
+		// given
+		repo := &github.MockRepository{
+			FullName: "duck8823/duci",
+		}
+
+		// and
+		gock.New("https://api.github.com").
+			Get(fmt.Sprintf("/repos/%s/collaborators/%s/permission", repo.FullName, "octocat")).
+			Reply(200).
+			JSON(map[string]string{"permission": "write", "role_name": "maintain"})
+		defer gock.Clean()
+
+		// when
+		got, err := sut.GetPermissionLevel(context.Background(), repo, "octocat")
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if got != github.MAINTAIN {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, github.MAINTAIN))
+		}
+	})
+
+	t.Run("when github server returns only permission", func(t *testing.T) {
+		// given
+		repo := &github.MockRepository{
+			FullName: "duck8823/duci",
+		}
+
+		// and
+		gock.New("https://api.github.com").
+			Get(fmt.Sprintf("/repos/%s/collaborators/%s/permission", repo.FullName, "octocat")).
+			Reply(200).
+			JSON(map[string]string{"permission": "read"})
+		defer gock.Clean()
+
+		// when
+		got, err := sut.GetPermissionLevel(context.Background(), repo, "octocat")
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if got != github.READ {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, github.READ))
+		}
+	})
+
+	t.Run("when github server returns status not found", func(t *testing.T) {
+		// given
+		repo := &github.MockRepository{
+			FullName: "duck8823/duci",
+		}
+
+		// and
+		gock.New("https://api.github.com").
+			Get(fmt.Sprintf("/repos/%s/collaborators/%s/permission", repo.FullName, "octocat")).
+			Reply(404)
+		defer gock.Clean()
+
+		// expect
+		if _, err := sut.GetPermissionLevel(context.Background(), repo, "octocat"); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+
+	t.Run("with invalid repository", func(t *testing.T) {
+		// given
+		repo := &github.MockRepository{
+			FullName: "",
+		}
+
+		// expect
+		if _, err := sut.GetPermissionLevel(context.Background(), repo, "octocat"); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+}
+
+func TestClient_IsTeamMember(t *testing.T) {
+	// given
+	_ = github.Initialize("github_api_token")
+	sut, err := github.GetInstance()
+	if err != nil {
+		t.Fatalf("error occurred. %+v", err)
+	}
+
+	// where
+	for _, tt := range []struct {
+		name    string
+		status  int
+		state   string
+		want    bool
+		wantErr bool
+	}{
+		{name: "when user is active member", status: 200, state: "active", want: true, wantErr: false},
+		{name: "when user is pending member", status: 200, state: "pending", want: false, wantErr: false},
+		{name: "when user is not member", status: 404, want: false, wantErr: false},
+		{name: "when github server returns error", status: 500, want: false, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			gock.New("https://api.github.com").
+				Get("/orgs/duck8823/teams/maintainers/memberships/octocat").
+				Reply(tt.status).
+				JSON(&go_github.Membership{State: go_github.String(tt.state)})
+			defer gock.Clean()
+
+			// when
+			got, err := sut.IsTeamMember(context.Background(), "duck8823/maintainers", "octocat")
+
+			// then
+			if tt.wantErr && err == nil {
+				t.Error("error must not be nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("error must be nil, but got %+v", err)
+			}
+
+			// and
+			if got != tt.want {
+				t.Errorf("must be %t, but got %t", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("with invalid team name", func(t *testing.T) {
+		// expect
+		if _, err := sut.IsTeamMember(context.Background(), "maintainers", "octocat"); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+}
+
+func TestClient_CreateComment(t *testing.T) {
+	// given
+	_ = github.Initialize("github_api_token")
+	sut, err := github.GetInstance()
+	if err != nil {
+		t.Fatalf("error occurred. %+v", err)
+	}
+
+	t.Run("when github server returns status created", func(t *testing.T) {
+		// given
+		repo := &github.MockRepository{
+			FullName: "duck8823/duci",
+		}
+
+		// and
+		gock.New("https://api.github.com").
+			Post(fmt.Sprintf("/repos/%s/issues/%d/comments", repo.FullName, 19)).
+			Reply(201)
+		defer gock.Clean()
+
+		// expect
+		if err := sut.CreateComment(context.Background(), repo, 19, "hello world"); err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("when github server returns status not found", func(t *testing.T) {
+		// given
+		repo := &github.MockRepository{
+			FullName: "duck8823/duci",
+		}
+
+		// and
+		gock.New("https://api.github.com").
+			Post(fmt.Sprintf("/repos/%s/issues/%d/comments", repo.FullName, 19)).
+			Reply(404)
+		defer gock.Clean()
+
+		// expect
+		if err := sut.CreateComment(context.Background(), repo, 19, "hello world"); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+
+	t.Run("with invalid repository", func(t *testing.T) {
+		// given
+		repo := &github.MockRepository{
+			FullName: "",
+		}
+
+		// expect
+		if err := sut.CreateComment(context.Background(), repo, 19, "hello world"); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+}
+
+func TestClient_CreateCommentReaction(t *testing.T) {
+	// given
+	_ = github.Initialize("github_api_token")
+	sut, err := github.GetInstance()
+	if err != nil {
+		t.Fatalf("error occurred. %+v", err)
+	}
+
+	t.Run("when github server returns status created", func(t *testing.T) {
+		// given
+		repo := &github.MockRepository{
+			FullName: "duck8823/duci",
+		}
+
+		// and
+		gock.New("https://api.github.com").
+			Post(fmt.Sprintf("/repos/%s/issues/comments/%d/reactions", repo.FullName, 42)).
+			Reply(201)
+		defer gock.Clean()
+
+		// expect
+		if err := sut.CreateCommentReaction(context.Background(), repo, 42, "confused"); err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("when github server returns status not found", func(t *testing.T) {
+		// given
+		repo := &github.MockRepository{
+			FullName: "duck8823/duci",
+		}
+
+		// and
+		gock.New("https://api.github.com").
+			Post(fmt.Sprintf("/repos/%s/issues/comments/%d/reactions", repo.FullName, 42)).
+			Reply(404)
+		defer gock.Clean()
+
+		// expect
+		if err := sut.CreateCommentReaction(context.Background(), repo, 42, "confused"); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+
+	t.Run("with invalid repository", func(t *testing.T) {
+		// given
+		repo := &github.MockRepository{
+			FullName: "",
+		}
+
+		// expect
+		if err := sut.CreateCommentReaction(context.Background(), repo, 42, "confused"); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+}
+
+func TestClient_GetContent(t *testing.T) {
+	// given
+	_ = github.Initialize("github_api_token")
+	sut, err := github.GetInstance()
+	if err != nil {
+		t.Fatalf("error occurred. %+v", err)
+	}
+
+	// and
+	repo := &github.MockRepository{
+		FullName: "duck8823/duci",
+	}
+
+	t.Run("when file exists", func(t *testing.T) {
+		// given
+		gock.New("https://api.github.com").
+			Get("/repos/duck8823/duci/contents/.duci/config.yml").
+			MatchParam("ref", "abcdef").
+			Reply(200).
+			JSON(&go_github.RepositoryContent{
+				Type:     go_github.String("file"),
+				Encoding: go_github.String("base64"),
+				Content:  go_github.String("aGVsbG8gd29ybGQ="),
+			})
+		defer gock.Clean()
+
+		// when
+		got, err := sut.GetContent(context.Background(), repo, "abcdef", ".duci/config.yml")
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if string(got) != "hello world" {
+			t.Errorf("want: hello world, but got: %s", got)
+		}
+	})
+
+	t.Run("when file not found", func(t *testing.T) {
+		// given
+		gock.New("https://api.github.com").
+			Get("/repos/duck8823/duci/contents/.duci/config.yml").
+			Reply(404)
+		defer gock.Clean()
+
+		// when
+		_, err := sut.GetContent(context.Background(), repo, "abcdef", ".duci/config.yml")
+
+		// then
+		if err != github.ErrContentNotFound {
+			t.Errorf("error must be %+v, but got %+v", github.ErrContentNotFound, err)
+		}
+	})
+
+	t.Run("when github server returns error", func(t *testing.T) {
+		// given
+		gock.New("https://api.github.com").
+			Get("/repos/duck8823/duci/contents/.duci/config.yml").
+			Reply(500)
+		defer gock.Clean()
+
+		// expect
+		if _, err := sut.GetContent(context.Background(), repo, "abcdef", ".duci/config.yml"); err == nil || err == github.ErrContentNotFound {
+			t.Errorf("error must not be nil nor not found, but got %+v", err)
+		}
+	})
+}
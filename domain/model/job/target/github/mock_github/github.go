@@ -59,3 +59,66 @@ func (m *MockGitHub) CreateCommitStatus(ctx context.Context, status github.Commi
 func (mr *MockGitHubMockRecorder) CreateCommitStatus(ctx, status interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCommitStatus", reflect.TypeOf((*MockGitHub)(nil).CreateCommitStatus), ctx, status)
 }
+
+// GetPermissionLevel mocks base method
+func (m *MockGitHub) GetPermissionLevel(ctx context.Context, repo github.Repository, user string) (github.Permission, error) {
+	ret := m.ctrl.Call(m, "GetPermissionLevel", ctx, repo, user)
+	ret0, _ := ret[0].(github.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPermissionLevel indicates an expected call of GetPermissionLevel
+func (mr *MockGitHubMockRecorder) GetPermissionLevel(ctx, repo, user interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissionLevel", reflect.TypeOf((*MockGitHub)(nil).GetPermissionLevel), ctx, repo, user)
+}
+
+// IsTeamMember mocks base method
+func (m *MockGitHub) IsTeamMember(ctx context.Context, team github.TeamName, user string) (bool, error) {
+	ret := m.ctrl.Call(m, "IsTeamMember", ctx, team, user)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTeamMember indicates an expected call of IsTeamMember
+func (mr *MockGitHubMockRecorder) IsTeamMember(ctx, team, user interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTeamMember", reflect.TypeOf((*MockGitHub)(nil).IsTeamMember), ctx, team, user)
+}
+
+// CreateComment mocks base method
+func (m *MockGitHub) CreateComment(ctx context.Context, repo github.Repository, num int, body string) error {
+	ret := m.ctrl.Call(m, "CreateComment", ctx, repo, num, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateComment indicates an expected call of CreateComment
+func (mr *MockGitHubMockRecorder) CreateComment(ctx, repo, num, body interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockGitHub)(nil).CreateComment), ctx, repo, num, body)
+}
+
+// CreateCommentReaction mocks base method
+func (m *MockGitHub) CreateCommentReaction(ctx context.Context, repo github.Repository, id int64, content string) error {
+	ret := m.ctrl.Call(m, "CreateCommentReaction", ctx, repo, id, content)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCommentReaction indicates an expected call of CreateCommentReaction
+func (mr *MockGitHubMockRecorder) CreateCommentReaction(ctx, repo, id, content interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCommentReaction", reflect.TypeOf((*MockGitHub)(nil).CreateCommentReaction), ctx, repo, id, content)
+}
+
+// GetContent mocks base method
+func (m *MockGitHub) GetContent(ctx context.Context, repo github.Repository, ref, path string) ([]byte, error) {
+	ret := m.ctrl.Call(m, "GetContent", ctx, repo, ref, path)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContent indicates an expected call of GetContent
+func (mr *MockGitHubMockRecorder) GetContent(ctx, repo, ref, path interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContent", reflect.TypeOf((*MockGitHub)(nil).GetContent), ctx, repo, ref, path)
+}
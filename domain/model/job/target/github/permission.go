@@ -0,0 +1,47 @@
+package github
+
+// Permission represents a permission level of repository
+type Permission string
+
+const (
+	// NONE represents no permission.
+	NONE Permission = "none"
+	// READ represents read permission.
+	READ Permission = "read"
+	// TRIAGE represents triage permission.
+	TRIAGE Permission = "triage"
+	// WRITE represents write permission.
+	WRITE Permission = "write"
+	// MAINTAIN represents maintain permission.
+	MAINTAIN Permission = "maintain"
+	// ADMIN represents admin permission.
+	ADMIN Permission = "admin"
+)
+
+var permissionLevels = map[Permission]int{
+	NONE:     0,
+	READ:     1,
+	TRIAGE:   2,
+	WRITE:    3,
+	MAINTAIN: 4,
+	ADMIN:    5,
+}
+
+// String returns string value
+func (p Permission) String() string {
+	return string(p)
+}
+
+// IsValid returns whether known permission or not
+func (p Permission) IsValid() bool {
+	_, ok := permissionLevels[p]
+	return ok
+}
+
+// Satisfies returns whether the permission is higher than or equal to required
+func (p Permission) Satisfies(required Permission) bool {
+	if !p.IsValid() || !required.IsValid() {
+		return false
+	}
+	return permissionLevels[p] >= permissionLevels[required]
+}
@@ -0,0 +1,36 @@
+package github_test
+
+import (
+	"fmt"
+	"github.com/duck8823/duci/domain/model/job/target/github"
+	"testing"
+)
+
+func TestPermission_Satisfies(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		sut      github.Permission
+		required github.Permission
+		want     bool
+	}{
+		{sut: github.ADMIN, required: github.WRITE, want: true},
+		{sut: github.MAINTAIN, required: github.WRITE, want: true},
+		{sut: github.WRITE, required: github.WRITE, want: true},
+		{sut: github.TRIAGE, required: github.WRITE, want: false},
+		{sut: github.READ, required: github.WRITE, want: false},
+		{sut: github.NONE, required: github.READ, want: false},
+		{sut: github.WRITE, required: github.MAINTAIN, want: false},
+		{sut: github.Permission("unknown"), required: github.NONE, want: false},
+		{sut: github.ADMIN, required: github.Permission("unknown"), want: false},
+	} {
+		t.Run(fmt.Sprintf("when %s requires %s", tt.sut, tt.required), func(t *testing.T) {
+			// when
+			got := tt.sut.Satisfies(tt.required)
+
+			// then
+			if got != tt.want {
+				t.Errorf("must be %t, but got %t", tt.want, got)
+			}
+		})
+	}
+}
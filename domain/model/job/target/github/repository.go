@@ -41,3 +41,15 @@ func (r RepositoryName) Split() (owner string, repo string, err error) {
 	}
 	return ss[0], ss[1], nil
 }
+
+// TeamName is a github team name formatted `org/team-slug`.
+type TeamName string
+
+// Split team name to organization and team slug
+func (t TeamName) Split() (org string, slug string, err error) {
+	ss := strings.Split(string(t), "/")
+	if len(ss) != 2 || len(ss[0]) == 0 || len(ss[1]) == 0 {
+		return "", "", fmt.Errorf("Invalid team name: %s ", t)
+	}
+	return ss[0], ss[1], nil
+}
@@ -122,3 +122,66 @@ func TestRepositoryName_Repo(t *testing.T) {
 		}
 	})
 }
+
+func TestTeamName_Split(t *testing.T) {
+	t.Run("with correct name", func(t *testing.T) {
+		// given
+		sut := github.TeamName("duck8823/maintainers")
+
+		// when
+		org, slug, err := sut.Split()
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if org != "duck8823" {
+			t.Errorf("must be equal, but %+v", cmp.Diff(org, "duck8823"))
+		}
+
+		// and
+		if slug != "maintainers" {
+			t.Errorf("must be equal, but %+v", cmp.Diff(slug, "maintainers"))
+		}
+	})
+
+	t.Run("with invalid name", func(t *testing.T) {
+		// where
+		for _, tt := range []struct {
+			name string
+		}{
+			{
+				name: "",
+			},
+			{
+				name: "duck8823",
+			},
+			{
+				name: "duck8823/",
+			},
+			{
+				name: "duck8823/maintainers/domain",
+			},
+		} {
+			t.Run(fmt.Sprintf("when name is %s", tt.name), func(t *testing.T) {
+				// given
+				sut := github.TeamName(tt.name)
+
+				// when
+				org, slug, err := sut.Split()
+
+				// then
+				if err == nil {
+					t.Error("error must not be nil")
+				}
+
+				// and
+				if org != "" || slug != "" {
+					t.Errorf("must be empty, but got %+v and %+v", org, slug)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package github
+
+import (
+	"context"
+	"github.com/duck8823/duci/internal/container"
+	go_github "github.com/google/go-github/github"
+	"github.com/pkg/errors"
+)
+
+// Route returns a github client for the repository, or false to use default client.
+type Route func(fullName string) (GitHub, bool)
+
+type router struct {
+	defaults GitHub
+	route    Route
+}
+
+// InitializeWithRouter create a github client switching clients for each repository.
+func InitializeWithRouter(defaults GitHub, route Route) error {
+	github := new(GitHub)
+	*github = &router{defaults: defaults, route: route}
+	if err := container.Submit(github); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// GetPullRequest returns a pull request with the client routed by repository name.
+func (r *router) GetPullRequest(ctx context.Context, repo Repository, num int) (*go_github.PullRequest, error) {
+	return r.client(repo.GetFullName()).GetPullRequest(ctx, repo, num)
+}
+
+// CreateCommitStatus create commit status with the client routed by repository name.
+func (r *router) CreateCommitStatus(ctx context.Context, status CommitStatus) error {
+	return r.client(status.TargetSource.GetFullName()).CreateCommitStatus(ctx, status)
+}
+
+// GetPermissionLevel returns a permission level with the client routed by repository name.
+func (r *router) GetPermissionLevel(ctx context.Context, repo Repository, user string) (Permission, error) {
+	return r.client(repo.GetFullName()).GetPermissionLevel(ctx, repo, user)
+}
+
+// IsTeamMember returns whether the user is a member of the team with default client.
+func (r *router) IsTeamMember(ctx context.Context, team TeamName, user string) (bool, error) {
+	return r.defaults.IsTeamMember(ctx, team, user)
+}
+
+// CreateComment create a comment with the client routed by repository name.
+func (r *router) CreateComment(ctx context.Context, repo Repository, num int, body string) error {
+	return r.client(repo.GetFullName()).CreateComment(ctx, repo, num, body)
+}
+
+// CreateCommentReaction create a reaction with the client routed by repository name.
+func (r *router) CreateCommentReaction(ctx context.Context, repo Repository, id int64, content string) error {
+	return r.client(repo.GetFullName()).CreateCommentReaction(ctx, repo, id, content)
+}
+
+// GetContent returns a content of the file with the client routed by repository name.
+func (r *router) GetContent(ctx context.Context, repo Repository, ref string, path string) ([]byte, error) {
+	return r.client(repo.GetFullName()).GetContent(ctx, repo, ref, path)
+}
+
+func (r *router) client(fullName string) GitHub {
+	if github, ok := r.route(fullName); ok {
+		return github
+	}
+	return r.defaults
+}
@@ -0,0 +1,141 @@
+package github_test
+
+import (
+	"context"
+	"github.com/duck8823/duci/domain/model/job/target/github"
+	"github.com/duck8823/duci/domain/model/job/target/github/mock_github"
+	"github.com/duck8823/duci/internal/container"
+	"github.com/golang/mock/gomock"
+	"testing"
+)
+
+func TestInitializeWithRouter(t *testing.T) {
+	t.Run("when instance is nil", func(t *testing.T) {
+		// given
+		container.Clear()
+
+		// when
+		err := github.InitializeWithRouter(&github.StubClient{}, func(string) (github.GitHub, bool) {
+			return nil, false
+		})
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("when instance is not nil", func(t *testing.T) {
+		// given
+		container.Override(&github.StubClient{})
+		defer container.Clear()
+
+		// when
+		err := github.InitializeWithRouter(&github.StubClient{}, func(string) (github.GitHub, bool) {
+			return nil, false
+		})
+
+		// then
+		if err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+}
+
+func TestRouter(t *testing.T) {
+	// given
+	routedRepo := &github.MockRepository{FullName: "duck8823/duci"}
+	defaultRepo := &github.MockRepository{FullName: "duck8823/other"}
+
+	// and
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	defaults := mock_github.NewMockGitHub(ctrl)
+	routed := mock_github.NewMockGitHub(ctrl)
+
+	// and
+	container.Clear()
+	defer container.Clear()
+	if err := github.InitializeWithRouter(defaults, func(fullName string) (github.GitHub, bool) {
+		return routed, fullName == routedRepo.FullName
+	}); err != nil {
+		t.Fatalf("error occur: %+v", err)
+	}
+	sut, err := github.GetInstance()
+	if err != nil {
+		t.Fatalf("error occur: %+v", err)
+	}
+
+	t.Run("GetPullRequest", func(t *testing.T) {
+		// given
+		routed.EXPECT().GetPullRequest(gomock.Any(), gomock.Eq(routedRepo), gomock.Eq(1)).Times(1)
+		defaults.EXPECT().GetPullRequest(gomock.Any(), gomock.Eq(defaultRepo), gomock.Eq(1)).Times(1)
+
+		// expect
+		_, _ = sut.GetPullRequest(context.Background(), routedRepo, 1)
+		_, _ = sut.GetPullRequest(context.Background(), defaultRepo, 1)
+	})
+
+	t.Run("CreateCommitStatus", func(t *testing.T) {
+		// given
+		routedStatus := github.CommitStatus{TargetSource: &github.TargetSource{Repository: routedRepo}}
+		defaultStatus := github.CommitStatus{TargetSource: &github.TargetSource{Repository: defaultRepo}}
+
+		// and
+		routed.EXPECT().CreateCommitStatus(gomock.Any(), gomock.Eq(routedStatus)).Times(1)
+		defaults.EXPECT().CreateCommitStatus(gomock.Any(), gomock.Eq(defaultStatus)).Times(1)
+
+		// expect
+		_ = sut.CreateCommitStatus(context.Background(), routedStatus)
+		_ = sut.CreateCommitStatus(context.Background(), defaultStatus)
+	})
+
+	t.Run("GetPermissionLevel", func(t *testing.T) {
+		// given
+		routed.EXPECT().GetPermissionLevel(gomock.Any(), gomock.Eq(routedRepo), gomock.Eq("octocat")).Times(1)
+		defaults.EXPECT().GetPermissionLevel(gomock.Any(), gomock.Eq(defaultRepo), gomock.Eq("octocat")).Times(1)
+
+		// expect
+		_, _ = sut.GetPermissionLevel(context.Background(), routedRepo, "octocat")
+		_, _ = sut.GetPermissionLevel(context.Background(), defaultRepo, "octocat")
+	})
+
+	t.Run("IsTeamMember", func(t *testing.T) {
+		// given
+		defaults.EXPECT().IsTeamMember(gomock.Any(), gomock.Eq(github.TeamName("duck8823/maintainers")), gomock.Eq("octocat")).Times(1)
+
+		// expect
+		_, _ = sut.IsTeamMember(context.Background(), "duck8823/maintainers", "octocat")
+	})
+
+	t.Run("CreateComment", func(t *testing.T) {
+		// given
+		routed.EXPECT().CreateComment(gomock.Any(), gomock.Eq(routedRepo), gomock.Eq(1), gomock.Eq("hello")).Times(1)
+		defaults.EXPECT().CreateComment(gomock.Any(), gomock.Eq(defaultRepo), gomock.Eq(1), gomock.Eq("hello")).Times(1)
+
+		// expect
+		_ = sut.CreateComment(context.Background(), routedRepo, 1, "hello")
+		_ = sut.CreateComment(context.Background(), defaultRepo, 1, "hello")
+	})
+
+	t.Run("CreateCommentReaction", func(t *testing.T) {
+		// given
+		routed.EXPECT().CreateCommentReaction(gomock.Any(), gomock.Eq(routedRepo), gomock.Eq(int64(1)), gomock.Eq("eyes")).Times(1)
+		defaults.EXPECT().CreateCommentReaction(gomock.Any(), gomock.Eq(defaultRepo), gomock.Eq(int64(1)), gomock.Eq("eyes")).Times(1)
+
+		// expect
+		_ = sut.CreateCommentReaction(context.Background(), routedRepo, 1, "eyes")
+		_ = sut.CreateCommentReaction(context.Background(), defaultRepo, 1, "eyes")
+	})
+
+	t.Run("GetContent", func(t *testing.T) {
+		// given
+		routed.EXPECT().GetContent(gomock.Any(), gomock.Eq(routedRepo), gomock.Eq("master"), gomock.Eq(".duci/config.yml")).Times(1)
+		defaults.EXPECT().GetContent(gomock.Any(), gomock.Eq(defaultRepo), gomock.Eq("master"), gomock.Eq(".duci/config.yml")).Times(1)
+
+		// expect
+		_, _ = sut.GetContent(context.Background(), routedRepo, "master", ".duci/config.yml")
+		_, _ = sut.GetContent(context.Background(), defaultRepo, "master", ".duci/config.yml")
+	})
+}
@@ -6,8 +6,10 @@ import (
 
 // Builder represents a builder of docker runner
 type Builder struct {
-	docker  docker.Docker
-	logFunc LogFunc
+	docker    docker.Docker
+	logFunc   LogFunc
+	resources docker.Resources
+	limits    docker.Limits
 }
 
 // DefaultDockerRunnerBuilder create new builder of docker runner
@@ -22,10 +24,24 @@ func (b *Builder) LogFunc(f LogFunc) *Builder {
 	return b
 }
 
+// Resources set default resources of containers
+func (b *Builder) Resources(resources docker.Resources) *Builder {
+	b.resources = resources
+	return b
+}
+
+// Limits set maximums of resources requested by repositories
+func (b *Builder) Limits(limits docker.Limits) *Builder {
+	b.limits = limits
+	return b
+}
+
 // Build returns a docker runner
 func (b *Builder) Build() DockerRunner {
 	return &dockerRunnerImpl{
-		docker:  b.docker,
-		logFunc: b.logFunc,
+		docker:    b.docker,
+		logFunc:   b.logFunc,
+		resources: b.resources,
+		limits:    b.limits,
 	}
 }
@@ -1,6 +1,40 @@
 package runner
 
-import "github.com/pkg/errors"
+import (
+	"fmt"
+	"github.com/duck8823/duci/domain/model/docker"
+	"github.com/pkg/errors"
+)
 
 // ErrFailure is a error describes task failure.
 var ErrFailure = errors.New("Task Failure")
+
+// FailureError is a error describes task failure with exit code.
+type FailureError struct {
+	Code docker.ExitCode
+}
+
+// Error returns error message
+func (e *FailureError) Error() string {
+	return fmt.Sprintf("%s: exit code %d", ErrFailure, e.Code)
+}
+
+// Cause returns ErrFailure
+func (e *FailureError) Cause() error {
+	return ErrFailure
+}
+
+// BuildFailureError is a error describes failure of building image.
+type BuildFailureError struct {
+	Message string
+}
+
+// Error returns error message
+func (e *BuildFailureError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrFailure, e.Message)
+}
+
+// Cause returns ErrFailure
+func (e *BuildFailureError) Cause() error {
+	return ErrFailure
+}
@@ -1,6 +1,9 @@
 package runner
 
-import "github.com/duck8823/duci/domain/model/docker"
+import (
+	"github.com/duck8823/duci/domain/model/docker"
+	"time"
+)
 
 func (b *Builder) SetDocker(docker docker.Docker) (reset func()) {
 	tmp := b.docker
@@ -44,8 +47,32 @@ func (r *DockerRunnerImpl) SetLogFunc(logFunc LogFunc) (reset func()) {
 	}
 }
 
+func (r *DockerRunnerImpl) SetResources(resources docker.Resources) (reset func()) {
+	tmp := r.resources
+	r.resources = resources
+	return func() {
+		r.resources = tmp
+	}
+}
+
+func (r *DockerRunnerImpl) SetLimits(limits docker.Limits) (reset func()) {
+	tmp := r.limits
+	r.limits = limits
+	return func() {
+		r.limits = tmp
+	}
+}
+
 var CreateTarball = createTarball
 
 var DockerfilePath = dockerfilePath
 
 var ExportedRuntimeOptions = runtimeOptions
+
+func SetHealthInterval(interval time.Duration) (reset func()) {
+	tmp := healthInterval
+	healthInterval = interval
+	return func() {
+		healthInterval = tmp
+	}
+}
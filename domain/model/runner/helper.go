@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"github.com/duck8823/duci/domain/model/docker"
 	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/domain/model/task"
 	"github.com/duck8823/duci/infrastructure/archive/tar"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
@@ -64,3 +65,20 @@ func runtimeOptions(workDir job.WorkDir) (docker.RuntimeOptions, error) {
 	}
 	return opts, nil
 }
+
+// taskOf returns the task declared in config.yml with the name
+func taskOf(workDir job.WorkDir, name string) (*task.Task, error) {
+	content, err := ioutil.ReadFile(filepath.Join(workDir.String(), ".duci/config.yml"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	tasks, err := task.Parse([]byte(os.ExpandEnv(string(content))))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	t, ok := tasks[name]
+	if !ok || t == nil {
+		return nil, errors.Errorf("task %s is not declared in .duci/config.yml", name)
+	}
+	return t, nil
+}
@@ -134,7 +134,7 @@ func TestDockerfilePath(t *testing.T) {
 			got := runner.DockerfilePath(in)
 
 			// then
-			if got != want {
+			if !cmp.Equal(got, want) {
 				t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
 			}
 
@@ -4,7 +4,10 @@ import (
 	"context"
 	"github.com/duck8823/duci/domain/model/docker"
 	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/domain/model/task"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"io"
 	"os"
 )
 
@@ -15,40 +18,104 @@ type DockerRunner interface {
 
 // dockerRunnerImpl is a implement of DockerRunner
 type dockerRunnerImpl struct {
-	docker  docker.Docker
-	logFunc LogFunc
+	docker    docker.Docker
+	logFunc   LogFunc
+	resources docker.Resources
+	limits    docker.Limits
 }
 
 // Run task in docker container
 func (r *dockerRunnerImpl) Run(ctx context.Context, dir job.WorkDir, tag docker.Tag, cmd docker.Command) error {
-	if err := r.dockerBuild(ctx, dir, tag); err != nil {
+	opts, err := runtimeOptions(dir)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	opts.Resources, err = r.resources.Override(opts.Resources).Cap(r.limits)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	dockerfile := dockerfilePath(dir)
+	var matrix task.Values
+	if name, ok := task.NameFromContext(ctx); ok {
+		t, err := taskOf(dir, name)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		opts.Environments = opts.Environments.Merge(t.Environments)
+		if len(t.Dockerfile) > 0 {
+			dockerfile.Path = t.Dockerfile
+		}
+		if len(cmd) == 0 {
+			cmd = t.Command
+		}
+		if combination, ok := task.CombinationFromContext(ctx); ok {
+			matrix = t.Matrix.ValuesOf(combination)
+		}
+		if len(matrix.Dockerfile) > 0 {
+			dockerfile.Path = matrix.Dockerfile
+		}
+	}
+	dockerfile.Args = matrix.Args
+	opts.Environments = opts.Environments.Merge(matrix.Environments)
+
+	if err := r.dockerBuild(ctx, dir, dockerfile, tag); err != nil {
+		r.cleanupIfDone(ctx, "", tag)
 		return errors.WithStack(err)
 	}
 
-	conID, err := r.dockerRun(ctx, dir, tag, cmd)
+	var conID docker.ContainerID
+	teardown, err := r.startServices(ctx, tag, &opts)
+	// the task container left by failures is removed before the network
+	defer func() { teardown(conID) }()
 	if err != nil {
+		r.cleanupIfDone(ctx, "", tag)
+		return errors.WithStack(err)
+	}
+
+	conID, err = r.dockerRun(ctx, opts, tag, cmd)
+	if err != nil {
+		r.cleanupIfDone(ctx, conID, tag)
 		return errors.WithStack(err)
 	}
 
 	code, err := r.docker.ExitCode(ctx, conID)
 	if err != nil {
+		r.cleanupIfDone(ctx, conID, tag)
 		return errors.WithStack(err)
 	}
 	if err := r.docker.RemoveContainer(ctx, conID); err != nil {
 		return errors.WithStack(err)
 	}
+	conID = ""
 	if err := r.docker.RemoveImage(ctx, tag); err != nil {
 		return errors.WithStack(err)
 	}
 	if code.IsFailure() {
-		return ErrFailure
+		return &FailureError{Code: code}
 	}
 
 	return nil
 }
 
+// cleanupIfDone removes the container and the image if the context is cancelled or timed out.
+// It uses a new context because the given one can no longer be used for requests.
+func (r *dockerRunnerImpl) cleanupIfDone(ctx context.Context, conID docker.ContainerID, tag docker.Tag) {
+	if ctx.Err() == nil {
+		return
+	}
+
+	if len(conID) > 0 {
+		if err := r.docker.RemoveContainer(context.Background(), conID); err != nil {
+			logrus.Warnf("Failed to remove container %s: %+v", conID, err)
+		}
+	}
+	if err := r.docker.RemoveImage(context.Background(), tag); err != nil {
+		logrus.Warnf("Failed to remove image %s: %+v", tag, err)
+	}
+}
+
 // dockerBuild build a docker image
-func (r *dockerRunnerImpl) dockerBuild(ctx context.Context, dir job.WorkDir, tag docker.Tag) error {
+func (r *dockerRunnerImpl) dockerBuild(ctx context.Context, dir job.WorkDir, dockerfile docker.Dockerfile, tag docker.Tag) error {
 	tarball, err := createTarball(dir)
 	if err != nil {
 		return errors.WithStack(err)
@@ -58,22 +125,26 @@ func (r *dockerRunnerImpl) dockerBuild(ctx context.Context, dir job.WorkDir, tag
 		os.Remove(tarball.Name())
 	}()
 
-	buildLog, err := r.docker.Build(ctx, tarball, docker.Tag(tag), dockerfilePath(dir))
+	buildLog, err := r.docker.Build(ctx, tarball, docker.Tag(tag), dockerfile)
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	r.logFunc(ctx, buildLog)
+	log := &recordedLog{Log: buildLog}
+	r.logFunc(ctx, log)
 
+	// For waiting build
+	if err := log.wait(); err != nil {
+		var failure *docker.BuildError
+		if errors.As(err, &failure) {
+			return &BuildFailureError{Message: failure.Message}
+		}
+		return errors.WithStack(err)
+	}
 	return nil
 }
 
 // dockerRun run docker container
-func (r *dockerRunnerImpl) dockerRun(ctx context.Context, dir job.WorkDir, tag docker.Tag, cmd docker.Command) (docker.ContainerID, error) {
-	opts, err := runtimeOptions(dir)
-	if err != nil {
-		return "", errors.WithStack(err)
-	}
-
+func (r *dockerRunnerImpl) dockerRun(ctx context.Context, opts docker.RuntimeOptions, tag docker.Tag, cmd docker.Command) (docker.ContainerID, error) {
 	conID, runLog, err := r.docker.Run(ctx, opts, tag, cmd)
 	if err != nil {
 		return conID, errors.WithStack(err)
@@ -81,3 +152,29 @@ func (r *dockerRunnerImpl) dockerRun(ctx context.Context, dir job.WorkDir, tag d
 	r.logFunc(ctx, runLog)
 	return conID, nil
 }
+
+// recordedLog is a job.Log that keeps the error ended reading.
+type recordedLog struct {
+	job.Log
+	err error
+}
+
+// ReadLine returns LogLine and records the error.
+func (l *recordedLog) ReadLine() (*job.LogLine, error) {
+	line, err := l.Log.ReadLine()
+	if err != nil {
+		l.err = err
+	}
+	return line, err
+}
+
+// wait reads the rest of the log and returns the error ended reading unless it is io.EOF.
+func (l *recordedLog) wait() error {
+	for l.err == nil {
+		l.ReadLine()
+	}
+	if l.err == io.EOF {
+		return nil
+	}
+	return l.err
+}
@@ -8,10 +8,12 @@ import (
 	"github.com/duck8823/duci/domain/model/job"
 	"github.com/duck8823/duci/domain/model/job/mock_job"
 	"github.com/duck8823/duci/domain/model/runner"
+	"github.com/duck8823/duci/domain/model/task"
 	"github.com/golang/mock/gomock"
 	"github.com/labstack/gommon/random"
 	"github.com/pkg/errors"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
@@ -125,14 +127,122 @@ func TestDockerRunnerImpl_Run(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
+		// and
+		mockDocker := mock_docker.NewMockDocker(ctrl)
+		mockDocker.EXPECT().
+			Build(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+		mockDocker.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := runner.DockerRunnerImpl{}
+		defer sut.SetDocker(mockDocker)()
+		defer sut.SetLogFunc(runner.NothingToDo)()
+
+		// expect
+		if err := sut.Run(context.Background(), dir, tag, cmd); err == nil {
+			t.Errorf("error must not be nil")
+		}
+	})
+
+	t.Run("with resources", func(t *testing.T) {
+		// given
+		dir, cleanup := tmpDir(t)
+		defer cleanup()
+
+		tag := docker.Tag(fmt.Sprintf("duci/test:%s", random.String(8)))
+		cmd := docker.Command{"echo", "test"}
+
+		// and
+		if err := os.MkdirAll(filepath.Join(dir.String(), ".duci"), 0700); err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir.String(), ".duci", "config.yml"), []byte("resources:\n  cpus: 8\n  network_mode: none\n"), 0400); err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+
+		// and
+		want := docker.RuntimeOptions{
+			Resources: docker.Resources{
+				CPUs:        2,
+				Memory:      512 * 1024 * 1024,
+				NetworkMode: "none",
+			},
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
 		// and
 		log := stubLog(t, ctrl)
+		conID := docker.ContainerID(random.String(16, random.Alphanumeric))
 
 		mockDocker := mock_docker.NewMockDocker(ctrl)
 		mockDocker.EXPECT().
 			Build(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Times(1).
 			Return(log, nil)
+		mockDocker.EXPECT().
+			Run(gomock.Any(), gomock.Eq(want), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(conID, log, nil)
+		mockDocker.EXPECT().
+			ExitCode(gomock.Any(), gomock.Eq(conID)).
+			Times(1).
+			Return(docker.ExitCode(0), nil)
+		mockDocker.EXPECT().
+			RemoveContainer(gomock.Any(), gomock.Eq(conID)).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			RemoveImage(gomock.Any(), gomock.Eq(tag)).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := runner.DockerRunnerImpl{}
+		defer sut.SetDocker(mockDocker)()
+		defer sut.SetLogFunc(runner.NothingToDo)()
+		defer sut.SetResources(docker.Resources{CPUs: 1, Memory: 512 * 1024 * 1024})()
+		defer sut.SetLimits(docker.Limits{CPUs: 2, NetworkModes: []string{"bridge", "none"}})()
+
+		// when
+		err := sut.Run(context.Background(), dir, tag, cmd)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("when network mode is not allowed", func(t *testing.T) {
+		// given
+		dir, cleanup := tmpDir(t)
+		defer cleanup()
+
+		tag := docker.Tag(fmt.Sprintf("duci/test:%s", random.String(8)))
+		cmd := docker.Command{"echo", "test"}
+
+		// and
+		if err := os.MkdirAll(filepath.Join(dir.String(), ".duci"), 0700); err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir.String(), ".duci", "config.yml"), []byte("resources:\n  network_mode: host\n"), 0400); err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		mockDocker := mock_docker.NewMockDocker(ctrl)
+		mockDocker.EXPECT().
+			Build(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
 		mockDocker.EXPECT().
 			Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Times(0)
@@ -141,6 +251,7 @@ func TestDockerRunnerImpl_Run(t *testing.T) {
 		sut := runner.DockerRunnerImpl{}
 		defer sut.SetDocker(mockDocker)()
 		defer sut.SetLogFunc(runner.NothingToDo)()
+		defer sut.SetLimits(docker.Limits{NetworkModes: []string{"none"}})()
 
 		// expect
 		if err := sut.Run(context.Background(), dir, tag, cmd); err == nil {
@@ -148,6 +259,193 @@ func TestDockerRunnerImpl_Run(t *testing.T) {
 		}
 	})
 
+	t.Run("with task", func(t *testing.T) {
+		// given
+		dir, cleanup := tmpDir(t)
+		defer cleanup()
+
+		tag := docker.Tag(fmt.Sprintf("duci/test:%s", random.String(8)))
+
+		// and
+		if err := os.MkdirAll(filepath.Join(dir.String(), ".duci"), 0700); err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		config := `
+environments:
+  FOO: foo
+tasks:
+  test:
+    command: [make, test]
+    dockerfile: .duci/Dockerfile.test
+    environments:
+      BAR: bar
+`
+		if err := ioutil.WriteFile(filepath.Join(dir.String(), ".duci", "config.yml"), []byte(config), 0400); err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+
+		// and
+		ctx := task.ContextWithName(context.Background(), "test")
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		log := stubLog(t, ctrl)
+		conID := docker.ContainerID(random.String(16, random.Alphanumeric))
+
+		mockDocker := mock_docker.NewMockDocker(ctrl)
+		mockDocker.EXPECT().
+			Build(gomock.Any(), gomock.Any(), gomock.Eq(tag), gomock.Eq(docker.Dockerfile{Dir: dir.String(), Path: ".duci/Dockerfile.test"})).
+			Times(1).
+			Return(log, nil)
+		mockDocker.EXPECT().
+			Run(gomock.Any(), gomock.Eq(docker.RuntimeOptions{
+				Environments: docker.Environments{"FOO": "foo", "BAR": "bar"},
+				Resources:    docker.Resources{NetworkMode: "bridge"},
+			}), gomock.Eq(tag), gomock.Eq(docker.Command{"make", "test"})).
+			Times(1).
+			Return(conID, log, nil)
+		mockDocker.EXPECT().
+			ExitCode(gomock.Any(), gomock.Eq(conID)).
+			Times(1).
+			Return(docker.ExitCode(0), nil)
+		mockDocker.EXPECT().
+			RemoveContainer(gomock.Any(), gomock.Eq(conID)).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			RemoveImage(gomock.Any(), gomock.Eq(tag)).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := runner.DockerRunnerImpl{}
+		defer sut.SetDocker(mockDocker)()
+		defer sut.SetLogFunc(runner.NothingToDo)()
+
+		// when
+		err := sut.Run(ctx, dir, tag, docker.Command{})
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("with combination of matrix", func(t *testing.T) {
+		// given
+		dir, cleanup := tmpDir(t)
+		defer cleanup()
+
+		tag := docker.Tag(fmt.Sprintf("duci/test:%s", random.String(8)))
+
+		// and
+		if err := os.MkdirAll(filepath.Join(dir.String(), ".duci"), 0700); err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		config := `
+tasks:
+  test:
+    command: [make, test]
+    matrix:
+      args:
+        GO_VERSION: ["1.12", "1.13"]
+      environments:
+        DB: [postgres, mysql]
+      dockerfiles: [.duci/Dockerfile.alpine]
+`
+		if err := ioutil.WriteFile(filepath.Join(dir.String(), ".duci", "config.yml"), []byte(config), 0400); err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+
+		// and
+		ctx := task.ContextWithName(context.Background(), "test")
+		ctx = task.ContextWithCombination(ctx, task.Combination{"GO_VERSION": "1.13", "DB": "mysql", "dockerfile": ".duci/Dockerfile.alpine"})
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		log := stubLog(t, ctrl)
+		conID := docker.ContainerID(random.String(16, random.Alphanumeric))
+
+		mockDocker := mock_docker.NewMockDocker(ctrl)
+		mockDocker.EXPECT().
+			Build(gomock.Any(), gomock.Any(), gomock.Eq(tag), gomock.Eq(docker.Dockerfile{
+				Dir:  dir.String(),
+				Path: ".duci/Dockerfile.alpine",
+				Args: map[string]string{"GO_VERSION": "1.13"},
+			})).
+			Times(1).
+			Return(log, nil)
+		mockDocker.EXPECT().
+			Run(gomock.Any(), gomock.Eq(docker.RuntimeOptions{
+				Environments: docker.Environments{"DB": "mysql"},
+				Resources:    docker.Resources{NetworkMode: "bridge"},
+			}), gomock.Eq(tag), gomock.Eq(docker.Command{"make", "test"})).
+			Times(1).
+			Return(conID, log, nil)
+		mockDocker.EXPECT().
+			ExitCode(gomock.Any(), gomock.Eq(conID)).
+			Times(1).
+			Return(docker.ExitCode(0), nil)
+		mockDocker.EXPECT().
+			RemoveContainer(gomock.Any(), gomock.Eq(conID)).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			RemoveImage(gomock.Any(), gomock.Eq(tag)).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := runner.DockerRunnerImpl{}
+		defer sut.SetDocker(mockDocker)()
+		defer sut.SetLogFunc(runner.NothingToDo)()
+
+		// when
+		err := sut.Run(ctx, dir, tag, docker.Command{})
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("when task is not declared", func(t *testing.T) {
+		// given
+		dir, cleanup := tmpDir(t)
+		defer cleanup()
+
+		tag := docker.Tag(fmt.Sprintf("duci/test:%s", random.String(8)))
+
+		// and
+		ctx := task.ContextWithName(context.Background(), "unknown")
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		mockDocker := mock_docker.NewMockDocker(ctrl)
+		mockDocker.EXPECT().
+			Build(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := runner.DockerRunnerImpl{}
+		defer sut.SetDocker(mockDocker)()
+		defer sut.SetLogFunc(runner.NothingToDo)()
+
+		// expect
+		if err := sut.Run(ctx, dir, tag, docker.Command{}); err == nil {
+			t.Errorf("error must not be nil")
+		}
+	})
+
 	t.Run("when failure docker build", func(t *testing.T) {
 		// given
 		dir, cleanup := tmpDir(t)
@@ -184,6 +482,54 @@ func TestDockerRunnerImpl_Run(t *testing.T) {
 		}
 	})
 
+	t.Run("when docker build reports a error", func(t *testing.T) {
+		// given
+		dir, cleanup := tmpDir(t)
+		defer cleanup()
+
+		tag := docker.Tag(fmt.Sprintf("duci/test:%s", random.String(8)))
+		cmd := docker.Command{"echo", "test"}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		log := mock_job.NewMockLog(ctrl)
+		log.EXPECT().
+			ReadLine().
+			Times(1).
+			Return(nil, &docker.BuildError{Message: "returned a non-zero code: 1"})
+
+		mockDocker := mock_docker.NewMockDocker(ctrl)
+		mockDocker.EXPECT().
+			Build(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(log, nil)
+		mockDocker.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := runner.DockerRunnerImpl{}
+		defer sut.SetDocker(mockDocker)()
+		defer sut.SetLogFunc(runner.NothingToDo)()
+
+		// when
+		err := sut.Run(context.Background(), dir, tag, cmd)
+
+		// then
+		if errors.Cause(err) != runner.ErrFailure {
+			t.Errorf("error must be %+v, but got %+v", runner.ErrFailure, err)
+		}
+
+		// and
+		want := "Task Failure: returned a non-zero code: 1"
+		if err.Error() != want {
+			t.Errorf("error message must be %s, but got %s", want, err.Error())
+		}
+	})
+
 	t.Run("when failure docker run", func(t *testing.T) {
 		// given
 		dir, cleanup := tmpDir(t)
@@ -275,6 +621,64 @@ func TestDockerRunnerImpl_Run(t *testing.T) {
 		}
 	})
 
+	t.Run("when context is cancelled while waiting exit code", func(t *testing.T) {
+		// given
+		dir, cleanup := tmpDir(t)
+		defer cleanup()
+
+		tag := docker.Tag(fmt.Sprintf("duci/test:%s", random.String(8)))
+		cmd := docker.Command{"echo", "test"}
+
+		// and
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		log := stubLog(t, ctrl)
+		conID := docker.ContainerID(random.String(16, random.Alphanumeric))
+
+		mockDocker := mock_docker.NewMockDocker(ctrl)
+		mockDocker.EXPECT().
+			Build(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(log, nil)
+		mockDocker.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(conID, log, nil)
+		mockDocker.EXPECT().
+			ExitCode(gomock.Any(), gomock.Eq(conID)).
+			Times(1).
+			DoAndReturn(func(_ context.Context, _ docker.ContainerID) (docker.ExitCode, error) {
+				cancel()
+				return -1, context.Canceled
+			})
+		mockDocker.EXPECT().
+			RemoveContainer(gomock.Eq(context.Background()), gomock.Eq(conID)).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			RemoveImage(gomock.Eq(context.Background()), gomock.Eq(tag)).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := runner.DockerRunnerImpl{}
+		defer sut.SetDocker(mockDocker)()
+		defer sut.SetLogFunc(runner.NothingToDo)()
+
+		// when
+		err := sut.Run(ctx, dir, tag, cmd)
+
+		// then
+		if errors.Cause(err) != context.Canceled {
+			t.Errorf("error must be %+v, but got %+v", context.Canceled, err)
+		}
+	})
+
 	t.Run("when exit code is not zero", func(t *testing.T) {
 		// given
 		dir, cleanup := tmpDir(t)
@@ -322,9 +726,14 @@ func TestDockerRunnerImpl_Run(t *testing.T) {
 		err := sut.Run(context.Background(), dir, tag, cmd)
 
 		// then
-		if err != runner.ErrFailure {
+		if errors.Cause(err) != runner.ErrFailure {
 			t.Errorf("error must be ErrFailure, but got %+v", err)
 		}
+
+		// and
+		if fe, ok := err.(*runner.FailureError); !ok || fe.Code != docker.ExitCode(-1) {
+			t.Errorf("error must be FailureError with exit code -1, but got %+v", err)
+		}
 	})
 
 	t.Run("when failure docker remove container", func(t *testing.T) {
@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"github.com/duck8823/duci/domain/model/docker"
+	"github.com/labstack/gommon/random"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// healthInterval is a interval of checking health of services.
+var healthInterval = time.Second
+
+// startServices starts services on a new network labelled with the tag and waits for them to be ready, then sets the network to the task container.
+// The returned function removes the task container, services and the network, and must be called even if it returns error.
+func (r *dockerRunnerImpl) startServices(ctx context.Context, tag docker.Tag, opts *docker.RuntimeOptions) (teardown func(task docker.ContainerID), err error) {
+	if len(opts.Services) == 0 {
+		return func(docker.ContainerID) {}, nil
+	}
+	switch opts.Resources.NetworkMode {
+	case "", "bridge", "default":
+	default:
+		return func(docker.ContainerID) {}, errors.Errorf("services can not be used with network mode %s", opts.Resources.NetworkMode)
+	}
+
+	network := docker.Network(fmt.Sprintf("duci-%s", random.String(16, random.Lowercase)))
+	if err := r.docker.CreateNetwork(ctx, network, tag); err != nil {
+		return func(docker.ContainerID) {}, errors.WithStack(err)
+	}
+
+	services := make(map[string]docker.ContainerID)
+	teardown = func(task docker.ContainerID) {
+		// uses a new context because the given one may be cancelled.
+		// containers must be removed before the network.
+		for _, conID := range append([]docker.ContainerID{task}, values(services)...) {
+			if len(conID) == 0 {
+				continue
+			}
+			if err := r.docker.RemoveContainer(context.Background(), conID); err != nil {
+				logrus.Warnf("Failed to remove container %s: %+v", conID, err)
+			}
+		}
+		if err := r.docker.RemoveNetwork(context.Background(), network); err != nil {
+			logrus.Warnf("Failed to remove network %s: %+v", network, err)
+		}
+	}
+
+	for _, name := range opts.Services.Names() {
+		conID, err := r.docker.StartService(ctx, network, name, opts.Services[name], opts.Resources, tag)
+		if len(conID) > 0 {
+			services[name] = conID
+		}
+		if err != nil {
+			return teardown, errors.Wrapf(err, "failed to start service %s", name)
+		}
+	}
+	for _, name := range opts.Services.Names() {
+		if err := r.waitForReady(ctx, name, services[name]); err != nil {
+			return teardown, errors.WithStack(err)
+		}
+	}
+
+	opts.Resources.NetworkMode = network.String()
+	return teardown, nil
+}
+
+// waitForReady waits until the healthcheck of service succeeds. It returns error if the service becomes unhealthy or stops.
+func (r *dockerRunnerImpl) waitForReady(ctx context.Context, name string, conID docker.ContainerID) error {
+	for {
+		health, err := r.docker.Health(ctx, conID)
+		if err != nil {
+			return errors.Wrapf(err, "service %s is not available", name)
+		}
+		if health.IsReady() {
+			return nil
+		}
+		if health == docker.Unhealthy {
+			return errors.Errorf("service %s is unhealthy", name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		case <-time.After(healthInterval):
+		}
+	}
+}
+
+func values(m map[string]docker.ContainerID) []docker.ContainerID {
+	var ids []docker.ContainerID
+	for _, id := range m {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,423 @@
+package runner_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/duck8823/duci/domain/model/docker"
+	"github.com/duck8823/duci/domain/model/docker/mock_docker"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/domain/model/runner"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/gommon/random"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDockerRunnerImpl_Run_WithServices(t *testing.T) {
+	t.Run("with healthy services", func(t *testing.T) {
+		// given
+		dir, cleanup := servicesDir(t, "services:\n  db:\n    image: postgres\n  cache:\n    image: redis\n")
+		defer cleanup()
+
+		tag := docker.Tag(fmt.Sprintf("duci/test:%s", random.String(8)))
+		cmd := docker.Command{"echo", "test"}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		log := stubLog(t, ctrl)
+		conID := docker.ContainerID("task")
+
+		var network docker.Network
+		mockDocker := mock_docker.NewMockDocker(ctrl)
+		mockDocker.EXPECT().
+			Build(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(log, nil)
+		mockDocker.EXPECT().
+			CreateNetwork(gomock.Any(), gomock.Any(), gomock.Eq(tag)).
+			Times(1).
+			DoAndReturn(func(_ context.Context, n docker.Network, _ docker.Tag) error {
+				network = n
+				return nil
+			})
+		mockDocker.EXPECT().
+			StartService(gomock.Any(), gomock.Any(), gomock.Eq("cache"), gomock.Eq(docker.Service{Image: "redis"}), gomock.Any(), gomock.Eq(tag)).
+			Times(1).
+			Return(docker.ContainerID("cache"), nil)
+		mockDocker.EXPECT().
+			StartService(gomock.Any(), gomock.Any(), gomock.Eq("db"), gomock.Eq(docker.Service{Image: "postgres"}), gomock.Any(), gomock.Eq(tag)).
+			Times(1).
+			Return(docker.ContainerID("db"), nil)
+		gomock.InOrder(
+			mockDocker.EXPECT().
+				Health(gomock.Any(), gomock.Eq(docker.ContainerID("cache"))).
+				Times(1).
+				Return(docker.Starting, nil),
+			mockDocker.EXPECT().
+				Health(gomock.Any(), gomock.Eq(docker.ContainerID("cache"))).
+				Times(1).
+				Return(docker.Healthy, nil),
+		)
+		mockDocker.EXPECT().
+			Health(gomock.Any(), gomock.Eq(docker.ContainerID("db"))).
+			Times(1).
+			Return(docker.NoHealthcheck, nil)
+		mockDocker.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Eq(tag), gomock.Eq(cmd)).
+			Times(1).
+			Do(func(_ context.Context, opts docker.RuntimeOptions, _ docker.Tag, _ docker.Command) {
+				if opts.Resources.NetworkMode != network.String() {
+					t.Errorf("network mode want: %s, but got: %s", network, opts.Resources.NetworkMode)
+				}
+			}).
+			Return(conID, log, nil)
+		mockDocker.EXPECT().
+			ExitCode(gomock.Any(), gomock.Eq(conID)).
+			Times(1).
+			Return(docker.ExitCode(0), nil)
+		mockDocker.EXPECT().
+			RemoveContainer(gomock.Any(), gomock.Eq(conID)).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			RemoveContainer(gomock.Any(), gomock.Eq(docker.ContainerID("cache"))).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			RemoveContainer(gomock.Any(), gomock.Eq(docker.ContainerID("db"))).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			RemoveNetwork(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(_ context.Context, n docker.Network) error {
+				if n != network {
+					t.Errorf("want: %s, but got: %s", network, n)
+				}
+				return nil
+			})
+		mockDocker.EXPECT().
+			RemoveImage(gomock.Any(), gomock.Eq(tag)).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := runner.DockerRunnerImpl{}
+		defer sut.SetDocker(mockDocker)()
+		defer sut.SetLogFunc(runner.NothingToDo)()
+		defer runner.SetHealthInterval(time.Millisecond)()
+
+		// when
+		err := sut.Run(context.Background(), dir, tag, cmd)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("with limits of resources", func(t *testing.T) {
+		// given
+		dir, cleanup := servicesDir(t, "resources:\n  cpus: 4\n  pids_limit: 512\nservices:\n  db:\n    image: postgres\n")
+		defer cleanup()
+
+		tag := docker.Tag(fmt.Sprintf("duci/test:%s", random.String(8)))
+		cmd := docker.Command{"echo", "test"}
+
+		// and
+		readOnly := true
+		want := docker.Resources{
+			CPUs:        2,
+			Memory:      1024,
+			PidsLimit:   256,
+			NetworkMode: "bridge",
+			ReadOnly:    &readOnly,
+			CapDrop:     []string{"NET_RAW"},
+			User:        "1000:1000",
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		log := stubLog(t, ctrl)
+		conID := docker.ContainerID("task")
+
+		mockDocker := mock_docker.NewMockDocker(ctrl)
+		mockDocker.EXPECT().
+			Build(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(log, nil)
+		mockDocker.EXPECT().
+			CreateNetwork(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			StartService(gomock.Any(), gomock.Any(), gomock.Eq("db"), gomock.Eq(docker.Service{Image: "postgres"}), gomock.Eq(want), gomock.Any()).
+			Times(1).
+			Return(docker.ContainerID("db"), nil)
+		mockDocker.EXPECT().
+			Health(gomock.Any(), gomock.Eq(docker.ContainerID("db"))).
+			Times(1).
+			Return(docker.Healthy, nil)
+		mockDocker.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Eq(tag), gomock.Eq(cmd)).
+			Times(1).
+			Return(conID, log, nil)
+		mockDocker.EXPECT().
+			ExitCode(gomock.Any(), gomock.Eq(conID)).
+			Times(1).
+			Return(docker.ExitCode(0), nil)
+		mockDocker.EXPECT().
+			RemoveContainer(gomock.Any(), gomock.Any()).
+			Times(2).
+			Return(nil)
+		mockDocker.EXPECT().
+			RemoveNetwork(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			RemoveImage(gomock.Any(), gomock.Eq(tag)).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := runner.DockerRunnerImpl{}
+		defer sut.SetDocker(mockDocker)()
+		defer sut.SetLogFunc(runner.NothingToDo)()
+		defer sut.SetResources(docker.Resources{Memory: 1024, ReadOnly: &readOnly, CapDrop: []string{"NET_RAW"}})()
+		defer sut.SetLimits(docker.Limits{CPUs: 2, PidsLimit: 256, User: "1000:1000"})()
+		defer runner.SetHealthInterval(time.Millisecond)()
+
+		// when
+		err := sut.Run(context.Background(), dir, tag, cmd)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+	})
+
+	t.Run("when service is unhealthy", func(t *testing.T) {
+		// given
+		dir, cleanup := servicesDir(t, "services:\n  db:\n    image: postgres\n")
+		defer cleanup()
+
+		tag := docker.Tag(fmt.Sprintf("duci/test:%s", random.String(8)))
+		cmd := docker.Command{"echo", "test"}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		mockDocker := mock_docker.NewMockDocker(ctrl)
+		mockDocker.EXPECT().
+			Build(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(stubLog(t, ctrl), nil)
+		mockDocker.EXPECT().
+			CreateNetwork(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			StartService(gomock.Any(), gomock.Any(), gomock.Eq("db"), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(docker.ContainerID("db"), nil)
+		mockDocker.EXPECT().
+			Health(gomock.Any(), gomock.Eq(docker.ContainerID("db"))).
+			Times(1).
+			Return(docker.Unhealthy, nil)
+		mockDocker.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+		mockDocker.EXPECT().
+			RemoveContainer(gomock.Any(), gomock.Eq(docker.ContainerID("db"))).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			RemoveNetwork(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := runner.DockerRunnerImpl{}
+		defer sut.SetDocker(mockDocker)()
+		defer sut.SetLogFunc(runner.NothingToDo)()
+
+		// when
+		err := sut.Run(context.Background(), dir, tag, cmd)
+
+		// then
+		if err == nil || !strings.Contains(err.Error(), "service db is unhealthy") {
+			t.Errorf("error must be unhealthy, but got %+v", err)
+		}
+	})
+
+	t.Run("when failure start service", func(t *testing.T) {
+		// given
+		dir, cleanup := servicesDir(t, "services:\n  db:\n    image: postgres\n")
+		defer cleanup()
+
+		tag := docker.Tag(fmt.Sprintf("duci/test:%s", random.String(8)))
+		cmd := docker.Command{"echo", "test"}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		mockDocker := mock_docker.NewMockDocker(ctrl)
+		mockDocker.EXPECT().
+			Build(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(stubLog(t, ctrl), nil)
+		mockDocker.EXPECT().
+			CreateNetwork(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			StartService(gomock.Any(), gomock.Any(), gomock.Eq("db"), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(docker.ContainerID("db"), errors.New("test error"))
+		mockDocker.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+		mockDocker.EXPECT().
+			RemoveContainer(gomock.Any(), gomock.Eq(docker.ContainerID("db"))).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			RemoveNetwork(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := runner.DockerRunnerImpl{}
+		defer sut.SetDocker(mockDocker)()
+		defer sut.SetLogFunc(runner.NothingToDo)()
+
+		// expect
+		if err := sut.Run(context.Background(), dir, tag, cmd); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+
+	t.Run("when task container fails", func(t *testing.T) {
+		// given
+		dir, cleanup := servicesDir(t, "services:\n  db:\n    image: postgres\n")
+		defer cleanup()
+
+		tag := docker.Tag(fmt.Sprintf("duci/test:%s", random.String(8)))
+		cmd := docker.Command{"echo", "test"}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		conID := docker.ContainerID("task")
+
+		mockDocker := mock_docker.NewMockDocker(ctrl)
+		mockDocker.EXPECT().
+			Build(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(stubLog(t, ctrl), nil)
+		mockDocker.EXPECT().
+			CreateNetwork(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			StartService(gomock.Any(), gomock.Any(), gomock.Eq("db"), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(docker.ContainerID("db"), nil)
+		mockDocker.EXPECT().
+			Health(gomock.Any(), gomock.Eq(docker.ContainerID("db"))).
+			Times(1).
+			Return(docker.Healthy, nil)
+		mockDocker.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(conID, nil, errors.New("test error"))
+		mockDocker.EXPECT().
+			RemoveContainer(gomock.Any(), gomock.Eq(conID)).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			RemoveContainer(gomock.Any(), gomock.Eq(docker.ContainerID("db"))).
+			Times(1).
+			Return(nil)
+		mockDocker.EXPECT().
+			RemoveNetwork(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(nil)
+
+		// and
+		sut := runner.DockerRunnerImpl{}
+		defer sut.SetDocker(mockDocker)()
+		defer sut.SetLogFunc(runner.NothingToDo)()
+
+		// expect
+		if err := sut.Run(context.Background(), dir, tag, cmd); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+
+	t.Run("with network mode none", func(t *testing.T) {
+		// given
+		dir, cleanup := servicesDir(t, "services:\n  db:\n    image: postgres\nresources:\n  network_mode: none\n")
+		defer cleanup()
+
+		tag := docker.Tag(fmt.Sprintf("duci/test:%s", random.String(8)))
+		cmd := docker.Command{"echo", "test"}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// and
+		mockDocker := mock_docker.NewMockDocker(ctrl)
+		mockDocker.EXPECT().
+			Build(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(stubLog(t, ctrl), nil)
+		mockDocker.EXPECT().
+			CreateNetwork(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+		mockDocker.EXPECT().
+			Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := runner.DockerRunnerImpl{}
+		defer sut.SetDocker(mockDocker)()
+		defer sut.SetLogFunc(runner.NothingToDo)()
+
+		// expect
+		if err := sut.Run(context.Background(), dir, tag, cmd); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+}
+
+func servicesDir(t *testing.T, config string) (workDir job.WorkDir, clean func()) {
+	t.Helper()
+
+	dir, clean := tmpDir(t)
+	if err := os.MkdirAll(filepath.Join(dir.String(), ".duci"), 0700); err != nil {
+		t.Fatalf("error occur: %+v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir.String(), ".duci", "config.yml"), []byte(config), 0400); err != nil {
+		t.Fatalf("error occur: %+v", err)
+	}
+	return dir, clean
+}
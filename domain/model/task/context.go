@@ -0,0 +1,30 @@
+package task
+
+import "context"
+
+var (
+	ctxKey            = "duci_task"
+	combinationCtxKey = "duci_task_combination"
+)
+
+// ContextWithName returns a context with the name of task to run.
+func ContextWithName(parent context.Context, name string) context.Context {
+	return context.WithValue(parent, &ctxKey, name)
+}
+
+// NameFromContext returns the name of task in the context.
+func NameFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(&ctxKey).(string)
+	return name, ok && len(name) > 0
+}
+
+// ContextWithCombination returns a context with the combination of matrix to run.
+func ContextWithCombination(parent context.Context, combination Combination) context.Context {
+	return context.WithValue(parent, &combinationCtxKey, combination)
+}
+
+// CombinationFromContext returns the combination of matrix in the context.
+func CombinationFromContext(ctx context.Context) (Combination, bool) {
+	combination, ok := ctx.Value(&combinationCtxKey).(Combination)
+	return combination, ok && len(combination) > 0
+}
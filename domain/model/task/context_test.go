@@ -0,0 +1,61 @@
+package task_test
+
+import (
+	"context"
+	"github.com/duck8823/duci/domain/model/task"
+	"testing"
+)
+
+func TestNameFromContext(t *testing.T) {
+	t.Run("with name", func(t *testing.T) {
+		// given
+		ctx := task.ContextWithName(context.Background(), "test")
+
+		// when
+		got, ok := task.NameFromContext(ctx)
+
+		// then
+		if !ok {
+			t.Error("must be ok")
+		}
+
+		// and
+		if got != "test" {
+			t.Errorf("want: test, but got: %s", got)
+		}
+	})
+
+	t.Run("without name", func(t *testing.T) {
+		// expect
+		if _, ok := task.NameFromContext(context.Background()); ok {
+			t.Error("must not be ok")
+		}
+	})
+}
+
+func TestCombinationFromContext(t *testing.T) {
+	t.Run("with combination", func(t *testing.T) {
+		// given
+		ctx := task.ContextWithCombination(context.Background(), task.Combination{"GO": "1.13"})
+
+		// when
+		got, ok := task.CombinationFromContext(ctx)
+
+		// then
+		if !ok {
+			t.Error("must be ok")
+		}
+
+		// and
+		if got["GO"] != "1.13" {
+			t.Errorf("want: GO=1.13, but got: %s", got)
+		}
+	})
+
+	t.Run("without combination", func(t *testing.T) {
+		// expect
+		if _, ok := task.CombinationFromContext(context.Background()); ok {
+			t.Error("must not be ok")
+		}
+	})
+}
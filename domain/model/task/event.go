@@ -0,0 +1,32 @@
+package task
+
+import "strings"
+
+// EventType describes a kind of event triggers tasks
+type EventType string
+
+const (
+	// Push is a event of push to a branch
+	Push EventType = "push"
+	// Tag is a event of push of a tag
+	Tag EventType = "tag"
+	// PullRequest is a event of pull request
+	PullRequest EventType = "pull_request"
+	// Comment is a event of comment with a phrase on pull request
+	Comment EventType = "comment"
+)
+
+// Event represents what happened on repository.
+// Name is a branch name, a tag name, a action of pull request or a phrase of comment.
+type Event struct {
+	Type EventType
+	Name string
+}
+
+// PushEventOf returns a event of push to the ref such as `refs/heads/master` or `refs/tags/v1.0.0`.
+func PushEventOf(ref string) Event {
+	if strings.HasPrefix(ref, "refs/tags/") {
+		return Event{Type: Tag, Name: strings.TrimPrefix(ref, "refs/tags/")}
+	}
+	return Event{Type: Push, Name: strings.TrimPrefix(ref, "refs/heads/")}
+}
@@ -0,0 +1,196 @@
+package task
+
+import (
+	"fmt"
+	"github.com/duck8823/duci/domain/model/docker"
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+)
+
+// DockerfileKey is a key of combination for the path of Dockerfile
+const DockerfileKey = "dockerfile"
+
+// MaxJobs is the maximum number of jobs the tasks in `.duci/config.yml` can expand into
+const MaxJobs = 256
+
+// Matrix represents values to expand a task into a job for each combination of them.
+type Matrix struct {
+	Args         map[string][]string
+	Environments map[string][]string
+	Dockerfiles  []string
+	Exclude      []Combination
+	FailFast     bool `yaml:"fail_fast"`
+	Aggregate    bool
+}
+
+// Combination represents values of a job in the matrix keyed by the name, or DockerfileKey for the path of Dockerfile.
+type Combination map[string]string
+
+// String returns values sorted by the key, such as `db=mysql, go=1.13`
+func (c Combination) String() string {
+	var keys []string
+	for key := range c {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var values []string
+	for _, key := range keys {
+		values = append(values, fmt.Sprintf("%s=%s", key, c[key]))
+	}
+	return strings.Join(values, ", ")
+}
+
+// includes returns whether the combination has all values of other
+func (c Combination) includes(other Combination) bool {
+	for key, value := range other {
+		if v, ok := c[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Values represents values of a combination sorted into build args, environments and the path of Dockerfile
+type Values struct {
+	Args         map[string]string
+	Environments docker.Environments
+	Dockerfile   string
+}
+
+// Combinations returns combinations of the matrix except excluded ones, keeping the order of values.
+// It returns nil if the matrix is nil or has no values.
+func (m *Matrix) Combinations() []Combination {
+	if m == nil {
+		return nil
+	}
+
+	axes := m.axes()
+	if len(axes) == 0 {
+		return nil
+	}
+
+	combinations := []Combination{{}}
+	for _, axis := range axes {
+		var expanded []Combination
+		for _, combination := range combinations {
+			for _, value := range axis.values {
+				c := Combination{axis.key: value}
+				for k, v := range combination {
+					c[k] = v
+				}
+				expanded = append(expanded, c)
+			}
+		}
+		combinations = expanded
+	}
+
+	var included []Combination
+	for _, combination := range combinations {
+		if !m.excludes(combination) {
+			included = append(included, combination)
+		}
+	}
+	return included
+}
+
+// ValuesOf returns values of the combination sorted as declared in the matrix
+func (m *Matrix) ValuesOf(combination Combination) Values {
+	values := Values{Args: map[string]string{}, Environments: docker.Environments{}}
+	if m == nil {
+		return values
+	}
+	for key, value := range combination {
+		if _, ok := m.Args[key]; ok {
+			values.Args[key] = value
+		} else if _, ok := m.Environments[key]; ok {
+			values.Environments[key] = value
+		} else if key == DockerfileKey {
+			values.Dockerfile = value
+		}
+	}
+	return values
+}
+
+// excludes returns whether the combination matches any of exclusions
+func (m *Matrix) excludes(combination Combination) bool {
+	for _, exclusion := range m.Exclude {
+		if combination.includes(exclusion) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate returns a error if keys of values are duplicated or all combinations are excluded
+func (m *Matrix) validate() error {
+	if m == nil {
+		return nil
+	}
+	for key := range m.Args {
+		if _, ok := m.Environments[key]; ok {
+			return errors.Errorf("matrix key %s is declared in both args and environments", key)
+		}
+	}
+	if _, ok := m.Args[DockerfileKey]; ok {
+		return errors.Errorf("matrix key %s is reserved for dockerfiles", DockerfileKey)
+	}
+	if _, ok := m.Environments[DockerfileKey]; ok {
+		return errors.Errorf("matrix key %s is reserved for dockerfiles", DockerfileKey)
+	}
+	// checked before expanding combinations, which can be too many to hold
+	if m.size() > MaxJobs {
+		return errors.Errorf("matrix must have at most %d combinations", MaxJobs)
+	}
+	if len(m.axes()) > 0 && len(m.Combinations()) == 0 {
+		return errors.New("all combinations of matrix are excluded")
+	}
+	return nil
+}
+
+// size returns the number of combinations including excluded ones, or MaxJobs+1 if it exceeds MaxJobs
+func (m *Matrix) size() int {
+	size := 1
+	for _, axis := range m.axes() {
+		size *= len(axis.values)
+		if size > MaxJobs {
+			return MaxJobs + 1
+		}
+	}
+	return size
+}
+
+// axes returns keys with values in order of args, environments and dockerfiles
+func (m *Matrix) axes() []axis {
+	var axes []axis
+	for _, key := range sortedKeys(m.Args) {
+		axes = append(axes, axis{key: key, values: m.Args[key]})
+	}
+	for _, key := range sortedKeys(m.Environments) {
+		axes = append(axes, axis{key: key, values: m.Environments[key]})
+	}
+	axes = append(axes, axis{key: DockerfileKey, values: m.Dockerfiles})
+
+	var declared []axis
+	for _, axis := range axes {
+		if len(axis.values) > 0 {
+			declared = append(declared, axis)
+		}
+	}
+	return declared
+}
+
+type axis struct {
+	key    string
+	values []string
+}
+
+func sortedKeys(m map[string][]string) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,201 @@
+package task_test
+
+import (
+	"github.com/duck8823/duci/domain/model/docker"
+	"github.com/duck8823/duci/domain/model/task"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+)
+
+func TestMatrix_Combinations(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		name string
+		in   *task.Matrix
+		want []task.Combination
+	}{
+		{
+			name: "with nil",
+			in:   nil,
+			want: nil,
+		},
+		{
+			name: "without values",
+			in:   &task.Matrix{FailFast: true},
+			want: nil,
+		},
+		{
+			name: "with args, environments and dockerfiles",
+			in: &task.Matrix{
+				Args:         map[string][]string{"GO": {"1.12", "1.13"}},
+				Environments: map[string][]string{"DB": {"postgres", "mysql"}},
+				Dockerfiles:  []string{"Dockerfile"},
+			},
+			want: []task.Combination{
+				{"GO": "1.12", "DB": "postgres", "dockerfile": "Dockerfile"},
+				{"GO": "1.12", "DB": "mysql", "dockerfile": "Dockerfile"},
+				{"GO": "1.13", "DB": "postgres", "dockerfile": "Dockerfile"},
+				{"GO": "1.13", "DB": "mysql", "dockerfile": "Dockerfile"},
+			},
+		},
+		{
+			name: "with exclusions",
+			in: &task.Matrix{
+				Environments: map[string][]string{"GO": {"1.12", "1.13"}, "DB": {"postgres", "mysql"}},
+				Exclude:      []task.Combination{{"GO": "1.12", "DB": "mysql"}},
+			},
+			want: []task.Combination{
+				{"DB": "postgres", "GO": "1.12"},
+				{"DB": "postgres", "GO": "1.13"},
+				{"DB": "mysql", "GO": "1.13"},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			got := tt.in.Combinations()
+
+			// then
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("must be equal, but %+v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestMatrix_ValuesOf(t *testing.T) {
+	// given
+	sut := &task.Matrix{
+		Args:         map[string][]string{"GO": {"1.12", "1.13"}},
+		Environments: map[string][]string{"DB": {"postgres", "mysql"}},
+		Dockerfiles:  []string{"Dockerfile", ".duci/Dockerfile.alpine"},
+	}
+
+	// and
+	want := task.Values{
+		Args:         map[string]string{"GO": "1.13"},
+		Environments: docker.Environments{"DB": "mysql"},
+		Dockerfile:   ".duci/Dockerfile.alpine",
+	}
+
+	// when
+	got := sut.ValuesOf(task.Combination{"GO": "1.13", "DB": "mysql", "dockerfile": ".duci/Dockerfile.alpine"})
+
+	// then
+	if !cmp.Equal(got, want) {
+		t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+	}
+}
+
+func TestCombination_String(t *testing.T) {
+	// given
+	sut := task.Combination{"go": "1.13", "db": "mysql"}
+
+	// expect
+	if sut.String() != "db=mysql, go=1.13" {
+		t.Errorf("want: db=mysql, go=1.13, but got: %s", sut.String())
+	}
+}
+
+func TestParse_Matrix(t *testing.T) {
+	t.Run("with matrix", func(t *testing.T) {
+		// given
+		in := []byte(`
+tasks:
+  test:
+    command: [make, test]
+    matrix:
+      args:
+        GO_VERSION: ["1.12", "1.13"]
+      environments:
+        DB: [postgres, mysql]
+      dockerfiles: [Dockerfile, .duci/Dockerfile.alpine]
+      exclude:
+        - GO_VERSION: "1.12"
+          DB: mysql
+      fail_fast: true
+      aggregate: true
+`)
+
+		// and
+		want := &task.Matrix{
+			Args:         map[string][]string{"GO_VERSION": {"1.12", "1.13"}},
+			Environments: map[string][]string{"DB": {"postgres", "mysql"}},
+			Dockerfiles:  []string{"Dockerfile", ".duci/Dockerfile.alpine"},
+			Exclude:      []task.Combination{{"GO_VERSION": "1.12", "DB": "mysql"}},
+			FailFast:     true,
+			Aggregate:    true,
+		}
+
+		// when
+		got, err := task.Parse(in)
+
+		// then
+		if err != nil {
+			t.Fatalf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if !cmp.Equal(got["test"].Matrix, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got["test"].Matrix, want))
+		}
+	})
+
+	t.Run("with the maximum number of combinations", func(t *testing.T) {
+		// given
+		in := []byte("tasks:\n  test:\n    matrix:\n" +
+			"      args: {A: [a, b, c, d, e, f, g, h, i, j, k, l, m, n, o, p]}\n" +
+			"      environments: {B: [a, b, c, d, e, f, g, h, i, j, k, l, m, n, o, p]}\n")
+
+		// when
+		got, err := task.Parse(in)
+
+		// then
+		if err != nil {
+			t.Fatalf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if len(got["test"].Matrix.Combinations()) != task.MaxJobs {
+			t.Errorf("combinations must be %d, but got %d", task.MaxJobs, len(got["test"].Matrix.Combinations()))
+		}
+	})
+
+	// where
+	for _, tt := range []struct {
+		name string
+		in   string
+	}{
+		{
+			name: "when key is declared in both args and environments",
+			in:   "tasks:\n  test:\n    matrix:\n      args: {GO: ['1.13']}\n      environments: {GO: ['1.13']}\n",
+		},
+		{
+			name: "when key is reserved",
+			in:   "tasks:\n  test:\n    matrix:\n      environments: {dockerfile: [Dockerfile]}\n",
+		},
+		{
+			name: "when too many combinations",
+			in: "tasks:\n  test:\n    matrix:\n      args:\n" +
+				"        A: [a, b, c, d, e, f, g, h, i, j, k, l, m, n, o, p, q]\n" +
+				"        B: [a, b, c, d, e, f, g, h, i, j, k, l, m, n, o, p]\n",
+		},
+		{
+			name: "when tasks expand into too many jobs",
+			in: "tasks:\n" +
+				"  test:\n    matrix:\n      args: {A: [a, b, c, d, e, f, g, h, i, j, k, l, m, n, o, p]}\n      environments: {B: [a, b, c, d, e, f, g, h, i, j, k, l, m, n, o, p]}\n" +
+				"  lint:\n    command: [make, lint]\n",
+		},
+		{
+			name: "when all combinations are excluded",
+			in:   "tasks:\n  test:\n    matrix:\n      environments: {GO: ['1.13']}\n      exclude: [{GO: '1.13'}]\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// expect
+			if _, err := task.Parse([]byte(tt.in)); err == nil {
+				t.Error("error must not be nil")
+			}
+		})
+	}
+}
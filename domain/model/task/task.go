@@ -0,0 +1,178 @@
+package task
+
+import (
+	"bytes"
+	"github.com/duck8823/duci/domain/model/docker"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"path"
+	"sort"
+	"time"
+)
+
+// Task represents a command declared in `.duci/config.yml` and events to run it.
+type Task struct {
+	Command      docker.Command
+	Dockerfile   string
+	Environments docker.Environments
+	Timeout      int64
+	Triggers     Triggers
+	Matrix       *Matrix
+	Needs        []string
+}
+
+// TimeoutDuration returns timeout duration, or zero if not set.
+func (t *Task) TimeoutDuration() time.Duration {
+	return time.Duration(t.Timeout) * time.Second
+}
+
+// Match returns whether the event triggers the task.
+func (t *Task) Match(event Event) bool {
+	var patterns []string
+	switch event.Type {
+	case Push:
+		patterns = t.Triggers.Push
+	case Tag:
+		patterns = t.Triggers.Tag
+	case PullRequest:
+		patterns = t.Triggers.PullRequest
+	case Comment:
+		patterns = t.Triggers.Comment
+	}
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, event.Name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Triggers represents patterns of events, such as branch names of push or actions of pull request.
+type Triggers struct {
+	Push        []string
+	Tag         []string
+	PullRequest []string `yaml:"pull_request"`
+	Comment     []string
+}
+
+// Tasks represents tasks keyed by the name.
+type Tasks map[string]*Task
+
+// Match returns sorted names of tasks triggered by the event.
+func (t Tasks) Match(event Event) []string {
+	var names []string
+	for name, task := range t {
+		if task != nil && task.Match(event) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithNeeds returns sorted names of the tasks and tasks they need recursively
+func (t Tasks) WithNeeds(names []string) []string {
+	seen := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		task, ok := t[name]
+		if !ok || seen[name] {
+			return
+		}
+		seen[name] = true
+		if task == nil {
+			return
+		}
+		for _, need := range task.Needs {
+			visit(need)
+		}
+	}
+	for _, name := range names {
+		visit(name)
+	}
+
+	var all []string
+	for name := range seen {
+		all = append(all, name)
+	}
+	sort.Strings(all)
+	return all
+}
+
+// validateNeeds returns a error if tasks need each other circularly
+func (t Tasks) validateNeeds() error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	states := make(map[string]int)
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch states[name] {
+		case visiting:
+			return errors.Errorf("task %s needs itself circularly", name)
+		case visited:
+			return nil
+		}
+		states[name] = visiting
+		if task := t[name]; task != nil {
+			for _, need := range task.Needs {
+				if err := visit(need); err != nil {
+					return err
+				}
+			}
+		}
+		states[name] = visited
+		return nil
+	}
+	for name := range t {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jobs returns the number of jobs the tasks expand into
+func (t Tasks) jobs() int {
+	jobs := 0
+	for _, task := range t {
+		if combinations := task.Matrix.Combinations(); len(combinations) > 0 {
+			jobs += len(combinations)
+		} else {
+			jobs++
+		}
+	}
+	return jobs
+}
+
+// Parse returns tasks in the content of `.duci/config.yml`.
+func Parse(content []byte) (Tasks, error) {
+	config := &struct {
+		Tasks Tasks
+	}{}
+	if err := yaml.NewDecoder(bytes.NewReader(content)).Decode(config); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for name, task := range config.Tasks {
+		if task == nil {
+			config.Tasks[name] = &Task{}
+			continue
+		}
+		if err := task.Matrix.validate(); err != nil {
+			return nil, errors.Wrapf(err, "invalid task %s", name)
+		}
+		for _, need := range task.Needs {
+			if _, ok := config.Tasks[need]; !ok {
+				return nil, errors.Errorf("invalid task %s: needs undeclared task %s", name, need)
+			}
+		}
+	}
+	if err := config.Tasks.validateNeeds(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if jobs := config.Tasks.jobs(); jobs > MaxJobs {
+		return nil, errors.Errorf("tasks must expand into at most %d jobs, but got %d", MaxJobs, jobs)
+	}
+	return config.Tasks, nil
+}
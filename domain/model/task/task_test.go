@@ -0,0 +1,248 @@
+package task_test
+
+import (
+	"github.com/duck8823/duci/domain/model/docker"
+	"github.com/duck8823/duci/domain/model/task"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("with tasks", func(t *testing.T) {
+		// given
+		in := []byte(`
+tasks:
+  test:
+    command: [make, test]
+    environments:
+      GO111MODULE: "on"
+    timeout: 600
+    triggers:
+      push: [master, "release/*"]
+      pull_request: [opened, synchronize]
+  release:
+    command: [make, release]
+    dockerfile: .duci/Dockerfile.release
+    triggers:
+      tag: ["v*"]
+`)
+
+		// and
+		want := task.Tasks{
+			"test": {
+				Command:      docker.Command{"make", "test"},
+				Environments: docker.Environments{"GO111MODULE": "on"},
+				Timeout:      600,
+				Triggers: task.Triggers{
+					Push:        []string{"master", "release/*"},
+					PullRequest: []string{"opened", "synchronize"},
+				},
+			},
+			"release": {
+				Command:    docker.Command{"make", "release"},
+				Dockerfile: ".duci/Dockerfile.release",
+				Triggers: task.Triggers{
+					Tag: []string{"v*"},
+				},
+			},
+		}
+
+		// when
+		got, err := task.Parse(in)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("without tasks", func(t *testing.T) {
+		// when
+		got, err := task.Parse([]byte("volumes: [/tmp:/tmp]\n"))
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if len(got) != 0 {
+			t.Errorf("tasks must be empty, but got %+v", got)
+		}
+	})
+
+	t.Run("with invalid format", func(t *testing.T) {
+		// expect
+		if _, err := task.Parse([]byte("tasks: [")); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+}
+
+func TestTasks_Match(t *testing.T) {
+	// given
+	tasks := task.Tasks{
+		"test": {
+			Triggers: task.Triggers{
+				Push:        []string{"*"},
+				PullRequest: []string{"opened", "synchronize"},
+				Comment:     []string{"test"},
+			},
+		},
+		"lint": {
+			Triggers: task.Triggers{
+				Push: []string{"master"},
+			},
+		},
+		"release": {
+			Triggers: task.Triggers{
+				Tag: []string{"v*"},
+			},
+		},
+	}
+
+	// where
+	for _, tt := range []struct {
+		in   task.Event
+		want []string
+	}{
+		{in: task.Event{Type: task.Push, Name: "master"}, want: []string{"lint", "test"}},
+		{in: task.Event{Type: task.Push, Name: "feature"}, want: []string{"test"}},
+		{in: task.Event{Type: task.Push, Name: "feature/foo"}, want: nil},
+		{in: task.Event{Type: task.Tag, Name: "v1.0.0"}, want: []string{"release"}},
+		{in: task.Event{Type: task.PullRequest, Name: "opened"}, want: []string{"test"}},
+		{in: task.Event{Type: task.PullRequest, Name: "closed"}, want: nil},
+		{in: task.Event{Type: task.Comment, Name: "test"}, want: []string{"test"}},
+	} {
+		// when
+		got := tasks.Match(tt.in)
+
+		// then
+		if !cmp.Equal(got, tt.want) {
+			t.Errorf("%+v must be equal, but %+v", tt.in, cmp.Diff(got, tt.want))
+		}
+	}
+}
+
+func TestTask_TimeoutDuration(t *testing.T) {
+	// given
+	sut := &task.Task{Timeout: 30}
+
+	// expect
+	if sut.TimeoutDuration() != 30*time.Second {
+		t.Errorf("want: %s, but got: %s", 30*time.Second, sut.TimeoutDuration())
+	}
+}
+
+func TestPushEventOf(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		in   string
+		want task.Event
+	}{
+		{in: "refs/heads/master", want: task.Event{Type: task.Push, Name: "master"}},
+		{in: "refs/heads/feature/foo", want: task.Event{Type: task.Push, Name: "feature/foo"}},
+		{in: "refs/tags/v1.0.0", want: task.Event{Type: task.Tag, Name: "v1.0.0"}},
+	} {
+		// when
+		got := task.PushEventOf(tt.in)
+
+		// then
+		if got != tt.want {
+			t.Errorf("want: %+v, but got: %+v", tt.want, got)
+		}
+	}
+}
+
+func TestParse_Needs(t *testing.T) {
+	t.Run("with needs", func(t *testing.T) {
+		// given
+		in := []byte(`
+tasks:
+  build:
+  unit:
+    needs: [build]
+  integration:
+    needs: [unit]
+`)
+
+		// when
+		got, err := task.Parse(in)
+
+		// then
+		if err != nil {
+			t.Fatalf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if !cmp.Equal(got["integration"].Needs, []string{"unit"}) {
+			t.Errorf("needs must be [unit], but got %+v", got["integration"].Needs)
+		}
+
+		// and
+		if got["build"] == nil {
+			t.Error("task without values must not be nil")
+		}
+	})
+
+	// where
+	for _, tt := range []struct {
+		name string
+		in   string
+	}{
+		{
+			name: "when needs undeclared task",
+			in:   "tasks:\n  test:\n    needs: [build]\n",
+		},
+		{
+			name: "when tasks need each other",
+			in:   "tasks:\n  build:\n    needs: [test]\n  test:\n    needs: [lint]\n  lint:\n    needs: [build]\n",
+		},
+		{
+			name: "when task needs itself",
+			in:   "tasks:\n  test:\n    needs: [test]\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// expect
+			if _, err := task.Parse([]byte(tt.in)); err == nil {
+				t.Error("error must not be nil")
+			}
+		})
+	}
+}
+
+func TestTasks_WithNeeds(t *testing.T) {
+	// given
+	sut := task.Tasks{
+		"build":       {},
+		"unit":        {Needs: []string{"build"}},
+		"lint":        {Needs: []string{"build"}},
+		"integration": {Needs: []string{"unit", "lint"}},
+		"release":     {},
+	}
+
+	// where
+	for _, tt := range []struct {
+		in   []string
+		want []string
+	}{
+		{in: []string{"integration"}, want: []string{"build", "integration", "lint", "unit"}},
+		{in: []string{"unit", "release"}, want: []string{"build", "release", "unit"}},
+		{in: []string{"unknown"}, want: nil},
+	} {
+		// when
+		got := sut.WithNeeds(tt.in)
+
+		// then
+		if !cmp.Equal(got, tt.want) {
+			t.Errorf("%+v must be equal, but %+v", tt.in, cmp.Diff(got, tt.want))
+		}
+	}
+}
@@ -8,7 +8,7 @@ require (
 	github.com/docker/distribution v2.7.1-0.20190205005809-0d3efadf0154+incompatible // indirect
 	github.com/docker/docker v0.7.3-0.20180814124044-678d4b3a6d4c
 	github.com/docker/go-connections v0.4.0 // indirect
-	github.com/docker/go-units v0.3.1 // indirect
+	github.com/docker/go-units v0.3.1
 	github.com/fsnotify/fsnotify v1.4.9 // indirect
 	github.com/go-chi/chi v4.1.2+incompatible
 	github.com/gogo/protobuf v1.3.2 // indirect
@@ -29,6 +29,7 @@ require (
 	github.com/spf13/cobra v1.4.0
 	github.com/syndtr/goleveldb v1.0.0
 	github.com/tcnksm/go-latest v0.0.0-20170313132115-e3007ae9052e
+	golang.org/x/net v0.0.0-20210405180319-a5a99cb37ef4
 	golang.org/x/oauth2 v0.0.0-20210402161424-2e8d93401602
 	google.golang.org/appengine v1.6.7 // indirect
 	google.golang.org/genproto v0.0.0-20210602131652-f16073e35f0c // indirect
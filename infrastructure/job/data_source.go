@@ -4,21 +4,32 @@ import (
 	"bytes"
 	"encoding/json"
 	"github.com/duck8823/duci/domain/model/job"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"sort"
+	"strings"
+	"sync"
 )
 
 type dataSource struct {
 	db LevelDB
+	mu sync.Mutex
 }
 
-// NewDataSource returns job data source
+// NewDataSource returns job data source.
+// Jobs stored in older format are migrated when opened.
 func NewDataSource(path string) (job.Repository, error) {
 	db, err := leveldb.OpenFile(path, nil)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
-	return &dataSource{db}, nil
+	if err := migrate(db); err != nil {
+		_ = db.Close()
+		return nil, errors.WithStack(err)
+	}
+	return &dataSource{db: db}, nil
 }
 
 // FindBy returns job found by ID
@@ -38,13 +49,299 @@ func (d *dataSource) FindBy(id job.ID) (*job.Job, error) {
 	return job, nil
 }
 
-// Save store job to data source
+// FindLogs returns log lines of the job from the sequence number to before the end.
+// Negative end means the last of lines.
+func (d *dataSource) FindLogs(id job.ID, from int, to int) ([]job.LogLine, error) {
+	rng, ok := logRange(id, from, to)
+	if !ok {
+		return []job.LogLine{}, nil
+	}
+
+	iter := d.db.NewIterator(rng, nil)
+	defer iter.Release()
+
+	lines := []job.LogLine{}
+	for iter.Next() {
+		line := job.LogLine{}
+		if err := json.Unmarshal(iter.Value(), &line); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		lines = append(lines, line)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return lines, nil
+}
+
+// ReverseLogs visits log lines of the job from before the end back to the sequence number, until visit returns false.
+// Negative end means the last of lines.
+func (d *dataSource) ReverseLogs(id job.ID, from int, to int, visit func(seq int, line job.LogLine) bool) error {
+	rng, ok := logRange(id, from, to)
+	if !ok {
+		return nil
+	}
+
+	iter := d.db.NewIterator(rng, nil)
+	defer iter.Release()
+
+	for ok := iter.Last(); ok; ok = iter.Prev() {
+		seq, err := parseLogKey(iter.Key())
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		line := job.LogLine{}
+		if err := json.Unmarshal(iter.Value(), &line); err != nil {
+			return errors.WithStack(err)
+		}
+		if !visit(seq, line) {
+			break
+		}
+	}
+	return errors.WithStack(iter.Error())
+}
+
+// logRange returns the range of log keys from the sequence number to before the end, or false if it is empty
+func logRange(id job.ID, from int, to int) (*util.Range, bool) {
+	if from < 0 {
+		from = 0
+	}
+	rng := util.BytesPrefix([]byte(logKeyPrefix(id)))
+	rng.Start = logKey(id, from)
+	if to >= 0 {
+		if to <= from {
+			return nil, false
+		}
+		rng.Limit = logKey(id, to)
+	}
+	return rng, true
+}
+
+// Save store metadata of job to data source. Log lines are stored by AppendLog.
 func (d *dataSource) Save(job job.Job) error {
-	data, err := job.ToBytes()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.save(job)
+}
+
+// save store metadata and indexes of job, and removes indexes of the stored one which no longer match such as of the previous state.
+// The caller must hold the lock.
+func (d *dataSource) save(j job.Job) error {
+	data, err := j.ToBytes()
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	if err := d.db.Put(job.ID.ToSlice(), data, nil); err != nil {
+
+	batch := new(leveldb.Batch)
+	if old, err := d.FindBy(j.ID); err == nil {
+		for _, key := range staleKeys(*old, j) {
+			batch.Delete(key)
+		}
+	} else if err != job.ErrNotFound {
+		return errors.WithStack(err)
+	}
+	batch.Put(j.ID.ToSlice(), data)
+	for _, key := range indexKeys(j) {
+		batch.Put(key, []byte{})
+	}
+
+	if err := d.db.Write(batch, nil); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// AppendLog store the log line next to the last one, and returns its sequence number
+func (d *dataSource) AppendLog(id job.ID, line job.LogLine) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	seq, err := d.nextSeq(id)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if seq == 0 {
+		if err := d.initialize(id); err != nil {
+			return 0, errors.WithStack(err)
+		}
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if err := d.db.Put(logKey(id, seq), data, nil); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return seq, nil
+}
+
+// nextSeq returns sequence number of a log line appended next
+func (d *dataSource) nextSeq(id job.ID) (int, error) {
+	iter := d.db.NewIterator(util.BytesPrefix([]byte(logKeyPrefix(id))), nil)
+	defer iter.Release()
+
+	if !iter.Last() {
+		return 0, errors.WithStack(iter.Error())
+	}
+	seq, err := parseLogKey(iter.Key())
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return seq + 1, nil
+}
+
+// initialize store empty job if metadata of the job does not exist.
+// The caller must hold the lock.
+func (d *dataSource) initialize(id job.ID) error {
+	if _, err := d.db.Get(id.ToSlice(), nil); err != leveldb.ErrNotFound {
+		return errors.WithStack(err)
+	}
+	return d.save(job.Job{ID: id, Finished: false})
+}
+
+// Search returns jobs matching the query in descending order of queued time
+func (d *dataSource) Search(q job.Query) (*job.Page, error) {
+	prefix := indexPrefix(q)
+	rng := util.BytesPrefix([]byte(prefix))
+	if !q.Until.IsZero() {
+		rng.Start = []byte(prefix + invertedTime(q.Until))
+	}
+	if len(q.Cursor) > 0 {
+		suffix, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if start := []byte(prefix + suffix + "\x00"); bytes.Compare(start, rng.Start) > 0 {
+			rng.Start = start
+		}
+	}
+
+	iter := d.db.NewIterator(rng, nil)
+	defer iter.Release()
+
+	page := &job.Page{Jobs: []job.Job{}}
+	var last string
+	for iter.Next() {
+		suffix := strings.TrimPrefix(string(iter.Key()), prefix)
+		queuedAt, id, err := parseIndexSuffix(suffix)
+		if err != nil {
+			// keys of other repositories with the name as prefix, such as `owner/name/...` for `owner`
+			continue
+		}
+		if !q.Since.IsZero() && queuedAt.Before(q.Since) {
+			break
+		}
+
+		j, err := d.FindBy(id)
+		if err == job.ErrNotFound {
+			continue
+		} else if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if !q.Match(j) {
+			continue
+		}
+
+		if q.Limit > 0 && len(page.Jobs) == q.Limit {
+			page.Next = encodeCursor(last)
+			break
+		}
+		page.Jobs = append(page.Jobs, *j)
+		last = suffix
+	}
+	if err := iter.Error(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return page, nil
+}
+
+// All returns all jobs including ones not indexed in descending order of queued time, and jobs not queued are last.
+func (d *dataSource) All() ([]job.Job, error) {
+	// keys of job are uuid in lower case hex, so that the range excludes keys of indexes and logs
+	iter := d.db.NewIterator(&util.Range{Start: []byte("0"), Limit: []byte("g")}, nil)
+	defer iter.Release()
+
+	jobs := []job.Job{}
+	for iter.Next() {
+		id, err := uuid.Parse(string(iter.Key()))
+		if err != nil {
+			continue
+		}
+
+		j := job.Job{}
+		if err := json.Unmarshal(iter.Value(), &j); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		j.ID = job.ID(id)
+		jobs = append(jobs, j)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	sort.SliceStable(jobs, func(i, k int) bool {
+		if jobs[k].QueuedAt == nil {
+			return jobs[i].QueuedAt != nil
+		}
+		return jobs[i].QueuedAt != nil && jobs[i].QueuedAt.After(*jobs[k].QueuedAt)
+	})
+	return jobs, nil
+}
+
+// Delete removes metadata, indexes and log lines of the job
+func (d *dataSource) Delete(id job.ID) error {
+	j, err := d.FindBy(id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Delete(id.ToSlice())
+	for _, key := range indexKeys(*j) {
+		batch.Delete(key)
+	}
+
+	iter := d.db.NewIterator(util.BytesPrefix([]byte(logKeyPrefix(id))), nil)
+	defer iter.Release()
+	for iter.Next() {
+		batch.Delete(iter.Key())
+	}
+	if err := iter.Error(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := d.db.Write(batch, nil); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// SizeOf returns approximate bytes of metadata and log lines of the job
+func (d *dataSource) SizeOf(id job.ID) (int64, error) {
+	sizes, err := d.db.SizeOf([]util.Range{
+		{Start: id.ToSlice(), Limit: append(id.ToSlice(), 0)},
+		*util.BytesPrefix([]byte(logKeyPrefix(id))),
+	})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return sizes.Sum(), nil
+}
+
+// Size returns approximate bytes of the whole data source
+func (d *dataSource) Size() (int64, error) {
+	sizes, err := d.db.SizeOf([]util.Range{{Start: nil, Limit: []byte{0xff}}})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return sizes.Sum(), nil
+}
+
+// Compact reclaims space of removed data
+func (d *dataSource) Compact() error {
+	if err := d.db.CompactRange(util.Range{}); err != nil {
 		return errors.WithStack(err)
 	}
 	return nil
@@ -2,6 +2,7 @@ package job_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/duck8823/duci/domain/model/job"
 	. "github.com/duck8823/duci/infrastructure/job"
 	"github.com/duck8823/duci/infrastructure/job/mock_job"
@@ -11,8 +12,10 @@ import (
 	"github.com/labstack/gommon/random"
 	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -77,7 +80,6 @@ func TestDataSource_FindBy(t *testing.T) {
 		want := &job.Job{
 			ID:       id,
 			Finished: false,
-			Stream:   []job.LogLine{{Timestamp: time.Now(), Message: "Hello Test"}},
 		}
 		data, err := json.Marshal(want)
 		if err != nil {
@@ -112,6 +114,106 @@ func TestDataSource_FindBy(t *testing.T) {
 		}
 	})
 
+	t.Run("when returns data with metadata", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+		code := int64(1)
+		queuedAt := time.Unix(10, 0)
+		startedAt := time.Unix(20, 0)
+		finishedAt := time.Unix(30, 0)
+
+		// and
+		want := &job.Job{
+			ID: id,
+			Trigger: &job.Trigger{
+				Repository: "duck8823/duci",
+				Ref:        "refs/heads/master",
+				SHA:        "aa218f56b14c9653891f9e74264a383fa43fefbd",
+				Event:      "push",
+				TaskName:   "duci/push",
+				TargetURL:  "http://example.com/logs/" + uuid.UUID(id).String(),
+			},
+			State:      job.FAILURE,
+			ExitCode:   &code,
+			QueuedAt:   &queuedAt,
+			StartedAt:  &startedAt,
+			FinishedAt: &finishedAt,
+			Finished:   true,
+		}
+		data, err := json.Marshal(want)
+		if err != nil {
+			t.Fatalf("error occurred: %+v", err)
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		db := mock_job.NewMockLevelDB(ctrl)
+		db.EXPECT().
+			Get(gomock.Eq([]byte(uuid.UUID(id).String())), gomock.Nil()).
+			Times(1).
+			Return(data, nil)
+
+		// and
+		sut := &DataSource{}
+		defer sut.SetDB(db)()
+
+		// when
+		got, err := sut.FindBy(id)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("when stored data is legacy format without metadata", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+
+		// and
+		data := []byte(`{"ID":[0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0],"finished":true,"stream":[{"time":"2018-09-21T22:19:33+09:00","message":"Hello Test"}]}`)
+
+		// and
+		want := &job.Job{
+			ID:       id,
+			Finished: true,
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		db := mock_job.NewMockLevelDB(ctrl)
+		db.EXPECT().
+			Get(gomock.Eq([]byte(uuid.UUID(id).String())), gomock.Nil()).
+			Times(1).
+			Return(data, nil)
+
+		// and
+		sut := &DataSource{}
+		defer sut.SetDB(db)()
+
+		// when
+		got, err := sut.FindBy(id)
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
 	t.Run("when returns error", func(t *testing.T) {
 		// given
 		id := job.ID(uuid.New())
@@ -210,7 +312,7 @@ func TestDataSource_FindBy(t *testing.T) {
 }
 
 func TestDataSource_Save(t *testing.T) {
-	t.Run("when returns no error", func(t *testing.T) {
+	t.Run("when returns no error, without stream", func(t *testing.T) {
 		// given
 		id := job.ID(uuid.New())
 
@@ -218,11 +320,6 @@ func TestDataSource_Save(t *testing.T) {
 		j := &job.Job{
 			ID:       id,
 			Finished: false,
-			Stream:   []job.LogLine{{Timestamp: time.Now(), Message: "Hello Test"}},
-		}
-		data, err := json.Marshal(j)
-		if err != nil {
-			t.Fatalf("error occurred: %+v", err)
 		}
 
 		// and
@@ -231,7 +328,11 @@ func TestDataSource_Save(t *testing.T) {
 
 		db := mock_job.NewMockLevelDB(ctrl)
 		db.EXPECT().
-			Put(gomock.Eq([]byte(uuid.UUID(id).String())), gomock.Eq(data), gomock.Nil()).
+			Get(gomock.Eq([]byte(uuid.UUID(id).String())), gomock.Nil()).
+			Times(1).
+			Return(nil, leveldb.ErrNotFound)
+		db.EXPECT().
+			Write(gomock.Any(), gomock.Nil()).
 			Times(1).
 			Return(nil)
 
@@ -253,11 +354,6 @@ func TestDataSource_Save(t *testing.T) {
 		j := &job.Job{
 			ID:       id,
 			Finished: false,
-			Stream:   []job.LogLine{{Timestamp: time.Now(), Message: "Hello Test"}},
-		}
-		data, err := json.Marshal(j)
-		if err != nil {
-			t.Fatalf("error occurred: %+v", err)
 		}
 
 		// and
@@ -266,7 +362,11 @@ func TestDataSource_Save(t *testing.T) {
 
 		db := mock_job.NewMockLevelDB(ctrl)
 		db.EXPECT().
-			Put(gomock.Eq([]byte(uuid.UUID(id).String())), gomock.Eq(data), gomock.Nil()).
+			Get(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(nil, leveldb.ErrNotFound)
+		db.EXPECT().
+			Write(gomock.Any(), gomock.Nil()).
 			Times(1).
 			Return(errors.New("test error"))
 
@@ -280,4 +380,759 @@ func TestDataSource_Save(t *testing.T) {
 		}
 	})
 
+	t.Run("when failed to get stored job", func(t *testing.T) {
+		// given
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		db := mock_job.NewMockLevelDB(ctrl)
+		db.EXPECT().
+			Get(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(nil, errors.New("test error"))
+		db.EXPECT().
+			Write(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &DataSource{}
+		defer sut.SetDB(db)()
+
+		// expect
+		if err := sut.Save(job.Job{ID: job.ID(uuid.New())}); err == nil {
+			t.Error("error must not be nil")
+		}
+	})
+}
+
+func TestDataSource_Save_WithIndex(t *testing.T) {
+	// given
+	tmpDir := filepath.Join(os.TempDir(), random.String(16, random.Alphanumeric))
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	db, err := leveldb.OpenFile(tmpDir, nil)
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+	defer db.Close()
+
+	// and
+	id := job.ID(uuid.New())
+	queuedAt := time.Unix(0, 1)
+	j := job.Job{
+		ID:       id,
+		Trigger:  &job.Trigger{Repository: "duck8823/duci", SHA: "aa218f56b14c9653891f9e74264a383fa43fefbd"},
+		State:    job.QUEUED,
+		QueuedAt: &queuedAt,
+	}
+
+	// and
+	suffix := "7ffffffffffffffe/" + uuid.UUID(id).String()
+	want := []string{
+		"index/repo/duck8823/duci/" + suffix,
+		"index/sha/aa218f56b14c9653891f9e74264a383fa43fefbd/" + suffix,
+		"index/state/running/" + suffix,
+		"index/time/" + suffix,
+	}
+
+	// and
+	sut := &DataSource{}
+	defer sut.SetDB(db)()
+
+	// when
+	if err := sut.Save(j); err != nil {
+		t.Fatalf("error must be nil, but got %+v", err)
+	}
+	j.State = job.RUNNING
+	if err := sut.Save(j); err != nil {
+		t.Fatalf("error must be nil, but got %+v", err)
+	}
+
+	// then
+	var got []string
+	iter := db.NewIterator(util.BytesPrefix([]byte("index/")), nil)
+	defer iter.Release()
+	for iter.Next() {
+		got = append(got, string(iter.Key()))
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+	}
+}
+
+func TestDataSource_Search(t *testing.T) {
+	// given
+	tmpDir := filepath.Join(os.TempDir(), random.String(16, random.Alphanumeric))
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	sut, err := NewDataSource(tmpDir)
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+
+	// and
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var ids []job.ID
+	for i, tr := range []job.Trigger{
+		{Repository: "duck8823/duci", Ref: "refs/heads/master", SHA: "a"},
+		{Repository: "duck8823/duci", Ref: "refs/heads/feature", SHA: "b"},
+		{Repository: "duck8823/other", Ref: "refs/heads/master", SHA: "c"},
+		{Repository: "duck8823/duci", Ref: "refs/heads/master", SHA: "d"},
+	} {
+		id := job.ID(uuid.New())
+		ids = append(ids, id)
+
+		j := job.Job{ID: id}
+		j.Queue(tr, base.Add(time.Duration(i)*time.Minute))
+		if err := sut.Save(j); err != nil {
+			t.Fatalf("error occurred: %+v", err)
+		}
+		if i == 1 {
+			j.End(job.Result{State: job.FAILURE}, base.Add(time.Hour))
+			if err := sut.Save(j); err != nil {
+				t.Fatalf("error occurred: %+v", err)
+			}
+		}
+	}
+
+	// and
+	legacy := job.Job{ID: job.ID(uuid.New()), Finished: true}
+	if err := sut.Save(legacy); err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+
+	// where
+	for _, tt := range []struct {
+		name  string
+		query job.Query
+		want  []job.ID
+	}{
+		{
+			name:  "without conditions",
+			query: job.Query{},
+			want:  []job.ID{ids[3], ids[2], ids[1], ids[0]},
+		},
+		{
+			name:  "with repository",
+			query: job.Query{Repository: "duck8823/duci"},
+			want:  []job.ID{ids[3], ids[1], ids[0]},
+		},
+		{
+			name:  "with repository and ref",
+			query: job.Query{Repository: "duck8823/duci", Ref: "refs/heads/master"},
+			want:  []job.ID{ids[3], ids[0]},
+		},
+		{
+			name:  "with sha",
+			query: job.Query{SHA: "c"},
+			want:  []job.ID{ids[2]},
+		},
+		{
+			name:  "with sha and repository",
+			query: job.Query{Repository: "duck8823/duci", SHA: "c"},
+			want:  []job.ID{},
+		},
+		{
+			name:  "with state",
+			query: job.Query{State: job.FAILURE},
+			want:  []job.ID{ids[1]},
+		},
+		{
+			name:  "with state changed from",
+			query: job.Query{State: job.QUEUED},
+			want:  []job.ID{ids[3], ids[2], ids[0]},
+		},
+		{
+			name:  "with repository and state",
+			query: job.Query{Repository: "duck8823/duci", State: job.QUEUED},
+			want:  []job.ID{ids[3], ids[0]},
+		},
+		{
+			name:  "with time range",
+			query: job.Query{Since: base.Add(time.Minute), Until: base.Add(2 * time.Minute)},
+			want:  []job.ID{ids[2], ids[1]},
+		},
+		{
+			name:  "with unknown repository",
+			query: job.Query{Repository: "duck8823/unknown"},
+			want:  []job.ID{},
+		},
+		{
+			name:  "with owner of repository",
+			query: job.Query{Repository: "duck8823"},
+			want:  []job.ID{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			got, err := sut.Search(tt.query)
+
+			// then
+			if err != nil {
+				t.Fatalf("error must be nil, but got %+v", err)
+			}
+
+			// and
+			gotIDs := []job.ID{}
+			for _, j := range got.Jobs {
+				gotIDs = append(gotIDs, j.ID)
+			}
+			if !cmp.Equal(gotIDs, tt.want) {
+				t.Errorf("must be equal, but %+v", cmp.Diff(gotIDs, tt.want))
+			}
+
+			// and
+			if len(got.Next) > 0 {
+				t.Errorf("next must be empty, but got %s", got.Next)
+			}
+		})
+	}
+
+	t.Run("with pagination", func(t *testing.T) {
+		// given
+		query := job.Query{Repository: "duck8823/duci", Limit: 2}
+
+		// when
+		first, err := sut.Search(query)
+
+		// then
+		if err != nil {
+			t.Fatalf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if len(first.Jobs) != 2 || first.Jobs[0].ID != ids[3] || first.Jobs[1].ID != ids[1] {
+			t.Errorf("unexpected first page: %+v", first.Jobs)
+		}
+
+		// and
+		if len(first.Next) == 0 {
+			t.Fatal("next must not be empty")
+		}
+
+		// when
+		query.Cursor = first.Next
+		second, err := sut.Search(query)
+
+		// then
+		if err != nil {
+			t.Fatalf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if len(second.Jobs) != 1 || second.Jobs[0].ID != ids[0] {
+			t.Errorf("unexpected second page: %+v", second.Jobs)
+		}
+
+		// and
+		if len(second.Next) > 0 {
+			t.Errorf("next must be empty, but got %s", second.Next)
+		}
+	})
+
+	t.Run("with invalid cursor", func(t *testing.T) {
+		// when
+		got, err := sut.Search(job.Query{Cursor: "invalid cursor"})
+
+		// then
+		if errors.Cause(err) != job.ErrInvalidCursor {
+			t.Errorf("error must be %+v, but got %+v", job.ErrInvalidCursor, err)
+		}
+
+		// and
+		if got != nil {
+			t.Errorf("must be nil, but got %+v", got)
+		}
+	})
+}
+
+func TestDataSource_All(t *testing.T) {
+	// given
+	tmpDir := filepath.Join(os.TempDir(), random.String(16, random.Alphanumeric))
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	sut, err := NewDataSource(tmpDir)
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+
+	// and
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	legacy := job.Job{ID: job.ID(uuid.New()), Finished: true}
+	older := job.Job{ID: job.ID(uuid.New())}
+	older.Queue(job.Trigger{Repository: "duck8823/duci"}, base)
+	newer := job.Job{ID: job.ID(uuid.New())}
+	newer.Queue(job.Trigger{Repository: "duck8823/other"}, base.Add(time.Minute))
+	for _, j := range []job.Job{legacy, older, newer} {
+		if err := sut.Save(j); err != nil {
+			t.Fatalf("error occurred: %+v", err)
+		}
+		if _, err := sut.AppendLog(j.ID, job.LogLine{Message: "Hello Test"}); err != nil {
+			t.Fatalf("error occurred: %+v", err)
+		}
+	}
+
+	// when
+	got, err := sut.All()
+
+	// then
+	if err != nil {
+		t.Fatalf("error must be nil, but got %+v", err)
+	}
+
+	// and
+	gotIDs := []job.ID{}
+	for _, j := range got {
+		gotIDs = append(gotIDs, j.ID)
+	}
+	want := []job.ID{newer.ID, older.ID, legacy.ID}
+	if !cmp.Equal(gotIDs, want) {
+		t.Errorf("must be equal, but %+v", cmp.Diff(gotIDs, want))
+	}
+}
+
+func TestDataSource_AppendLog(t *testing.T) {
+	// given
+	tmpDir := filepath.Join(os.TempDir(), random.String(16, random.Alphanumeric))
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	sut, err := NewDataSource(tmpDir)
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+
+	// and
+	id := job.ID(uuid.New())
+	var lines []job.LogLine
+	for i := 0; i < 300; i++ {
+		lines = append(lines, job.LogLine{Timestamp: time.Unix(int64(i), 0).UTC(), Message: fmt.Sprintf("line %d", i)})
+	}
+
+	// when
+	for i, line := range lines {
+		seq, err := sut.AppendLog(id, line)
+
+		// then
+		if err != nil {
+			t.Fatalf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if seq != i {
+			t.Fatalf("sequence number must be %d, but got %d", i, seq)
+		}
+	}
+
+	t.Run("job is initialized", func(t *testing.T) {
+		// when
+		got, err := sut.FindBy(id)
+
+		// then
+		if err != nil {
+			t.Fatalf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		want := &job.Job{ID: id}
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("save does not overwrite lines", func(t *testing.T) {
+		// given
+		j := job.Job{ID: id}
+
+		// when
+		if err := sut.Save(j); err != nil {
+			t.Fatalf("error occurred: %+v", err)
+		}
+
+		// then
+		got, err := sut.FindLogs(id, 0, 1)
+		if err != nil {
+			t.Fatalf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if !cmp.Equal(got, lines[:1]) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, lines[:1]))
+		}
+	})
+
+	// where
+	for _, tt := range []struct {
+		name string
+		from int
+		to   int
+		want []job.LogLine
+	}{
+		{
+			name: "with range",
+			from: 255,
+			to:   258,
+			want: lines[255:258],
+		},
+		{
+			name: "with negative end",
+			from: 298,
+			to:   -1,
+			want: lines[298:],
+		},
+		{
+			name: "with negative start",
+			from: -1,
+			to:   2,
+			want: lines[:2],
+		},
+		{
+			name: "with end out of range",
+			from: 299,
+			to:   1000,
+			want: lines[299:],
+		},
+		{
+			name: "with empty range",
+			from: 10,
+			to:   10,
+			want: []job.LogLine{},
+		},
+		{
+			name: "with start out of range",
+			from: 300,
+			to:   -1,
+			want: []job.LogLine{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			got, err := sut.FindLogs(id, tt.from, tt.to)
+
+			// then
+			if err != nil {
+				t.Fatalf("error must be nil, but got %+v", err)
+			}
+
+			// and
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("must be equal, but %+v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestDataSource_ReverseLogs(t *testing.T) {
+	// given
+	tmpDir := filepath.Join(os.TempDir(), random.String(16, random.Alphanumeric))
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	sut, err := NewDataSource(tmpDir)
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+
+	// and
+	id := job.ID(uuid.New())
+	for i := 0; i < 300; i++ {
+		if _, err := sut.AppendLog(id, job.LogLine{Message: fmt.Sprintf("line %d", i)}); err != nil {
+			t.Fatalf("error occurred: %+v", err)
+		}
+	}
+
+	// where
+	for _, tt := range []struct {
+		name  string
+		from  int
+		to    int
+		limit int
+		want  []int
+	}{
+		{
+			name:  "with range",
+			from:  255,
+			to:    258,
+			limit: 10,
+			want:  []int{257, 256, 255},
+		},
+		{
+			name:  "with negative end",
+			from:  0,
+			to:    -1,
+			limit: 3,
+			want:  []int{299, 298, 297},
+		},
+		{
+			name:  "with empty range",
+			from:  10,
+			to:    10,
+			limit: 10,
+			want:  nil,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			var got []int
+			err := sut.ReverseLogs(id, tt.from, tt.to, func(seq int, line job.LogLine) bool {
+				if line.Message != fmt.Sprintf("line %d", seq) {
+					t.Errorf("line of %d must be visited, but got %s", seq, line.Message)
+				}
+				got = append(got, seq)
+				return len(got) < tt.limit
+			})
+
+			// then
+			if err != nil {
+				t.Fatalf("error must be nil, but got %+v", err)
+			}
+
+			// and
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("must be equal, but %+v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestNewDataSource_Migration(t *testing.T) {
+	// given
+	tmpDir := filepath.Join(os.TempDir(), random.String(16, random.Alphanumeric))
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	// and
+	id := job.ID(uuid.New())
+	lines := []job.LogLine{
+		{Timestamp: time.Unix(1, 0).UTC(), Message: "first"},
+		{Timestamp: time.Unix(2, 0).UTC(), Message: "second"},
+	}
+	startedAt := time.Unix(1, 0).UTC()
+	legacy, err := json.Marshal(&LegacyJob{Job: job.Job{ID: id, StartedAt: &startedAt, Finished: true}, Stream: lines})
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+
+	// and
+	other := job.ID(uuid.New())
+	otherLegacy, err := json.Marshal(&LegacyJob{Job: job.Job{ID: other, Finished: true}, Stream: lines})
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+
+	// and
+	db, err := leveldb.OpenFile(tmpDir, nil)
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+	if err := db.Put(id.ToSlice(), legacy, nil); err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+	if err := db.Put(other.ToSlice(), otherLegacy, nil); err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+	_ = db.Close()
+
+	// when
+	sut, err := NewDataSource(tmpDir)
+
+	// then
+	if err != nil {
+		t.Fatalf("error must be nil, but got %+v", err)
+	}
+
+	// and
+	got, err := sut.FindBy(id)
+	if err != nil {
+		t.Fatalf("error must be nil, but got %+v", err)
+	}
+	want := &job.Job{ID: id, QueuedAt: &startedAt, StartedAt: &startedAt, Finished: true}
+	if !cmp.Equal(got, want) {
+		t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+	}
+
+	// and
+	page, err := sut.Search(job.Query{})
+	if err != nil {
+		t.Fatalf("error must be nil, but got %+v", err)
+	}
+	var found []job.ID
+	for _, j := range page.Jobs {
+		if j.QueuedAt == nil {
+			t.Errorf("queued time of job %s must be backfilled", j.ID)
+		}
+		found = append(found, j.ID)
+	}
+	if wantIDs := []job.ID{other, id}; !cmp.Equal(found, wantIDs) {
+		t.Errorf("migrated jobs must be searched, but %+v", cmp.Diff(found, wantIDs))
+	}
+
+	// and
+	gotLines, err := sut.FindLogs(id, 0, -1)
+	if err != nil {
+		t.Fatalf("error must be nil, but got %+v", err)
+	}
+	if !cmp.Equal(gotLines, lines) {
+		t.Errorf("must be equal, but %+v", cmp.Diff(gotLines, lines))
+	}
+
+	// and
+	seq, err := sut.AppendLog(id, job.LogLine{Message: "third"})
+	if err != nil {
+		t.Fatalf("error must be nil, but got %+v", err)
+	}
+	if seq != 2 {
+		t.Errorf("sequence number must be 2, but got %d", seq)
+	}
+}
+
+func TestNewDataSource_Reindex(t *testing.T) {
+	// given
+	tmpDir := filepath.Join(os.TempDir(), random.String(16, random.Alphanumeric))
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	// and
+	j := job.Job{ID: job.ID(uuid.New())}
+	j.Queue(job.Trigger{Repository: "duck8823/duci", SHA: "a"}, time.Unix(1, 0))
+	data, err := j.ToBytes()
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+
+	// and
+	db, err := leveldb.OpenFile(tmpDir, nil)
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+	if err := db.Put(j.ID.ToSlice(), data, nil); err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+	if err := db.Put([]byte("meta/schema"), []byte("2"), nil); err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+	_ = db.Close()
+
+	// when
+	sut, err := NewDataSource(tmpDir)
+
+	// then
+	if err != nil {
+		t.Fatalf("error must be nil, but got %+v", err)
+	}
+
+	// where
+	for _, query := range []job.Query{
+		{State: job.QUEUED},
+		{SHA: "a"},
+	} {
+		// when
+		got, err := sut.Search(query)
+
+		// then
+		if err != nil {
+			t.Fatalf("error must be nil, but got %+v", err)
+		}
+		if len(got.Jobs) != 1 || got.Jobs[0].ID != j.ID {
+			t.Errorf("job must be found by %+v, but got %+v", query, got.Jobs)
+		}
+	}
+}
+
+func TestDataSource_Delete(t *testing.T) {
+	// given
+	tmpDir := filepath.Join(os.TempDir(), random.String(16, random.Alphanumeric))
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	sut, err := NewDataSource(tmpDir)
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+
+	// and
+	removed := job.Job{ID: job.ID(uuid.New())}
+	removed.Queue(job.Trigger{Repository: "duck8823/duci"}, time.Unix(1, 0))
+	kept := job.Job{ID: job.ID(uuid.New())}
+	kept.Queue(job.Trigger{Repository: "duck8823/duci"}, time.Unix(2, 0))
+	for _, j := range []job.Job{removed, kept} {
+		if err := sut.Save(j); err != nil {
+			t.Fatalf("error occurred: %+v", err)
+		}
+		for i := 0; i < 100; i++ {
+			if _, err := sut.AppendLog(j.ID, job.LogLine{Message: strings.Repeat(random.String(128), 8)}); err != nil {
+				t.Fatalf("error occurred: %+v", err)
+			}
+		}
+	}
+
+	// and
+	if err := sut.Compact(); err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+	before, err := sut.Size()
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+	size, err := sut.SizeOf(removed.ID)
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+	if size <= 0 || size > before {
+		t.Errorf("size of job must be between 0 and %d, but got %d", before, size)
+	}
+
+	// when
+	err = sut.Delete(removed.ID)
+
+	// then
+	if err != nil {
+		t.Errorf("error must be nil, but got %+v", err)
+	}
+
+	// and
+	if _, err := sut.FindBy(removed.ID); err != job.ErrNotFound {
+		t.Errorf("error must be %+v, but got %+v", job.ErrNotFound, err)
+	}
+
+	// and
+	lines, err := sut.FindLogs(removed.ID, 0, -1)
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+	if len(lines) != 0 {
+		t.Errorf("lines must be empty, but got %d lines", len(lines))
+	}
+
+	// and
+	page, err := sut.Search(job.Query{Repository: "duck8823/duci"})
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+	if len(page.Jobs) != 1 || page.Jobs[0].ID != kept.ID {
+		t.Errorf("must be only kept job, but got %+v", page.Jobs)
+	}
+
+	// and
+	if err := sut.Compact(); err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+	after, err := sut.Size()
+	if err != nil {
+		t.Fatalf("error occurred: %+v", err)
+	}
+	if after >= before {
+		t.Errorf("size must be reduced from %d, but got %d", before, after)
+	}
 }
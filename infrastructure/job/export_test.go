@@ -2,6 +2,8 @@ package job
 
 type DataSource = dataSource
 
+type LegacyJob = legacyJob
+
 func (d *DataSource) SetDB(db LevelDB) (cancel func()) {
 	tmp := d.db
 	d.db = db
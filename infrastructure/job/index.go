@@ -0,0 +1,114 @@
+package job
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	timeIndexPrefix  = "index/time/"
+	repoIndexPrefix  = "index/repo/"
+	shaIndexPrefix   = "index/sha/"
+	stateIndexPrefix = "index/state/"
+)
+
+// indexKeys returns secondary index keys of the job.
+// Jobs not queued yet are not indexed.
+func indexKeys(j job.Job) [][]byte {
+	if j.QueuedAt == nil {
+		return nil
+	}
+	suffix := indexSuffix(*j.QueuedAt, j.ID)
+	keys := [][]byte{[]byte(timeIndexPrefix + suffix)}
+	if len(j.State) > 0 {
+		keys = append(keys, []byte(stateIndexPrefix+j.State.String()+"/"+suffix))
+	}
+	if j.Trigger == nil {
+		return keys
+	}
+	if len(j.Trigger.Repository) > 0 {
+		keys = append(keys, []byte(repoIndexPrefix+j.Trigger.Repository+"/"+suffix))
+	}
+	if len(j.Trigger.SHA) > 0 {
+		keys = append(keys, []byte(shaIndexPrefix+j.Trigger.SHA+"/"+suffix))
+	}
+	return keys
+}
+
+// staleKeys returns index keys of the stored job which are no longer keys of the saved one, such as the one of previous state
+func staleKeys(stored job.Job, saved job.Job) [][]byte {
+	current := make(map[string]bool)
+	for _, key := range indexKeys(saved) {
+		current[string(key)] = true
+	}
+	var keys [][]byte
+	for _, key := range indexKeys(stored) {
+		if !current[string(key)] {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// indexPrefix returns a prefix of index keys to scan for the query.
+// The most selective index is used, and the other conditions are matched with jobs.
+func indexPrefix(q job.Query) string {
+	switch {
+	case len(q.SHA) > 0:
+		return shaIndexPrefix + q.SHA + "/"
+	case len(q.Repository) > 0:
+		return repoIndexPrefix + q.Repository + "/"
+	case len(q.State) > 0:
+		return stateIndexPrefix + q.State.String() + "/"
+	default:
+		return timeIndexPrefix
+	}
+}
+
+// indexSuffix returns a part of key sorted in descending order of time.
+func indexSuffix(t time.Time, id job.ID) string {
+	return fmt.Sprintf("%s/%s", invertedTime(t), uuid.UUID(id).String())
+}
+
+func invertedTime(t time.Time) string {
+	return fmt.Sprintf("%016x", uint64(math.MaxInt64-t.UnixNano()))
+}
+
+// parseIndexSuffix returns queued time and ID of job from a part of index key
+func parseIndexSuffix(suffix string) (time.Time, job.ID, error) {
+	parts := strings.SplitN(suffix, "/", 2)
+	if len(parts) != 2 {
+		return time.Time{}, job.ID{}, errors.Errorf("invalid index: %s", suffix)
+	}
+	inv, err := strconv.ParseUint(parts[0], 16, 64)
+	if err != nil {
+		return time.Time{}, job.ID{}, errors.WithStack(err)
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return time.Time{}, job.ID{}, errors.WithStack(err)
+	}
+	return time.Unix(0, math.MaxInt64-int64(inv)), job.ID(id), nil
+}
+
+func encodeCursor(suffix string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(suffix))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", errors.Wrap(job.ErrInvalidCursor, err.Error())
+	}
+	if _, _, err := parseIndexSuffix(string(data)); err != nil {
+		return "", errors.Wrap(job.ErrInvalidCursor, err.Error())
+	}
+	return string(data), nil
+}
@@ -0,0 +1,31 @@
+package job
+
+import (
+	"fmt"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+const logPrefix = "log/"
+
+// logKey returns a key of log line sorted in order of sequence number
+func logKey(id job.ID, seq int) []byte {
+	return []byte(fmt.Sprintf("%s%016x", logKeyPrefix(id), seq))
+}
+
+// logKeyPrefix returns a prefix of keys of all log lines of the job
+func logKeyPrefix(id job.ID) string {
+	return logPrefix + id.String() + "/"
+}
+
+// parseLogKey returns sequence number of log line from the key
+func parseLogKey(key []byte) (int, error) {
+	parts := strings.Split(string(key), "/")
+	seq, err := strconv.ParseInt(parts[len(parts)-1], 16, 64)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return int(seq), nil
+}
@@ -0,0 +1,174 @@
+package job
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/syndtr/goleveldb/leveldb"
+	"time"
+)
+
+const (
+	schemaKey     = "meta/schema"
+	schemaVersion = "4"
+)
+
+// legacyJob is a job stored by older versions, which has log lines inside of it
+type legacyJob struct {
+	job.Job
+	Stream []job.LogLine `json:"stream"`
+}
+
+// migrate upgrades data stored by older versions to the current schema.
+// It does nothing if the data source has already migrated.
+func migrate(db LevelDB) error {
+	version, err := db.Get([]byte(schemaKey), nil)
+	if err == nil && string(version) == schemaVersion {
+		return nil
+	} else if err != nil && err != leveldb.ErrNotFound {
+		return errors.WithStack(err)
+	}
+
+	// versions are single digits, so that they can be compared as strings
+	if string(version) < "2" {
+		if err := moveLogs(db); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if string(version) < "3" {
+		if err := reindex(db); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if string(version) < "4" {
+		if err := backfill(db, time.Now()); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	if err := db.Put([]byte(schemaKey), []byte(schemaVersion), nil); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// moveLogs moves log lines stored inside of job to keys of each line.
+func moveLogs(db LevelDB) error {
+	iter := db.NewIterator(nil, nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		id, err := uuid.Parse(string(iter.Key()))
+		if err != nil {
+			continue
+		}
+
+		j := &legacyJob{}
+		if err := json.NewDecoder(bytes.NewReader(iter.Value())).Decode(j); err != nil {
+			logrus.Warnf("skip migration of job %s: %+v", id, err)
+			continue
+		}
+		if len(j.Stream) == 0 {
+			continue
+		}
+
+		batch := new(leveldb.Batch)
+		for seq, line := range j.Stream {
+			data, err := json.Marshal(line)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			batch.Put(logKey(job.ID(id), seq), data)
+		}
+		j.ID = job.ID(id)
+		data, err := j.Job.ToBytes()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		batch.Put(j.ID.ToSlice(), data)
+
+		if err := db.Write(batch, nil); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return errors.WithStack(iter.Error())
+}
+
+// reindex writes index keys of all jobs, such as of state and sha added after they were stored.
+func reindex(db LevelDB) error {
+	iter := db.NewIterator(nil, nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		id, err := uuid.Parse(string(iter.Key()))
+		if err != nil {
+			continue
+		}
+
+		j := job.Job{}
+		if err := json.Unmarshal(iter.Value(), &j); err != nil {
+			logrus.Warnf("skip indexing of job %s: %+v", id, err)
+			continue
+		}
+		j.ID = job.ID(id)
+
+		batch := new(leveldb.Batch)
+		for _, key := range indexKeys(j) {
+			batch.Put(key, []byte{})
+		}
+		if err := db.Write(batch, nil); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return errors.WithStack(iter.Error())
+}
+
+// backfill sets queued time to jobs stored without it, and writes their index keys so that they can be searched.
+// The queued time is the earliest known time of the job, or the time of migration.
+func backfill(db LevelDB, now time.Time) error {
+	iter := db.NewIterator(nil, nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		id, err := uuid.Parse(string(iter.Key()))
+		if err != nil {
+			continue
+		}
+
+		j := job.Job{}
+		if err := json.Unmarshal(iter.Value(), &j); err != nil {
+			logrus.Warnf("skip backfill of job %s: %+v", id, err)
+			continue
+		}
+		if j.QueuedAt != nil {
+			continue
+		}
+		j.ID = job.ID(id)
+		switch {
+		case j.StartedAt != nil:
+			j.QueuedAt = j.StartedAt
+		case j.FinishedAt != nil:
+			j.QueuedAt = j.FinishedAt
+		default:
+			queuedAt := now
+			j.QueuedAt = &queuedAt
+		}
+
+		data, err := j.ToBytes()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		batch := new(leveldb.Batch)
+		batch.Put(j.ID.ToSlice(), data)
+		for _, key := range indexKeys(j) {
+			batch.Put(key, []byte{})
+		}
+		if err := db.Write(batch, nil); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return errors.WithStack(iter.Error())
+}
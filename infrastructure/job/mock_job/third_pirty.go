@@ -6,7 +6,10 @@ package mock_job
 
 import (
 	gomock "github.com/golang/mock/gomock"
+	leveldb "github.com/syndtr/goleveldb/leveldb"
+	iterator "github.com/syndtr/goleveldb/leveldb/iterator"
 	opt "github.com/syndtr/goleveldb/leveldb/opt"
+	util "github.com/syndtr/goleveldb/leveldb/util"
 	reflect "reflect"
 )
 
@@ -57,3 +60,52 @@ func (m *MockLevelDB) Put(key, value []byte, wo *opt.WriteOptions) error {
 func (mr *MockLevelDBMockRecorder) Put(key, value, wo interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockLevelDB)(nil).Put), key, value, wo)
 }
+
+// Write mocks base method
+func (m *MockLevelDB) Write(batch *leveldb.Batch, wo *opt.WriteOptions) error {
+	ret := m.ctrl.Call(m, "Write", batch, wo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Write indicates an expected call of Write
+func (mr *MockLevelDBMockRecorder) Write(batch, wo interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockLevelDB)(nil).Write), batch, wo)
+}
+
+// NewIterator mocks base method
+func (m *MockLevelDB) NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator {
+	ret := m.ctrl.Call(m, "NewIterator", slice, ro)
+	ret0, _ := ret[0].(iterator.Iterator)
+	return ret0
+}
+
+// NewIterator indicates an expected call of NewIterator
+func (mr *MockLevelDBMockRecorder) NewIterator(slice, ro interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewIterator", reflect.TypeOf((*MockLevelDB)(nil).NewIterator), slice, ro)
+}
+
+// SizeOf mocks base method
+func (m *MockLevelDB) SizeOf(ranges []util.Range) (leveldb.Sizes, error) {
+	ret := m.ctrl.Call(m, "SizeOf", ranges)
+	ret0, _ := ret[0].(leveldb.Sizes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SizeOf indicates an expected call of SizeOf
+func (mr *MockLevelDBMockRecorder) SizeOf(ranges interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SizeOf", reflect.TypeOf((*MockLevelDB)(nil).SizeOf), ranges)
+}
+
+// CompactRange mocks base method
+func (m *MockLevelDB) CompactRange(r util.Range) error {
+	ret := m.ctrl.Call(m, "CompactRange", r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompactRange indicates an expected call of CompactRange
+func (mr *MockLevelDBMockRecorder) CompactRange(r interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactRange", reflect.TypeOf((*MockLevelDB)(nil).CompactRange), r)
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/duck8823/duci/application"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+var cancelCmd = createCmd("cancel <id>", "Cancel a queued or running job", cancelJob)
+
+func init() {
+	cancelCmd.Args = cobra.ExactArgs(1)
+	cancelCmd.Flags().StringP("server", "s", "", "url of duci server (default: http://localhost with port in configuration)")
+}
+
+func cancelJob(cmd *cobra.Command, args []string) {
+	readConfiguration(cmd)
+
+	id, err := uuid.Parse(args[0])
+	if err != nil {
+		logrus.Fatalf("Invalid job id: %s", args[0])
+	}
+
+	url := fmt.Sprintf("%s/jobs/%s/cancel", serverURL(cmd), id)
+	resp, err := postAsAdmin(url)
+	if err != nil {
+		logrus.Fatalf("Failed to request.\n%+v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		body, _ := ioutil.ReadAll(resp.Body)
+		logrus.Fatalf("Failed to cancel %s: %s", id, strings.TrimSpace(string(body)))
+	}
+	logrus.Infof("cancelled %s", id)
+}
+
+// serverURL returns url of duci server specified by flag or configuration
+func serverURL(cmd *cobra.Command) string {
+	if server := cmd.Flag("server").Value.String(); len(server) > 0 {
+		return strings.TrimSuffix(server, "/")
+	}
+	return fmt.Sprintf("http://localhost%s", application.Config.Addr())
+}
+
+// postAsAdmin requests to duci server with the admin token in configuration.
+// It fails without requesting if the admin token is not configured, because the server forbids such requests.
+func postAsAdmin(url string) (*http.Response, error) {
+	token := application.Config.Server.AdminToken.String()
+	if len(token) == 0 {
+		return nil, errors.New("admin token is not configured. Set server.admin_token or DUCI_ADMIN_TOKEN")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	return http.DefaultClient.Do(req)
+}
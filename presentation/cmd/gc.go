@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+var gcCmd = createCmd("gc", "Remove jobs exceeding the retention", collectGarbage)
+
+func init() {
+	gcCmd.Flags().Bool("dry-run", false, "show jobs to be removed without removing them")
+	gcCmd.Flags().StringP("server", "s", "", "url of duci server (default: http://localhost with port in configuration)")
+}
+
+func collectGarbage(cmd *cobra.Command, _ []string) {
+	readConfiguration(cmd)
+
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		logrus.Fatalf("Invalid flag.\n%+v", err)
+	}
+
+	url := fmt.Sprintf("%s/gc?dry_run=%t", serverURL(cmd), dryRun)
+	resp, err := postAsAdmin(url)
+	if err != nil {
+		logrus.Fatalf("Failed to request.\n%+v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		logrus.Fatalf("Failed to collect garbage: %s", strings.TrimSpace(string(body)))
+	}
+
+	result := &struct {
+		Jobs []struct {
+			ID      string `json:"id"`
+			Trigger *struct {
+				Repository string `json:"repository"`
+			} `json:"trigger"`
+			Size   int64  `json:"size"`
+			Reason string `json:"reason"`
+		} `json:"jobs"`
+		Size int64 `json:"size"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		logrus.Fatalf("Failed to read response.\n%+v", err)
+	}
+
+	verb := "removed"
+	if dryRun {
+		verb = "would remove"
+	}
+	for _, j := range result.Jobs {
+		var repo string
+		if j.Trigger != nil {
+			repo = j.Trigger.Repository
+		}
+		logrus.Infof("%s %s %s (%s, %d bytes)", verb, j.ID, repo, j.Reason, j.Size)
+	}
+	logrus.Infof("%s %d jobs, %d bytes", verb, len(result.Jobs), result.Size)
+}
@@ -10,7 +10,7 @@ import (
 var rootCmd = &cobra.Command{Use: "duci"}
 
 func init() {
-	rootCmd.AddCommand(serverCmd, runCmd, configCmd, healthCmd, versionCmd, updateCmd)
+	rootCmd.AddCommand(serverCmd, runCmd, configCmd, healthCmd, versionCmd, updateCmd, cancelCmd, gcCmd)
 }
 
 // Execute command
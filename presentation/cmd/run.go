@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"github.com/duck8823/duci/application"
 	"github.com/duck8823/duci/domain/model/docker"
 	"github.com/duck8823/duci/domain/model/job"
 	"github.com/duck8823/duci/domain/model/runner"
@@ -23,10 +24,13 @@ func runLocal(cmd *cobra.Command, args []string) {
 
 	runner := runner.DefaultDockerRunnerBuilder().
 		LogFunc(func(_ context.Context, log job.Log) {
+			log = job.NewMasker(application.Config.Secrets()...).Log(log)
 			for line, err := log.ReadLine(); err == nil; line, err = log.ReadLine() {
 				logrus.Info(line.Message)
 			}
 		}).
+		Resources(application.Config.Job.Resources).
+		Limits(application.Config.Job.MaxResources).
 		Build()
 
 	workspace := filepath.Join(os.TempDir(), random.String(16))
@@ -1,14 +1,21 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/application/duci"
 	"github.com/duck8823/duci/application/semaphore"
+	"github.com/duck8823/duci/application/service/executor"
 	"github.com/duck8823/duci/presentation/router"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 )
 
 var (
@@ -46,8 +53,63 @@ func runServer(cmd *cobra.Command, _ []string) {
 	for _, l := range strings.Split(logo, "\n") {
 		logrus.Info(l)
 	}
-	if err := http.ListenAndServe(application.Config.Addr(), rtr); err != nil {
+
+	if len(application.Config.Server.AdminToken.String()) == 0 {
+		logrus.Warn("Admin token is not configured. Cancel, rerun and gc are forbidden.")
+	}
+
+	if err := duci.Recover(context.Background()); err != nil {
+		logrus.Errorf("Failed to recover jobs.\n%+v", err)
+	}
+
+	// ctx is done on shutdown, to stop the janitor and streams of log which would block closing connections
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+
+	go application.Janitor(ctx)
+
+	srv := &http.Server{
+		Addr:    application.Config.Addr(),
+		Handler: rtr,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+	srv.RegisterOnShutdown(stop)
+	stopped := make(chan struct{})
+	go shutdownOnSignal(srv, stopped)
+
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
 		logrus.Fatal(fmt.Sprintf("Failed to run server.\n%+v", err))
 		return
 	}
+	<-stopped
+}
+
+// shutdownOnSignal stops accepting requests and waits for running jobs when the process receives SIGINT or SIGTERM
+func shutdownOnSignal(srv *http.Server, stopped chan<- struct{}) {
+	defer close(stopped)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	logrus.Infof("Received %s, shutting down.", <-sig)
+	signal.Stop(sig)
+
+	ctx, cancel := context.WithTimeout(context.Background(), application.Config.GracePeriod())
+	defer cancel()
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		if err := srv.Shutdown(ctx); err != nil {
+			logrus.Warnf("Failed to close connections gracefully: %+v", err)
+			_ = srv.Close()
+		}
+	}()
+
+	if err := executor.Shutdown(ctx); err != nil {
+		logrus.Warnf("Running jobs are cancelled after grace period: %+v", err)
+	}
+	<-closed
+	logrus.Info("Server stopped.")
 }
@@ -0,0 +1,439 @@
+package dashboard
+
+// indexHTML is the page of the dashboard. It shows the job list or a job by the path.
+// The body is formatted with whether the admin token is configured, to show buttons requiring it.
+const indexHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>duci</title>
+<link rel="stylesheet" href="/ui/style.css">
+</head>
+<body data-admin="%t">
+<header><a href="/ui/">duci</a></header>
+<main id="app"></main>
+<script src="/ui/app.js"></script>
+</body>
+</html>
+`
+
+// appJS renders the job list and a job with its live log using the API of duci.
+const appJS = `(function () {
+  'use strict';
+
+  var app = document.getElementById('app');
+  var admin = document.body.getAttribute('data-admin') === 'true';
+  var states = ['queued', 'running', 'success', 'failure', 'error', 'timeout', 'cancelled', 'skipped'];
+  var colors = ['black', 'red', 'green', 'yellow', 'blue', 'magenta', 'cyan', 'white'];
+
+  function el(tag, attrs, children) {
+    var node = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (key) {
+      if (key === 'text') {
+        node.textContent = attrs[key];
+      } else if (key.indexOf('on') === 0) {
+        node.addEventListener(key.substring(2), attrs[key]);
+      } else if (attrs[key] !== undefined && attrs[key] !== null) {
+        node.setAttribute(key, attrs[key]);
+      }
+    });
+    (children || []).forEach(function (child) {
+      node.appendChild(typeof child === 'string' ? document.createTextNode(child) : child);
+    });
+    return node;
+  }
+
+  // request calls the API. Requests except GET send the admin token, which is asked and kept in the browser.
+  function request(method, path) {
+    var headers = {Accept: 'application/json'};
+    if (method !== 'GET') {
+      var token = localStorage.getItem('duci.adminToken') || prompt('Admin token') || '';
+      headers.Authorization = 'Bearer ' + token;
+    }
+    return fetch(path, {method: method, headers: headers}).then(function (res) {
+      if (res.status === 401) {
+        localStorage.removeItem('duci.adminToken');
+      } else if (method !== 'GET' && res.ok) {
+        localStorage.setItem('duci.adminToken', headers.Authorization.substring('Bearer '.length));
+      }
+      return res.text().then(function (text) {
+        if (!res.ok) {
+          throw new Error(text || res.statusText);
+        }
+        return text ? JSON.parse(text) : null;
+      });
+    });
+  }
+
+  function formatTime(value) {
+    return value ? new Date(value).toLocaleString() : '';
+  }
+
+  function duration(job) {
+    if (!job.startedAt) {
+      return '';
+    }
+    var end = job.finishedAt ? new Date(job.finishedAt) : new Date();
+    var sec = Math.max(0, Math.round((end - new Date(job.startedAt)) / 1000));
+    return sec >= 60 ? Math.floor(sec / 60) + 'min ' + (sec % 60) + 'sec' : sec + 'sec';
+  }
+
+  function shortRef(ref) {
+    return (ref || '').replace(/^refs\/heads\//, '');
+  }
+
+  function badge(state) {
+    return el('span', {'class': 'state state-' + (state || 'unknown'), text: state || 'unknown'});
+  }
+
+  function showError(err) {
+    app.insertBefore(el('p', {'class': 'error', text: err.message}), app.firstChild);
+  }
+
+  // list shows jobs filtered by the query of the page, and refreshes the first page while it is shown.
+  function list() {
+    var params = new URLSearchParams(location.search);
+    var query = new URLSearchParams();
+    if (params.get('repository')) {
+      query.set('repository', params.get('repository'));
+    }
+    if (params.get('branch')) {
+      var branch = params.get('branch');
+      query.set('ref', branch.indexOf('refs/') === 0 ? branch : 'refs/heads/' + branch);
+    }
+    if (params.get('state')) {
+      query.set('state', params.get('state'));
+    }
+
+    var stateSelect = el('select', {name: 'state'}, [el('option', {value: '', text: 'any state'})].concat(states.map(function (state) {
+      return el('option', {value: state, text: state});
+    })));
+    stateSelect.value = params.get('state') || '';
+
+    var body = el('tbody');
+    var more = el('button', {type: 'button', 'class': 'more', text: 'Load more'});
+    var next = '';
+    var paged = false;
+
+    app.appendChild(el('form', {'class': 'filter', method: 'get', action: '/ui/'}, [
+      el('input', {name: 'repository', placeholder: 'owner/repository', value: params.get('repository') || ''}),
+      el('input', {name: 'branch', placeholder: 'branch', value: params.get('branch') || ''}),
+      stateSelect,
+      el('button', {type: 'submit', text: 'Filter'})
+    ]));
+    app.appendChild(el('table', {'class': 'jobs'}, [
+      el('thead', {}, [el('tr', {}, ['State', 'Repository', 'Branch', 'Commit', 'Task', 'Queued', 'Duration'].map(function (name) {
+        return el('th', {text: name});
+      }))]),
+      body
+    ]));
+    app.appendChild(more);
+
+    function row(job) {
+      var trigger = job.trigger || {};
+      var link = '/ui/jobs/' + job.id;
+      return el('tr', {}, [
+        el('td', {}, [badge(job.state)]),
+        el('td', {}, [el('a', {href: link, text: trigger.repository || job.id})]),
+        el('td', {text: shortRef(trigger.ref)}),
+        el('td', {'class': 'sha', text: (trigger.sha || '').substring(0, 7)}),
+        el('td', {text: trigger.taskName || ''}),
+        el('td', {text: formatTime(job.queuedAt)}),
+        el('td', {text: duration(job)})
+      ]);
+    }
+
+    function load(cursor) {
+      var q = new URLSearchParams(query);
+      if (cursor) {
+        q.set('cursor', cursor);
+      }
+      return request('GET', '/jobs?' + q.toString()).then(function (page) {
+        if (!cursor) {
+          body.textContent = '';
+        }
+        page.jobs.forEach(function (job) {
+          body.appendChild(row(job));
+        });
+        if (!page.jobs.length && !cursor) {
+          body.appendChild(el('tr', {}, [el('td', {colspan: 7, 'class': 'empty', text: 'No jobs'})]));
+        }
+        next = page.next || '';
+        more.hidden = !next;
+      });
+    }
+
+    more.addEventListener('click', function () {
+      paged = true;
+      load(next).catch(showError);
+    });
+    load('').catch(showError);
+    setInterval(function () {
+      if (!paged) {
+        load('').catch(showError);
+      }
+    }, 10000);
+  }
+
+  // Ansi renders ANSI colored text into elements. The style is carried over lines as a terminal does.
+  function Ansi() {
+    this.style = {};
+  }
+
+  Ansi.prototype.color = function (codes, i) {
+    if (codes[i + 1] === '5' && i + 2 < codes.length) {
+      var n = parseInt(codes[i + 2], 10);
+      var value;
+      if (n < 8) {
+        value = colors[n];
+      } else if (n < 16) {
+        value = 'bright-' + colors[n - 8];
+      } else if (n < 232) {
+        var level = function (v) {
+          return v ? 55 + v * 40 : 0;
+        };
+        n -= 16;
+        value = 'rgb(' + level(Math.floor(n / 36)) + ',' + level(Math.floor(n / 6) % 6) + ',' + level(n % 6) + ')';
+      } else {
+        var gray = 8 + (n - 232) * 10;
+        value = 'rgb(' + gray + ',' + gray + ',' + gray + ')';
+      }
+      return {value: value, next: i + 2};
+    }
+    if (codes[i + 1] === '2' && i + 4 < codes.length) {
+      return {value: 'rgb(' + codes.slice(i + 2, i + 5).join(',') + ')', next: i + 4};
+    }
+    return {value: '', next: codes.length};
+  };
+
+  Ansi.prototype.apply = function (params) {
+    var codes = params ? params.split(';') : ['0'];
+    for (var i = 0; i < codes.length; i++) {
+      var code = parseInt(codes[i], 10);
+      var extended;
+      if (code === 0) {
+        this.style = {};
+      } else if (code === 1) {
+        this.style.bold = true;
+      } else if (code === 2) {
+        this.style.faint = true;
+      } else if (code === 3) {
+        this.style.italic = true;
+      } else if (code === 4) {
+        this.style.underline = true;
+      } else if (code === 22) {
+        this.style.bold = this.style.faint = false;
+      } else if (code === 23) {
+        this.style.italic = false;
+      } else if (code === 24) {
+        this.style.underline = false;
+      } else if (code >= 30 && code <= 37) {
+        this.style.fg = colors[code - 30];
+      } else if (code === 38 || code === 48) {
+        extended = this.color(codes, i);
+        this.style[code === 38 ? 'fg' : 'bg'] = extended.value;
+        i = extended.next;
+      } else if (code === 39) {
+        this.style.fg = '';
+      } else if (code >= 40 && code <= 47) {
+        this.style.bg = colors[code - 40];
+      } else if (code === 49) {
+        this.style.bg = '';
+      } else if (code >= 90 && code <= 97) {
+        this.style.fg = 'bright-' + colors[code - 90];
+      } else if (code >= 100 && code <= 107) {
+        this.style.bg = 'bright-' + colors[code - 100];
+      }
+    }
+  };
+
+  Ansi.prototype.span = function (text) {
+    var s = this.style;
+    var classes = ['bold', 'faint', 'italic', 'underline'].filter(function (name) {
+      return s[name];
+    });
+    var node = el('span', {}, [text]);
+    [['fg', 'color'], ['bg', 'backgroundColor']].forEach(function (pair) {
+      var value = s[pair[0]];
+      if (!value) {
+        return;
+      }
+      if (value.indexOf('rgb(') === 0) {
+        node.style[pair[1]] = value;
+      } else {
+        classes.push(pair[0] + '-' + value);
+      }
+    });
+    if (classes.length) {
+      node.className = classes.join(' ');
+    }
+    return node;
+  };
+
+  Ansi.prototype.render = function (line) {
+    var fragment = document.createDocumentFragment();
+    var pattern = /\x1b\[([0-9;?]*)([A-Za-z])/g;
+    var pos = 0;
+    var match;
+    while ((match = pattern.exec(line)) !== null) {
+      if (match.index > pos) {
+        fragment.appendChild(this.span(line.substring(pos, match.index)));
+      }
+      pos = pattern.lastIndex;
+      if (match[2] === 'm') {
+        this.apply(match[1]);
+      }
+    }
+    if (pos < line.length) {
+      fragment.appendChild(this.span(line.substring(pos)));
+    }
+    return fragment;
+  };
+
+  // show shows metadata of the job and follows its log until the job finishes.
+  function show(id) {
+    var meta = el('dl', {'class': 'meta'});
+    var actions = el('div', {'class': 'actions'});
+    var log = el('pre', {'class': 'log'});
+    var ansi = new Ansi();
+    var follow = true;
+
+    app.appendChild(el('h1', {}, ['Job ', el('span', {'class': 'sha', text: id})]));
+    app.appendChild(actions);
+    app.appendChild(meta);
+    app.appendChild(log);
+
+    window.addEventListener('scroll', function () {
+      follow = window.innerHeight + window.scrollY >= document.body.scrollHeight - 40;
+    });
+
+    function field(name, value) {
+      if (value === undefined || value === null || value === '') {
+        return;
+      }
+      meta.appendChild(el('dt', {text: name}));
+      meta.appendChild(el('dd', {}, [value]));
+    }
+
+    function render(job) {
+      var trigger = job.trigger || {};
+      meta.textContent = '';
+      field('State', badge(job.state));
+      field('Repository', trigger.repository);
+      field('Branch', shortRef(trigger.ref));
+      field('Commit', trigger.sha);
+      field('Event', trigger.event);
+      field('Task', trigger.taskName);
+      field('Command', (trigger.command || []).join(' '));
+      field('Rerun of', trigger.rerunOf ? el('a', {href: '/ui/jobs/' + trigger.rerunOf, text: trigger.rerunOf}) : '');
+      field('Queued', formatTime(job.queuedAt));
+      field('Started', formatTime(job.startedAt));
+      field('Finished', formatTime(job.finishedAt));
+      field('Duration', duration(job));
+      field('Exit code', job.exitCode === undefined ? '' : String(job.exitCode));
+      field('Log', job.truncated ? 'truncated' : '');
+
+      actions.textContent = '';
+      // cancel and rerun are forbidden without the admin token, so their buttons are not shown
+      if (admin && !job.finished) {
+        actions.appendChild(el('button', {type: 'button', text: 'Cancel', onclick: function () {
+          request('POST', '/jobs/' + id + '/cancel').then(refresh).catch(showError);
+        }}));
+      }
+      if (admin && job.finished) {
+        actions.appendChild(el('button', {type: 'button', text: 'Rerun', onclick: function () {
+          request('POST', '/jobs/' + id + '/rerun').then(function (rerun) {
+            location.href = '/ui/jobs/' + rerun.id;
+          }).catch(showError);
+        }}));
+      }
+      if (job.finished) {
+        actions.appendChild(el('a', {href: '/logs/' + id + '?format=gzip', text: 'Download log'}));
+      }
+      actions.appendChild(el('a', {href: '/logs/' + id + '?format=text', text: 'Raw log'}));
+      return job;
+    }
+
+    function refresh() {
+      return request('GET', '/jobs/' + id).then(render);
+    }
+
+    refresh().then(function (job) {
+      var timer = job.finished ? null : setInterval(function () {
+        refresh().catch(showError);
+      }, 5000);
+
+      var source = new EventSource('/logs/' + id + '/events');
+      source.addEventListener('log', function (event) {
+        var line = JSON.parse(event.data);
+        var node = el('div', {'class': 'line ' + (line.stream || '')});
+        node.appendChild(ansi.render(line.message));
+        log.appendChild(node);
+        if (follow) {
+          window.scrollTo(0, document.body.scrollHeight);
+        }
+      });
+      source.addEventListener('finished', function () {
+        source.close();
+        clearInterval(timer);
+        refresh().catch(showError);
+      });
+      source.addEventListener('error', function (event) {
+        if (event.data) {
+          log.appendChild(el('div', {'class': 'line system', text: event.data}));
+        }
+      });
+    }).catch(showError);
+  }
+
+  var matched = location.pathname.match(/^\/ui\/jobs\/([0-9a-fA-F-]+)\/?$/);
+  if (matched) {
+    show(matched[1]);
+  } else {
+    list();
+  }
+})();
+`
+
+// styleCSS is the style of the dashboard including classes of ANSI colors.
+const styleCSS = `body { margin: 0; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; color: #24292e; }
+header { padding: 0.8em 1.5em; background-color: #24292e; }
+header a { color: #ffffff; font-weight: bold; font-size: 1.2em; text-decoration: none; }
+main { padding: 1em 1.5em; }
+a { color: #0366d6; }
+h1 { font-size: 1.3em; }
+button { margin-right: 0.5em; padding: 0.3em 0.9em; cursor: pointer; }
+.error { padding: 0.5em; color: #86181d; background-color: #ffdce0; }
+.filter input, .filter select { margin-right: 0.5em; padding: 0.3em; }
+.jobs { width: 100%; margin: 1em 0; border-collapse: collapse; }
+.jobs th, .jobs td { padding: 0.4em 0.6em; border-bottom: 1px solid #e1e4e8; text-align: left; white-space: nowrap; }
+.jobs .empty { text-align: center; color: #6a737d; }
+.sha { font-family: monospace; }
+.state { padding: 0.1em 0.5em; border-radius: 0.8em; color: #ffffff; background-color: #6a737d; font-size: 0.85em; }
+.state-queued { background-color: #b08800; }
+.state-running { background-color: #0366d6; }
+.state-success { background-color: #28a745; }
+.state-failure, .state-timeout { background-color: #d73a49; }
+.state-error { background-color: #6f42c1; }
+.state-cancelled { background-color: #586069; }
+.state-skipped { background-color: #959da5; }
+.actions { margin: 0.5em 0; }
+.actions a { margin-right: 1em; }
+.meta { display: grid; grid-template-columns: max-content auto; gap: 0.3em 1em; }
+.meta dt { font-weight: bold; }
+.meta dd { margin: 0; }
+.log { margin: 1em 0; padding: 1em; background-color: #1e1e1e; color: #d4d4d4; white-space: pre-wrap; word-break: break-all; font-family: monospace; }
+.log .line { min-height: 1.2em; }
+.log .stderr { color: #f48771; }
+.log .system { color: #9cdcfe; }
+.bold { font-weight: bold; } .faint { opacity: 0.6; } .italic { font-style: italic; } .underline { text-decoration: underline; }
+.fg-black { color: #000000; } .fg-red { color: #cd3131; } .fg-green { color: #0dbc79; } .fg-yellow { color: #e5e510; }
+.fg-blue { color: #2472c8; } .fg-magenta { color: #bc3fbc; } .fg-cyan { color: #11a8cd; } .fg-white { color: #e5e5e5; }
+.fg-bright-black { color: #666666; } .fg-bright-red { color: #f14c4c; } .fg-bright-green { color: #23d18b; } .fg-bright-yellow { color: #f5f543; }
+.fg-bright-blue { color: #3b8eea; } .fg-bright-magenta { color: #d670d6; } .fg-bright-cyan { color: #29b8db; } .fg-bright-white { color: #ffffff; }
+.bg-black { background-color: #000000; } .bg-red { background-color: #cd3131; } .bg-green { background-color: #0dbc79; } .bg-yellow { background-color: #e5e510; }
+.bg-blue { background-color: #2472c8; } .bg-magenta { background-color: #bc3fbc; } .bg-cyan { background-color: #11a8cd; } .bg-white { background-color: #e5e5e5; }
+.bg-bright-black { background-color: #666666; } .bg-bright-red { background-color: #f14c4c; } .bg-bright-green { background-color: #23d18b; } .bg-bright-yellow { background-color: #f5f543; }
+.bg-bright-blue { background-color: #3b8eea; } .bg-bright-magenta { background-color: #d670d6; } .bg-bright-cyan { background-color: #29b8db; } .bg-bright-white { background-color: #ffffff; }
+`
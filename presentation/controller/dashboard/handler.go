@@ -0,0 +1,43 @@
+package dashboard
+
+import (
+	"fmt"
+	"github.com/duck8823/duci/application"
+	"net/http"
+	"strings"
+)
+
+// Prefix is the path the dashboard is served under.
+const Prefix = "/ui/"
+
+type asset struct {
+	contentType string
+	body        string
+}
+
+// assets are files of the dashboard keyed by path under the prefix.
+var assets = map[string]asset{
+	"app.js":    {contentType: "application/javascript; charset=utf-8", body: appJS},
+	"style.css": {contentType: "text/css; charset=utf-8", body: styleCSS},
+}
+
+type handler struct{}
+
+// NewHandler returns implement of dashboard
+func NewHandler() (http.Handler, error) {
+	return &handler{}, nil
+}
+
+// ServeHTTP responses the assets, or the page for any other paths because the page routes itself by the path.
+// The page shows cancel and rerun buttons only if the admin token is configured.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if a, ok := assets[strings.TrimPrefix(r.URL.Path, Prefix)]; ok {
+		w.Header().Set("Content-Type", a.contentType)
+		_, _ = w.Write([]byte(a.body))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	admin := len(application.Config.Server.AdminToken.String()) > 0
+	_, _ = w.Write([]byte(fmt.Sprintf(indexHTML, admin)))
+}
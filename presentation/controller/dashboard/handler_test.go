@@ -0,0 +1,130 @@
+package dashboard_test
+
+import (
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/presentation/controller/dashboard"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewHandler(t *testing.T) {
+	// when
+	got, err := dashboard.NewHandler()
+
+	// then
+	if err != nil {
+		t.Errorf("error must be nil, but got %+v", err)
+	}
+
+	// and
+	if got == nil {
+		t.Error("must not be nil")
+	}
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{
+			path:        "/ui/",
+			contentType: "text/html; charset=utf-8",
+			contains:    `<script src="/ui/app.js"></script>`,
+		},
+		{
+			path:        "/ui/jobs/72d3162e-cc78-11e3-81ab-4c9367dc0958",
+			contentType: "text/html; charset=utf-8",
+			contains:    `<script src="/ui/app.js"></script>`,
+		},
+		{
+			path:        "/ui/app.js",
+			contentType: "application/javascript; charset=utf-8",
+			contains:    "new EventSource(",
+		},
+		{
+			path:        "/ui/style.css",
+			contentType: "text/css; charset=utf-8",
+			contains:    ".fg-red",
+		},
+	} {
+		t.Run(tt.path, func(t *testing.T) {
+			// given
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tt.path, nil)
+
+			// and
+			sut, _ := dashboard.NewHandler()
+
+			// when
+			sut.ServeHTTP(rec, req)
+
+			// then
+			if rec.Code != http.StatusOK {
+				t.Errorf("must be %d, but got %d", http.StatusOK, rec.Code)
+			}
+
+			// and
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("content type must be %s, but got %s", tt.contentType, got)
+			}
+
+			// and
+			if !strings.Contains(rec.Body.String(), tt.contains) {
+				t.Errorf("must contain %s", tt.contains)
+			}
+		})
+	}
+}
+
+func TestHandler_ServeHTTP_Admin(t *testing.T) {
+	// given
+	token := application.Config.Server.AdminToken
+	defer func() {
+		application.Config.Server.AdminToken = token
+	}()
+
+	t.Run("when admin token is configured", func(t *testing.T) {
+		// given
+		application.Config.Server.AdminToken = "duci_admin_token"
+
+		// and
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/ui/", nil)
+
+		// and
+		sut, _ := dashboard.NewHandler()
+
+		// when
+		sut.ServeHTTP(rec, req)
+
+		// then
+		if want := `<body data-admin="true">`; !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("must contain %s", want)
+		}
+	})
+
+	t.Run("when admin token is not configured", func(t *testing.T) {
+		// given
+		application.Config.Server.AdminToken = ""
+
+		// and
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/ui/", nil)
+
+		// and
+		sut, _ := dashboard.NewHandler()
+
+		// when
+		sut.ServeHTTP(rec, req)
+
+		// then
+		if want := `<body data-admin="false">`; !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("must contain %s", want)
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package job
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// escapeSequence matches ANSI escape sequences. Only SGR sequences ending with `m` are rendered.
+var escapeSequence = regexp.MustCompile(`\x1b\[([0-9;?]*)([A-Za-z])`)
+
+// ansiColors are names of 8 colors, also used as class names such as `fg-red`.
+var ansiColors = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// ansiStyle represents text attributes set by SGR parameters.
+type ansiStyle struct {
+	bold      bool
+	faint     bool
+	italic    bool
+	underline bool
+	fg        string
+	bg        string
+}
+
+// render returns the style as attributes of span, or empty string for default style.
+func (s ansiStyle) render() string {
+	var classes, styles []string
+	if s.bold {
+		classes = append(classes, "bold")
+	}
+	if s.faint {
+		classes = append(classes, "faint")
+	}
+	if s.italic {
+		classes = append(classes, "italic")
+	}
+	if s.underline {
+		classes = append(classes, "underline")
+	}
+	for _, color := range []struct {
+		prefix   string
+		property string
+		value    string
+	}{
+		{"fg-", "color", s.fg},
+		{"bg-", "background-color", s.bg},
+	} {
+		switch {
+		case len(color.value) == 0:
+		case strings.HasPrefix(color.value, "#"):
+			styles = append(styles, fmt.Sprintf("%s:%s", color.property, color.value))
+		default:
+			classes = append(classes, color.prefix+color.value)
+		}
+	}
+
+	var attrs []string
+	if len(classes) > 0 {
+		attrs = append(attrs, fmt.Sprintf(`class="%s"`, strings.Join(classes, " ")))
+	}
+	if len(styles) > 0 {
+		attrs = append(attrs, fmt.Sprintf(`style="%s"`, strings.Join(styles, ";")))
+	}
+	return strings.Join(attrs, " ")
+}
+
+// ansiRenderer converts ANSI colored text into HTML. The style is carried over lines as a terminal does.
+type ansiRenderer struct {
+	style ansiStyle
+}
+
+// render returns HTML of a line. Escape sequences other than SGR are removed.
+func (r *ansiRenderer) render(line string) string {
+	var b strings.Builder
+	open := r.open(&b)
+
+	pos := 0
+	for _, loc := range escapeSequence.FindAllStringSubmatchIndex(line, -1) {
+		b.WriteString(html.EscapeString(line[pos:loc[0]]))
+		pos = loc[1]
+		if line[loc[4]:loc[5]] != "m" {
+			continue
+		}
+
+		if open {
+			b.WriteString("</span>")
+		}
+		r.apply(line[loc[2]:loc[3]])
+		open = r.open(&b)
+	}
+	b.WriteString(html.EscapeString(line[pos:]))
+
+	if open {
+		b.WriteString("</span>")
+	}
+	return b.String()
+}
+
+// open writes a start tag of span for the current style, and returns whether it was written.
+func (r *ansiRenderer) open(b *strings.Builder) bool {
+	attrs := r.style.render()
+	if len(attrs) == 0 {
+		return false
+	}
+	b.WriteString("<span " + attrs + ">")
+	return true
+}
+
+// apply updates the style with SGR parameters separated by semicolons.
+func (r *ansiRenderer) apply(params string) {
+	if len(params) == 0 {
+		r.style = ansiStyle{}
+		return
+	}
+
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if err != nil {
+			continue
+		}
+		switch {
+		case code == 0:
+			r.style = ansiStyle{}
+		case code == 1:
+			r.style.bold = true
+		case code == 2:
+			r.style.faint = true
+		case code == 3:
+			r.style.italic = true
+		case code == 4:
+			r.style.underline = true
+		case code == 22:
+			r.style.bold, r.style.faint = false, false
+		case code == 23:
+			r.style.italic = false
+		case code == 24:
+			r.style.underline = false
+		case 30 <= code && code <= 37:
+			r.style.fg = ansiColors[code-30]
+		case code == 38:
+			r.style.fg, i = extendedColor(codes, i)
+		case code == 39:
+			r.style.fg = ""
+		case 40 <= code && code <= 47:
+			r.style.bg = ansiColors[code-40]
+		case code == 48:
+			r.style.bg, i = extendedColor(codes, i)
+		case code == 49:
+			r.style.bg = ""
+		case 90 <= code && code <= 97:
+			r.style.fg = "bright-" + ansiColors[code-90]
+		case 100 <= code && code <= 107:
+			r.style.bg = "bright-" + ansiColors[code-100]
+		}
+	}
+}
+
+// extendedColor returns a color of 256 colors (`38;5;n`) or true color (`38;2;r;g;b`) at the index, and the last index read.
+func extendedColor(codes []string, i int) (string, int) {
+	if i+2 < len(codes) && codes[i+1] == "5" {
+		n, err := strconv.Atoi(codes[i+2])
+		if err != nil || n < 0 || n > 255 {
+			return "", i + 2
+		}
+		return xterm256(n), i + 2
+	}
+	if i+4 < len(codes) && codes[i+1] == "2" {
+		var rgb [3]int
+		for j := range rgb {
+			rgb[j], _ = strconv.Atoi(codes[i+2+j])
+		}
+		return fmt.Sprintf("#%02x%02x%02x", rgb[0]&0xff, rgb[1]&0xff, rgb[2]&0xff), i + 4
+	}
+	return "", len(codes)
+}
+
+// xterm256 returns a color of xterm 256 color palette.
+func xterm256(n int) string {
+	switch {
+	case n < 8:
+		return ansiColors[n]
+	case n < 16:
+		return "bright-" + ansiColors[n-8]
+	case n < 232:
+		n -= 16
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		return fmt.Sprintf("#%02x%02x%02x", level(n/36), level(n/6%6), level(n%6))
+	default:
+		gray := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+	}
+}
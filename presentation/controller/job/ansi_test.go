@@ -0,0 +1,58 @@
+package job_test
+
+import (
+	jobController "github.com/duck8823/duci/presentation/controller/job"
+	"github.com/google/go-cmp/cmp"
+	"testing"
+)
+
+func TestAnsiRenderer_Render(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		name  string
+		given []string
+		want  []string
+	}{
+		{
+			name:  "without escape sequence",
+			given: []string{"<hello & world>"},
+			want:  []string{"&lt;hello &amp; world&gt;"},
+		},
+		{
+			name:  "with colors",
+			given: []string{"\x1b[1;32mPASS\x1b[0m \x1b[41;97mFAIL\x1b[m"},
+			want:  []string{`<span class="bold fg-green">PASS</span> <span class="fg-bright-white bg-red">FAIL</span>`},
+		},
+		{
+			name:  "with 256 colors and true color",
+			given: []string{"\x1b[38;5;196mred\x1b[48;2;0;128;255mblue"},
+			want:  []string{`<span style="color:#ff0000">red</span><span style="color:#ff0000;background-color:#0080ff">blue</span>`},
+		},
+		{
+			name:  "with style carried over lines",
+			given: []string{"\x1b[33mfirst", "second\x1b[39m third"},
+			want:  []string{`<span class="fg-yellow">first</span>`, `<span class="fg-yellow">second</span> third`},
+		},
+		{
+			name:  "with other escape sequences",
+			given: []string{"\x1b[2K\x1b[1Gprogress\x1b[?25h"},
+			want:  []string{"progress"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			sut := &jobController.AnsiRenderer{}
+
+			// when
+			var got []string
+			for _, line := range tt.given {
+				got = append(got, sut.Render(line))
+			}
+
+			// then
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("must be equal, but %+v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
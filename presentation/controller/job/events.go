@@ -0,0 +1,85 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"net/http"
+	"strconv"
+)
+
+type eventsHandler struct {
+	handler
+}
+
+// NewEventsHandler returns implement of job log as server-sent events
+func NewEventsHandler() (http.Handler, error) {
+	service, err := jobService.GetInstance()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &eventsHandler{handler{service: service}}, nil
+}
+
+// ServeHTTP responses log stream as server-sent events. It resumes after Last-Event-ID if given.
+func (h *eventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	id, err := uuid.Parse(chi.URLParam(r, "uuid"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error occurred: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	last := -1
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		last, err = strconv.Atoi(lastEventID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid Last-Event-ID: %s", lastEventID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	f, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	match := streamFilter(r)
+	var sent bool
+	err = h.stream(r.Context(), job.ID(id), last, func(event job.LogEvent) error {
+		if !match(event.Line) {
+			return nil
+		}
+		data, err := json.Marshal(event.Line)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: log\ndata: %s\n\n", event.Seq, data); err != nil {
+			return errors.WithStack(err)
+		}
+		f.Flush()
+		sent = true
+		return nil
+	})
+	if err != nil && !sent {
+		http.Error(w, fmt.Sprintf(" Error occurred: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "event: error\ndata: %s\n\n", err.Error())
+		f.Flush()
+		return
+	}
+
+	_, _ = fmt.Fprint(w, "event: finished\ndata: \n\n")
+	f.Flush()
+}
@@ -11,3 +11,13 @@ func (h *Handler) SetService(service job.Service) (reset func()) {
 		h.service = tmp
 	}
 }
+
+type EventsHandler = eventsHandler
+
+type WebSocketHandler = websocketHandler
+
+type AnsiRenderer = ansiRenderer
+
+func (r *AnsiRenderer) Render(line string) string {
+	return r.render(line)
+}
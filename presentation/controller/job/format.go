@@ -0,0 +1,172 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/pkg/errors"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// formats of log selected with `format` parameter
+const (
+	formatJSON = "json"
+	formatText = "text"
+	formatHTML = "html"
+	formatGzip = "gzip"
+)
+
+// mediaTypes maps media types in Accept header to formats
+var mediaTypes = map[string]string{
+	"application/x-ndjson": formatJSON,
+	"application/json":     formatJSON,
+	"text/plain":           formatText,
+	"text/html":            formatHTML,
+	"application/gzip":     formatGzip,
+}
+
+// negotiate returns the format in the query, or the first media type acceptable in Accept header.
+// It returns json if neither of them selects a format.
+func negotiate(r *http.Request) (string, error) {
+	if format := r.URL.Query().Get("format"); len(format) > 0 {
+		for _, known := range mediaTypes {
+			if format == known {
+				return format, nil
+			}
+		}
+		return "", errors.Errorf("unknown format: %s", format)
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		if format, ok := mediaTypes[mediaType]; ok {
+			return format, nil
+		}
+	}
+	return formatJSON, nil
+}
+
+// encoder writes log lines in a format.
+type encoder interface {
+	Encode(line job.LogLine) error
+	Close() error
+}
+
+type jsonEncoder struct {
+	w io.Writer
+}
+
+// Encode writes a line as a json object followed by newline.
+func (e *jsonEncoder) Encode(line job.LogLine) error {
+	if err := json.NewEncoder(e.w).Encode(line); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Close does nothing.
+func (e *jsonEncoder) Close() error {
+	return nil
+}
+
+type textEncoder struct {
+	w          io.Writer
+	timestamps bool
+}
+
+// Encode writes a message of line, prefixed with the timestamp if enabled.
+func (e *textEncoder) Encode(line job.LogLine) error {
+	msg := line.Message
+	if e.timestamps {
+		msg = fmt.Sprintf("%s %s", line.Timestamp.Format(time.RFC3339Nano), msg)
+	}
+	if _, err := fmt.Fprintln(e.w, msg); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Close does nothing.
+func (e *textEncoder) Close() error {
+	return nil
+}
+
+type htmlEncoder struct {
+	w          io.Writer
+	timestamps bool
+	renderer   *ansiRenderer
+	started    bool
+}
+
+// Encode writes a line with ANSI colors rendered. It writes the head of document before the first line.
+func (e *htmlEncoder) Encode(line job.LogLine) error {
+	if err := e.start(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	var timestamp string
+	if e.timestamps {
+		timestamp = fmt.Sprintf(`<span class="time">%s</span> `, line.Timestamp.Format(time.RFC3339Nano))
+	}
+	if _, err := fmt.Fprintf(e.w, "<span class=\"line %s\">%s%s</span>\n", html.EscapeString(string(line.Stream)), timestamp, e.renderer.render(line.Message)); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Close writes the end of document.
+func (e *htmlEncoder) Close() error {
+	if err := e.start(); err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := fmt.Fprint(e.w, "</pre>\n</body>\n</html>\n"); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (e *htmlEncoder) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	if _, err := fmt.Fprint(e.w, htmlHead); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// htmlHead is the head of log document, which defines classes of ANSI colors.
+const htmlHead = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>duci log</title>
+<style>
+body { margin: 0; background-color: #1e1e1e; color: #d4d4d4; }
+pre { margin: 0; padding: 1em; font-family: monospace; white-space: pre-wrap; word-break: break-all; }
+.time { color: #808080; }
+.stderr { color: #f48771; }
+.system { color: #9cdcfe; }
+.bold { font-weight: bold; } .faint { opacity: 0.6; } .italic { font-style: italic; } .underline { text-decoration: underline; }
+.fg-black { color: #000000; } .fg-red { color: #cd3131; } .fg-green { color: #0dbc79; } .fg-yellow { color: #e5e510; }
+.fg-blue { color: #2472c8; } .fg-magenta { color: #bc3fbc; } .fg-cyan { color: #11a8cd; } .fg-white { color: #e5e5e5; }
+.fg-bright-black { color: #666666; } .fg-bright-red { color: #f14c4c; } .fg-bright-green { color: #23d18b; } .fg-bright-yellow { color: #f5f543; }
+.fg-bright-blue { color: #3b8eea; } .fg-bright-magenta { color: #d670d6; } .fg-bright-cyan { color: #29b8db; } .fg-bright-white { color: #ffffff; }
+.bg-black { background-color: #000000; } .bg-red { background-color: #cd3131; } .bg-green { background-color: #0dbc79; } .bg-yellow { background-color: #e5e510; }
+.bg-blue { background-color: #2472c8; } .bg-magenta { background-color: #bc3fbc; } .bg-cyan { background-color: #11a8cd; } .bg-white { background-color: #e5e5e5; }
+.bg-bright-black { background-color: #666666; } .bg-bright-red { background-color: #f14c4c; } .bg-bright-green { background-color: #23d18b; } .bg-bright-yellow { background-color: #f5f543; }
+.bg-bright-blue { background-color: #3b8eea; } .bg-bright-magenta { background-color: #d670d6; } .bg-bright-cyan { background-color: #29b8db; } .bg-bright-white { background-color: #ffffff; }
+</style>
+</head>
+<body>
+<pre>
+`
@@ -1,8 +1,8 @@
 package job
 
 import (
+	"compress/gzip"
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/duck8823/duci/application"
 	jobService "github.com/duck8823/duci/application/service/job"
@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"net/http"
+	"strings"
 )
 
 type handler struct {
@@ -27,7 +28,7 @@ func NewHandler() (http.Handler, error) {
 	return &handler{service: service}, nil
 }
 
-// ServeHTTP responses log stream
+// ServeHTTP responses log stream in the format selected with `format` parameter or Accept header
 func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
@@ -37,52 +38,236 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.logs(w, job.ID(id)); err != nil {
+	format, err := negotiate(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	sel, err := selectionOf(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid parameter: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	if format == formatGzip {
+		h.download(w, job.ID(id), sel)
+		return
+	}
+
+	var enc encoder
+	switch format {
+	case formatText:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		enc = &textEncoder{w: w, timestamps: sel.timestamps}
+	case formatHTML:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		enc = &htmlEncoder{w: w, timestamps: sel.timestamps, renderer: &ansiRenderer{}}
+	default:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc = &jsonEncoder{w: w}
+	}
+
+	if err := h.logs(r.Context(), w, job.ID(id), enc, sel); err != nil {
 		http.Error(w, fmt.Sprintf(" Error occurred: %s", err.Error()), http.StatusInternalServerError)
 		return
 	}
 }
 
-func (h *handler) logs(w http.ResponseWriter, id job.ID) error {
+func (h *handler) logs(ctx context.Context, w http.ResponseWriter, id job.ID, enc encoder, sel selection) error {
 	f, ok := w.(http.Flusher)
 	if !ok {
 		return errors.New("Streaming unsupported")
 	}
 
-	timeout, cancel := context.WithTimeout(context.Background(), application.Config.Timeout())
+	last, err := h.first(id, sel)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := h.stream(ctx, id, last, sel.until(func(event job.LogEvent) error {
+		if !sel.match(event.Line) {
+			return nil
+		}
+		if err := enc.Encode(event.Line); err != nil {
+			logrus.Errorf("%+v", err)
+		}
+		f.Flush()
+		return nil
+	})); err != nil {
+		return errors.WithStack(err)
+	}
+	return enc.Close()
+}
+
+// download responses the log of finished job as a gzip compressed text file.
+func (h *handler) download(w http.ResponseWriter, id job.ID, sel selection) {
+	stored, err := h.service.FindBy(id)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(" Error occurred: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+	if !stored.Finished {
+		http.Error(w, "Job is not finished yet", http.StatusConflict)
+		return
+	}
+
+	last, err := h.first(id, sel)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(" Error occurred: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+	lines, err := h.service.FindLogs(id, last+1, sel.end())
+	if err != nil {
+		http.Error(w, fmt.Sprintf(" Error occurred: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.log.gz"`, id))
+
+	gz := gzip.NewWriter(w)
+	enc := &textEncoder{w: gz, timestamps: sel.timestamps}
+	for _, line := range lines {
+		if !sel.match(line) {
+			continue
+		}
+		if err := enc.Encode(line); err != nil {
+			logrus.Errorf("%+v", err)
+			return
+		}
+	}
+	if err := gz.Close(); err != nil {
+		logrus.Errorf("%+v", err)
+	}
+}
+
+// first returns the sequence number before the first line to send.
+// With tail, it counts lines matching the selection back from the end of stored lines.
+func (h *handler) first(id job.ID, sel selection) (int, error) {
+	if sel.tail <= 0 {
+		return sel.from - 1, nil
+	}
+
+	last := sel.from - 1
+	count := 0
+	if err := h.service.ReverseLogs(id, sel.from, sel.end(), func(seq int, line job.LogLine) bool {
+		if !sel.match(line) {
+			return true
+		}
+		count++
+		if count < sel.tail {
+			return true
+		}
+		last = seq - 1
+		return false
+	}); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return last, nil
+}
+
+// streamFilter returns a function matching lines of streams in the query such as `?stream=stdout,stderr`.
+// It matches all lines without the query.
+func streamFilter(r *http.Request) func(job.LogLine) bool {
+	streams := make(map[job.LogStream]bool)
+	for _, val := range r.URL.Query()["stream"] {
+		for _, stream := range strings.Split(val, ",") {
+			streams[job.LogStream(strings.TrimSpace(stream))] = true
+		}
+	}
+	return func(line job.LogLine) bool {
+		return len(streams) == 0 || streams[line.Stream]
+	}
+}
+
+// stream sends log lines after the sequence number until the job finished or timeout.
+func (h *handler) stream(ctx context.Context, id job.ID, last int, send func(job.LogEvent) error) error {
+	timeout, cancel := context.WithTimeout(ctx, application.Config.Timeout())
 	defer cancel()
 
 	errs := make(chan error, 1)
 
 	go func() {
-		var read int
-		for {
-			job, err := h.service.FindBy(id)
-			if err != nil {
-				errs <- errors.WithStack(err)
-				break
-			}
-			for _, msg := range job.Stream[read:] {
-				if err := json.NewEncoder(w).Encode(msg); err != nil {
-					logrus.Errorf("%+v", err)
-				}
-				f.Flush()
-				read++
-			}
-			if job.Finished {
-				errs <- nil
-				break
-			}
-		}
+		errs <- h.follow(timeout, id, last, send)
 	}()
 
 	select {
 	case <-timeout.Done():
 		return timeout.Err()
 	case err := <-errs:
-		if err != nil {
+		if err != nil && errors.Cause(err) != errEnough {
 			return errors.WithStack(err)
 		}
 		return nil
 	}
 }
+
+// follow sends stored log lines after the sequence number, and then lines published to the job.
+// It re-reads stored lines when it missed published lines.
+func (h *handler) follow(ctx context.Context, id job.ID, last int, send func(job.LogEvent) error) error {
+	for {
+		events, unsubscribe := h.service.Subscribe(id)
+
+		stored, err := h.service.FindBy(id)
+		if err != nil {
+			unsubscribe()
+			return errors.WithStack(err)
+		}
+		if ctx.Err() != nil {
+			unsubscribe()
+			return ctx.Err()
+		}
+
+		// lines are read after the state, so that all lines are read if the job has finished
+		lines, err := h.service.FindLogs(id, last+1, -1)
+		if err != nil {
+			unsubscribe()
+			return errors.WithStack(err)
+		}
+		for _, line := range lines {
+			if err := send(job.LogEvent{Seq: last + 1, Line: line}); err != nil {
+				unsubscribe()
+				return errors.WithStack(err)
+			}
+			last++
+		}
+		if stored.Finished {
+			unsubscribe()
+			return nil
+		}
+
+		missed, err := receive(ctx, events, &last, send)
+		unsubscribe()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if !missed {
+			return nil
+		}
+	}
+}
+
+// receive sends published lines until the job finished. It returns true if some lines are missed.
+func receive(ctx context.Context, events <-chan job.LogEvent, last *int, send func(job.LogEvent) error) (missed bool, err error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case event, ok := <-events:
+			switch {
+			case !ok:
+				return true, nil
+			case event.Finished:
+				return false, nil
+			case event.Seq <= *last:
+				continue
+			case event.Seq > *last+1:
+				return true, nil
+			}
+			if err := send(event); err != nil {
+				return false, errors.WithStack(err)
+			}
+			*last = event.Seq
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package job_test
 
 import (
+	"compress/gzip"
 	"context"
+	"fmt"
 	"github.com/duck8823/duci/application"
 	jobService "github.com/duck8823/duci/application/service/job"
 	"github.com/duck8823/duci/application/service/job/mock_job"
@@ -13,8 +15,12 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"golang.org/x/net/websocket"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -86,15 +92,249 @@ func TestHandler_ServeHTTP(t *testing.T) {
 		defer ctrl.Finish()
 
 		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Subscribe(gomock.Eq(id)).
+			Times(1).
+			Return(make(chan job.LogEvent), func() {})
 		service.EXPECT().
 			FindBy(gomock.Eq(id)).
 			Times(1).
 			Return(&job.Job{
 				ID:       id,
 				Finished: true,
-				Stream: []job.LogLine{
-					{Timestamp: time.Now(), Message: "Hello Test"},
-				},
+			}, nil)
+		service.EXPECT().
+			FindLogs(gomock.Eq(id), gomock.Eq(0), gomock.Eq(-1)).
+			Times(1).
+			Return([]job.LogLine{
+				{Timestamp: time.Now(), Message: "Hello Test"},
+			}, nil)
+
+		// and
+		sut := &jobController.Handler{}
+		defer sut.SetService(service)()
+
+		// when
+		sut.ServeHTTP(rec, req.WithContext(ctx))
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("with lines published after subscribe", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		id := job.ID(uuid.New())
+
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("uuid", uuid.UUID(id).String())
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		// and
+		first := job.LogLine{Timestamp: time.Unix(1, 0).UTC(), Message: "first"}
+		second := job.LogLine{Timestamp: time.Unix(2, 0).UTC(), Message: "second"}
+
+		events := make(chan job.LogEvent, 3)
+		events <- job.LogEvent{Seq: 0, Line: first}
+		events <- job.LogEvent{Seq: 1, Line: second}
+		events <- job.LogEvent{Finished: true}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Subscribe(gomock.Eq(id)).
+			Times(1).
+			Return(events, func() {})
+		service.EXPECT().
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(&job.Job{ID: id}, nil)
+		service.EXPECT().
+			FindLogs(gomock.Eq(id), gomock.Eq(0), gomock.Eq(-1)).
+			Times(1).
+			Return([]job.LogLine{first}, nil)
+
+		// and
+		sut := &jobController.Handler{}
+		defer sut.SetService(service)()
+
+		// when
+		sut.ServeHTTP(rec, req.WithContext(ctx))
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+
+		// and
+		got := rec.Body.String()
+		want := "{\"time\":\"1970-01-01T00:00:01Z\",\"message\":\"first\"}\n" +
+			"{\"time\":\"1970-01-01T00:00:02Z\",\"message\":\"second\"}\n"
+		if got != want {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("when subscriber missed published lines", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		id := job.ID(uuid.New())
+
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("uuid", uuid.UUID(id).String())
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		// and
+		first := job.LogLine{Timestamp: time.Unix(1, 0).UTC(), Message: "first"}
+		second := job.LogLine{Timestamp: time.Unix(2, 0).UTC(), Message: "second"}
+
+		dropped := make(chan job.LogEvent)
+		close(dropped)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		gomock.InOrder(
+			service.EXPECT().
+				Subscribe(gomock.Eq(id)).
+				Return(dropped, func() {}),
+			service.EXPECT().
+				FindBy(gomock.Eq(id)).
+				Return(&job.Job{ID: id}, nil),
+			service.EXPECT().
+				FindLogs(gomock.Eq(id), gomock.Eq(0), gomock.Eq(-1)).
+				Return([]job.LogLine{first}, nil),
+			service.EXPECT().
+				Subscribe(gomock.Eq(id)).
+				Return(make(chan job.LogEvent), func() {}),
+			service.EXPECT().
+				FindBy(gomock.Eq(id)).
+				Return(&job.Job{ID: id, Finished: true}, nil),
+			service.EXPECT().
+				FindLogs(gomock.Eq(id), gomock.Eq(1), gomock.Eq(-1)).
+				Return([]job.LogLine{second}, nil),
+		)
+
+		// and
+		sut := &jobController.Handler{}
+		defer sut.SetService(service)()
+
+		// when
+		sut.ServeHTTP(rec, req.WithContext(ctx))
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+
+		// and
+		got := rec.Body.String()
+		want := "{\"time\":\"1970-01-01T00:00:01Z\",\"message\":\"first\"}\n" +
+			"{\"time\":\"1970-01-01T00:00:02Z\",\"message\":\"second\"}\n"
+		if got != want {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("with stream filter", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/?stream=stderr,system", nil)
+
+		// and
+		id := job.ID(uuid.New())
+
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("uuid", uuid.UUID(id).String())
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Subscribe(gomock.Eq(id)).
+			Times(1).
+			Return(make(chan job.LogEvent), func() {})
+		service.EXPECT().
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(&job.Job{ID: id, Finished: true}, nil)
+		service.EXPECT().
+			FindLogs(gomock.Eq(id), gomock.Eq(0), gomock.Eq(-1)).
+			Times(1).
+			Return([]job.LogLine{
+				{Timestamp: time.Unix(1, 0).UTC(), Stream: job.STDOUT, Message: "out"},
+				{Timestamp: time.Unix(2, 0).UTC(), Stream: job.STDERR, Message: "err"},
+				{Timestamp: time.Unix(3, 0).UTC(), Stream: job.SYSTEM, Message: "system"},
+			}, nil)
+
+		// and
+		sut := &jobController.Handler{}
+		defer sut.SetService(service)()
+
+		// when
+		sut.ServeHTTP(rec, req.WithContext(ctx))
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+
+		// and
+		got := rec.Body.String()
+		want := "{\"time\":\"1970-01-01T00:00:02Z\",\"stream\":\"stderr\",\"message\":\"err\"}\n" +
+			"{\"time\":\"1970-01-01T00:00:03Z\",\"stream\":\"system\",\"message\":\"system\"}\n"
+		if got != want {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("with text format and timestamps", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/?format=text&timestamps=true", nil)
+
+		// and
+		id := job.ID(uuid.New())
+
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("uuid", uuid.UUID(id).String())
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Subscribe(gomock.Eq(id)).
+			Times(1).
+			Return(make(chan job.LogEvent), func() {})
+		service.EXPECT().
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(&job.Job{ID: id, Finished: true}, nil)
+		service.EXPECT().
+			FindLogs(gomock.Eq(id), gomock.Eq(0), gomock.Eq(-1)).
+			Times(1).
+			Return([]job.LogLine{
+				{Timestamp: time.Unix(1, 0).UTC(), Stream: job.STDOUT, Message: "out"},
+				{Timestamp: time.Unix(2, 0).UTC(), Stream: job.STDERR, Message: "err"},
 			}, nil)
 
 		// and
@@ -108,6 +348,275 @@ func TestHandler_ServeHTTP(t *testing.T) {
 		if rec.Code != http.StatusOK {
 			t.Errorf("must be %d, but got %d", http.StatusOK, rec.Code)
 		}
+
+		// and
+		if got := rec.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+			t.Errorf("content type must be text/plain, but got %s", got)
+		}
+
+		// and
+		got := rec.Body.String()
+		want := "1970-01-01T00:00:01Z out\n" +
+			"1970-01-01T00:00:02Z err\n"
+		if got != want {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("when accept html", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8")
+
+		// and
+		id := job.ID(uuid.New())
+
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("uuid", uuid.UUID(id).String())
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Subscribe(gomock.Eq(id)).
+			Times(1).
+			Return(make(chan job.LogEvent), func() {})
+		service.EXPECT().
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(&job.Job{ID: id, Finished: true}, nil)
+		service.EXPECT().
+			FindLogs(gomock.Eq(id), gomock.Eq(0), gomock.Eq(-1)).
+			Times(1).
+			Return([]job.LogLine{
+				{Timestamp: time.Unix(1, 0).UTC(), Stream: job.STDOUT, Message: "\x1b[31mred\x1b[0m <b>"},
+			}, nil)
+
+		// and
+		sut := &jobController.Handler{}
+		defer sut.SetService(service)()
+
+		// when
+		sut.ServeHTTP(rec, req.WithContext(ctx))
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+
+		// and
+		if got := rec.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+			t.Errorf("content type must be text/html, but got %s", got)
+		}
+
+		// and
+		got := rec.Body.String()
+		want := `<span class="line stdout"><span class="fg-red">red</span> &lt;b&gt;</span>`
+		if !strings.Contains(got, want) {
+			t.Errorf("must contain %s, but got %s", want, got)
+		}
+		if !strings.HasSuffix(got, "</html>\n") {
+			t.Errorf("must end with </html>, but got %s", got)
+		}
+	})
+
+	t.Run("with tail and range", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/?format=text&from=1&to=3&tail=2", nil)
+
+		// and
+		id := job.ID(uuid.New())
+
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("uuid", uuid.UUID(id).String())
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			ReverseLogs(gomock.Eq(id), gomock.Eq(1), gomock.Eq(4), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(_ job.ID, _ int, _ int, visit func(int, job.LogLine) bool) error {
+				for _, seq := range []int{3, 2, 1} {
+					if !visit(seq, job.LogLine{Message: strconv.Itoa(seq)}) {
+						return nil
+					}
+				}
+				t.Error("must stop visiting at the tail")
+				return nil
+			})
+		service.EXPECT().
+			Subscribe(gomock.Eq(id)).
+			Times(1).
+			Return(make(chan job.LogEvent), func() {})
+		service.EXPECT().
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(&job.Job{ID: id, Finished: false}, nil)
+		service.EXPECT().
+			FindLogs(gomock.Eq(id), gomock.Eq(2), gomock.Eq(-1)).
+			Times(1).
+			Return([]job.LogLine{{Message: "2"}, {Message: "3"}, {Message: "4"}}, nil)
+
+		// and
+		sut := &jobController.Handler{}
+		defer sut.SetService(service)()
+
+		// when
+		sut.ServeHTTP(rec, req.WithContext(ctx))
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+
+		// and
+		got := rec.Body.String()
+		want := "2\n3\n"
+		if got != want {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("with gzip format", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/?format=gzip&stream=stdout", nil)
+
+		// and
+		id := job.ID(uuid.New())
+
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("uuid", uuid.UUID(id).String())
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(&job.Job{ID: id, Finished: true}, nil)
+		service.EXPECT().
+			FindLogs(gomock.Eq(id), gomock.Eq(0), gomock.Eq(-1)).
+			Times(1).
+			Return([]job.LogLine{
+				{Stream: job.STDOUT, Message: "out"},
+				{Stream: job.STDERR, Message: "err"},
+			}, nil)
+
+		// and
+		sut := &jobController.Handler{}
+		defer sut.SetService(service)()
+
+		// when
+		sut.ServeHTTP(rec, req.WithContext(ctx))
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+
+		// and
+		wantDisposition := fmt.Sprintf(`attachment; filename="%s.log.gz"`, id)
+		if got := rec.Header().Get("Content-Disposition"); got != wantDisposition {
+			t.Errorf("content disposition must be %s, but got %s", wantDisposition, got)
+		}
+
+		// and
+		gz, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		got, err := ioutil.ReadAll(gz)
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		if string(got) != "out\n" {
+			t.Errorf("must be equal, but %+v", cmp.Diff(string(got), "out\n"))
+		}
+	})
+
+	t.Run("with gzip format of running job", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept", "application/gzip")
+
+		// and
+		id := job.ID(uuid.New())
+
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("uuid", uuid.UUID(id).String())
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(&job.Job{ID: id, Finished: false}, nil)
+		service.EXPECT().
+			FindLogs(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &jobController.Handler{}
+		defer sut.SetService(service)()
+
+		// when
+		sut.ServeHTTP(rec, req.WithContext(ctx))
+
+		// then
+		if rec.Code != http.StatusConflict {
+			t.Errorf("must be %d, but got %d", http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("with invalid query", func(t *testing.T) {
+		// where
+		for _, query := range []string{
+			"format=xml",
+			"tail=-1",
+			"from=foo",
+			"from=3&to=1",
+			"timestamps=maybe",
+		} {
+			t.Run(query, func(t *testing.T) {
+				// given
+				rec := httptest.NewRecorder()
+				req := httptest.NewRequest("GET", "/?"+query, nil)
+
+				// and
+				routeCtx := chi.NewRouteContext()
+				routeCtx.URLParams.Add("uuid", uuid.New().String())
+				ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+				// and
+				sut := &jobController.Handler{}
+
+				// when
+				sut.ServeHTTP(rec, req.WithContext(ctx))
+
+				// then
+				if rec.Code != http.StatusBadRequest {
+					t.Errorf("must be %d, but got %d", http.StatusBadRequest, rec.Code)
+				}
+			})
+		}
 	})
 
 	t.Run("with invalid path param", func(t *testing.T) {
@@ -148,6 +657,10 @@ func TestHandler_ServeHTTP(t *testing.T) {
 		defer ctrl.Finish()
 
 		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Subscribe(gomock.Eq(id)).
+			Times(1).
+			Return(make(chan job.LogEvent), func() {})
 		service.EXPECT().
 			FindBy(gomock.Eq(id)).
 			Times(1).
@@ -190,6 +703,10 @@ func TestHandler_ServeHTTP(t *testing.T) {
 		defer ctrl.Finish()
 
 		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Subscribe(gomock.Eq(id)).
+			Times(1).
+			Return(make(chan job.LogEvent), func() {})
 		service.EXPECT().
 			FindBy(gomock.Eq(id)).
 			Times(1).
@@ -210,3 +727,194 @@ func TestHandler_ServeHTTP(t *testing.T) {
 		}
 	})
 }
+
+func TestEventsHandler_ServeHTTP(t *testing.T) {
+	t.Run("with Last-Event-ID", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Last-Event-ID", "0")
+
+		// and
+		id := job.ID(uuid.New())
+
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("uuid", uuid.UUID(id).String())
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Subscribe(gomock.Eq(id)).
+			Times(1).
+			Return(make(chan job.LogEvent), func() {})
+		service.EXPECT().
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(&job.Job{
+				ID:       id,
+				Finished: true,
+			}, nil)
+		service.EXPECT().
+			FindLogs(gomock.Eq(id), gomock.Eq(1), gomock.Eq(-1)).
+			Times(1).
+			Return([]job.LogLine{
+				{Timestamp: time.Unix(2, 0).UTC(), Message: "second"},
+			}, nil)
+
+		// and
+		sut := &jobController.EventsHandler{}
+		defer sut.SetService(service)()
+
+		// when
+		sut.ServeHTTP(rec, req.WithContext(ctx))
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+
+		// and
+		if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+			t.Errorf("content type must be text/event-stream, but got %s", got)
+		}
+
+		// and
+		got := rec.Body.String()
+		want := "id: 1\nevent: log\ndata: {\"time\":\"1970-01-01T00:00:02Z\",\"message\":\"second\"}\n\n" +
+			"event: finished\ndata: \n\n"
+		if got != want {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("with invalid Last-Event-ID", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Last-Event-ID", "invalid")
+
+		// and
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("uuid", uuid.New().String())
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		// and
+		sut := &jobController.EventsHandler{}
+
+		// when
+		sut.ServeHTTP(rec, req.WithContext(ctx))
+
+		// then
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("must be %d, but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("when service returns error", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		id := job.ID(uuid.New())
+
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("uuid", uuid.UUID(id).String())
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Subscribe(gomock.Eq(id)).
+			Times(1).
+			Return(make(chan job.LogEvent), func() {})
+		service.EXPECT().
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(nil, errors.New("test error"))
+
+		// and
+		sut := &jobController.EventsHandler{}
+		defer sut.SetService(service)()
+
+		// when
+		sut.ServeHTTP(rec, req.WithContext(ctx))
+
+		// then
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("must be %d, but got %d", http.StatusInternalServerError, rec.Code)
+		}
+	})
+}
+
+func TestWebSocketHandler_ServeHTTP(t *testing.T) {
+	// given
+	id := job.ID(uuid.New())
+
+	// and
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mock_job_service.NewMockService(ctrl)
+	service.EXPECT().
+		Subscribe(gomock.Eq(id)).
+		Times(1).
+		Return(make(chan job.LogEvent), func() {})
+	service.EXPECT().
+		FindBy(gomock.Eq(id)).
+		Times(1).
+		Return(&job.Job{
+			ID:       id,
+			Finished: true,
+		}, nil)
+	service.EXPECT().
+		FindLogs(gomock.Eq(id), gomock.Eq(0), gomock.Eq(-1)).
+		Times(1).
+		Return([]job.LogLine{
+			{Timestamp: time.Unix(1, 0).UTC(), Message: "Hello Test"},
+		}, nil)
+
+	// and
+	sut := &jobController.WebSocketHandler{}
+	defer sut.SetService(service)()
+
+	rtr := chi.NewRouter()
+	rtr.Get("/logs/{uuid}/ws", sut.ServeHTTP)
+
+	s := httptest.NewServer(rtr)
+	defer s.Close()
+
+	// and
+	url := strings.Replace(s.URL, "http", "ws", 1) + "/logs/" + uuid.UUID(id).String() + "/ws"
+	conn, err := websocket.Dial(url, "", s.URL)
+	if err != nil {
+		t.Fatalf("error must be nil, but got %+v", err)
+	}
+	defer conn.Close()
+
+	// when
+	var got []map[string]interface{}
+	for {
+		var msg map[string]interface{}
+		if err := websocket.JSON.Receive(conn, &msg); err != nil {
+			break
+		}
+		got = append(got, msg)
+	}
+
+	// then
+	want := []map[string]interface{}{
+		{"seq": float64(0), "time": "1970-01-01T00:00:01Z", "message": "Hello Test"},
+		{"finished": true},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+	}
+}
@@ -0,0 +1,85 @@
+package job
+
+import (
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/pkg/errors"
+	"net/http"
+	"strconv"
+)
+
+// errEnough tells that all lines in the selection were sent.
+var errEnough = errors.New("enough lines")
+
+// selection represents lines selected with query parameters.
+type selection struct {
+	match      func(job.LogLine) bool
+	from       int
+	to         int
+	tail       int
+	timestamps bool
+}
+
+// selectionOf returns a selection of lines in the query such as `?from=10&to=20&tail=5&timestamps=true`.
+// `from` and `to` are sequence numbers of the first and the last lines, and `tail` is the number of last lines.
+func selectionOf(r *http.Request) (selection, error) {
+	sel := selection{match: streamFilter(r), from: 0, to: -1}
+
+	query := r.URL.Query()
+	for _, param := range []struct {
+		name  string
+		value *int
+	}{
+		{"from", &sel.from},
+		{"to", &sel.to},
+		{"tail", &sel.tail},
+	} {
+		val := query.Get(param.name)
+		if len(val) == 0 {
+			continue
+		}
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 0 {
+			return selection{}, errors.Errorf("%s must be a non-negative integer, but got %s", param.name, val)
+		}
+		*param.value = n
+	}
+	if sel.to >= 0 && sel.to < sel.from {
+		return selection{}, errors.Errorf("to must not be less than from, but got %d < %d", sel.to, sel.from)
+	}
+
+	if val := query.Get("timestamps"); len(val) > 0 {
+		timestamps, err := strconv.ParseBool(val)
+		if err != nil {
+			return selection{}, errors.Errorf("timestamps must be a boolean, but got %s", val)
+		}
+		sel.timestamps = timestamps
+	}
+	return sel, nil
+}
+
+// end returns the sequence number after the last line, or negative number for the end of log.
+func (s selection) end() int {
+	if s.to < 0 {
+		return -1
+	}
+	return s.to + 1
+}
+
+// until returns a function sending lines until the last line in the selection. It returns errEnough after that.
+func (s selection) until(send func(job.LogEvent) error) func(job.LogEvent) error {
+	if s.to < 0 {
+		return send
+	}
+	return func(event job.LogEvent) error {
+		if event.Seq > s.to {
+			return errEnough
+		}
+		if err := send(event); err != nil {
+			return errors.WithStack(err)
+		}
+		if event.Seq == s.to {
+			return errEnough
+		}
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+package job
+
+import (
+	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+	"net/http"
+	"time"
+)
+
+type websocketHandler struct {
+	handler
+}
+
+// message is a log line sent through websocket
+type message struct {
+	Seq     int           `json:"seq"`
+	Time    time.Time     `json:"time"`
+	Stream  job.LogStream `json:"stream,omitempty"`
+	Message string        `json:"message"`
+}
+
+// finished is sent through websocket at end of job
+type finished struct {
+	Finished bool `json:"finished"`
+}
+
+// NewWebSocketHandler returns implement of job log over websocket
+func NewWebSocketHandler() (http.Handler, error) {
+	service, err := jobService.GetInstance()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &websocketHandler{handler{service: service}}, nil
+}
+
+// ServeHTTP upgrades the connection and sends log lines as json messages
+func (h *websocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "uuid"))
+	if err != nil {
+		http.Error(w, "Error occurred: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	match := streamFilter(r)
+	websocket.Server{Handler: func(conn *websocket.Conn) {
+		defer conn.Close()
+
+		err := h.stream(r.Context(), job.ID(id), -1, func(event job.LogEvent) error {
+			if !match(event.Line) {
+				return nil
+			}
+			return websocket.JSON.Send(conn, message{
+				Seq:     event.Seq,
+				Time:    event.Line.Timestamp,
+				Stream:  event.Line.Stream,
+				Message: event.Line.Message,
+			})
+		})
+		if err != nil {
+			logrus.Errorf("%+v", err)
+			return
+		}
+		if err := websocket.JSON.Send(conn, finished{Finished: true}); err != nil {
+			logrus.Errorf("%+v", err)
+		}
+	}}.ServeHTTP(w, r)
+}
@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"fmt"
+	"github.com/duck8823/duci/application/service/executor"
+	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+type cancelHandler struct {
+	service jobService.Service
+	cancel  func(job.ID) error
+}
+
+// NewCancelHandler returns implement of handler to cancel job
+func NewCancelHandler() (http.Handler, error) {
+	service, err := jobService.GetInstance()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &cancelHandler{service: service, cancel: executor.Cancel}, nil
+}
+
+// ServeHTTP cancels the queued or running job
+func (h *cancelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid job id: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.cancel(job.ID(id)); err == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	} else if err != executor.ErrNotRunning {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if _, err := h.service.FindBy(job.ID(id)); errors.Cause(err) == job.ErrNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Error(w, executor.ErrNotRunning.Error(), http.StatusConflict)
+}
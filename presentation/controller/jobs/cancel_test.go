@@ -0,0 +1,166 @@
+package jobs_test
+
+import (
+	"context"
+	"github.com/duck8823/duci/application/service/executor"
+	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/application/service/job/mock_job"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/internal/container"
+	"github.com/duck8823/duci/presentation/controller/jobs"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewCancelHandler(t *testing.T) {
+	t.Run("when there is service in container", func(t *testing.T) {
+		// given
+		container.Override(new(jobService.Service))
+		defer container.Clear()
+
+		// when
+		got, err := jobs.NewCancelHandler()
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if got == nil {
+			t.Error("must not be nil")
+		}
+	})
+
+	t.Run("when there are no service in container", func(t *testing.T) {
+		// given
+		container.Clear()
+
+		// when
+		got, err := jobs.NewCancelHandler()
+
+		// then
+		if err == nil {
+			t.Error("error must not be nil")
+		}
+
+		// and
+		if got != nil {
+			t.Errorf("must be nil, but got %+v", got)
+		}
+	})
+}
+
+func TestCancelHandler_ServeHTTP(t *testing.T) {
+	// where
+	for _, tt := range []struct {
+		name      string
+		cancelErr error
+		findTimes int
+		findErr   error
+		want      int
+	}{
+		{
+			name:      "when job is running",
+			cancelErr: nil,
+			findTimes: 0,
+			want:      http.StatusAccepted,
+		},
+		{
+			name:      "when job is already finished",
+			cancelErr: executor.ErrNotRunning,
+			findTimes: 1,
+			findErr:   nil,
+			want:      http.StatusConflict,
+		},
+		{
+			name:      "when job is not found",
+			cancelErr: executor.ErrNotRunning,
+			findTimes: 1,
+			findErr:   errors.WithStack(job.ErrNotFound),
+			want:      http.StatusNotFound,
+		},
+		{
+			name:      "when failed to find job",
+			cancelErr: executor.ErrNotRunning,
+			findTimes: 1,
+			findErr:   errors.New("test error"),
+			want:      http.StatusInternalServerError,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			id := job.ID(uuid.New())
+
+			// and
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("id", uuid.UUID(id).String())
+			ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/", nil).WithContext(ctx)
+
+			// and
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mock_job_service.NewMockService(ctrl)
+			service.EXPECT().
+				FindBy(gomock.Eq(id)).
+				Times(tt.findTimes).
+				Return(&job.Job{ID: id, Finished: true}, tt.findErr)
+
+			// and
+			var cancelled job.ID
+			sut := &jobs.CancelHandler{}
+			defer sut.SetService(service)()
+			defer sut.SetCancel(func(id job.ID) error {
+				cancelled = id
+				return tt.cancelErr
+			})()
+
+			// when
+			sut.ServeHTTP(rec, req)
+
+			// then
+			if rec.Code != tt.want {
+				t.Errorf("must be %d, but got %d", tt.want, rec.Code)
+			}
+
+			// and
+			if cancelled != id {
+				t.Errorf("must cancel %+v, but got %+v", id, cancelled)
+			}
+		})
+	}
+
+	t.Run("with invalid id", func(t *testing.T) {
+		// given
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", "invalid")
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/", nil).WithContext(ctx)
+
+		// and
+		sut := &jobs.CancelHandler{}
+		defer sut.SetCancel(func(job.ID) error {
+			t.Error("must not be called")
+			return nil
+		})()
+
+		// when
+		sut.ServeHTTP(rec, req)
+
+		// then
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("must be %d, but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
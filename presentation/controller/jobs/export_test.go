@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"github.com/duck8823/duci/application/service/executor"
+	"github.com/duck8823/duci/application/service/job"
+	domain "github.com/duck8823/duci/domain/model/job"
+)
+
+type Handler = handler
+
+func (h *Handler) SetService(service job.Service) (reset func()) {
+	tmp := h.service
+	h.service = service
+	return func() {
+		h.service = tmp
+	}
+}
+
+type CancelHandler = cancelHandler
+
+func (h *CancelHandler) SetService(service job.Service) (reset func()) {
+	tmp := h.service
+	h.service = service
+	return func() {
+		h.service = tmp
+	}
+}
+
+func (h *CancelHandler) SetCancel(cancel func(domain.ID) error) (reset func()) {
+	tmp := h.cancel
+	h.cancel = cancel
+	return func() {
+		h.cancel = tmp
+	}
+}
+
+type RerunHandler = rerunHandler
+
+func (h *RerunHandler) SetService(service job.Service) (reset func()) {
+	tmp := h.service
+	h.service = service
+	return func() {
+		h.service = tmp
+	}
+}
+
+func (h *RerunHandler) SetExecutor(executor executor.Executor) (reset func()) {
+	tmp := h.executor
+	h.executor = executor
+	return func() {
+		h.executor = tmp
+	}
+}
+
+type GCHandler = gcHandler
+
+func (h *GCHandler) SetService(service job.Service) (reset func()) {
+	tmp := h.service
+	h.service = service
+	return func() {
+		h.service = tmp
+	}
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"github.com/duck8823/duci/application"
+	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/pkg/errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type gcHandler struct {
+	service jobService.Service
+}
+
+// NewGCHandler returns implement of handler to remove jobs by retention
+func NewGCHandler() (http.Handler, error) {
+	service, err := jobService.GetInstance()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &gcHandler{service: service}, nil
+}
+
+// ServeHTTP removes jobs exceeding the retention, or only responses them with dry_run parameter
+func (h *gcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var dryRun bool
+	if val := r.URL.Query().Get("dry_run"); len(val) > 0 {
+		var err error
+		if dryRun, err = strconv.ParseBool(val); err != nil {
+			http.Error(w, "invalid dry_run: "+val, http.StatusBadRequest)
+			return
+		}
+	}
+
+	garbage, err := h.service.Collect(application.Config.RetentionPolicy(), time.Now(), dryRun)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := &collected{DryRun: dryRun, Jobs: []*removed{}}
+	for _, g := range garbage {
+		resp.Jobs = append(resp.Jobs, &removed{summary: summaryOf(&g.Job), Size: g.Size, Reason: g.Reason})
+		resp.Size += g.Size
+	}
+	respond(w, resp)
+}
@@ -0,0 +1,177 @@
+package jobs_test
+
+import (
+	"encoding/json"
+	"github.com/duck8823/duci/application"
+	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/application/service/job/mock_job"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/internal/container"
+	"github.com/duck8823/duci/presentation/controller/jobs"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewGCHandler(t *testing.T) {
+	t.Run("when there is service in container", func(t *testing.T) {
+		// given
+		container.Override(new(jobService.Service))
+		defer container.Clear()
+
+		// when
+		got, err := jobs.NewGCHandler()
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if got == nil {
+			t.Error("must not be nil")
+		}
+	})
+
+	t.Run("when there are no service in container", func(t *testing.T) {
+		// given
+		container.Clear()
+
+		// when
+		got, err := jobs.NewGCHandler()
+
+		// then
+		if err == nil {
+			t.Error("error must not be nil")
+		}
+
+		// and
+		if got != nil {
+			t.Errorf("must be nil, but got %+v", got)
+		}
+	})
+}
+
+func TestGCHandler_ServeHTTP(t *testing.T) {
+	t.Run("with dry run", func(t *testing.T) {
+		// given
+		tmp := application.Config.Retention
+		application.Config.Retention = &application.Retention{MaxJobs: 10}
+		defer func() {
+			application.Config.Retention = tmp
+		}()
+
+		// and
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/gc?dry_run=true", nil)
+
+		// and
+		id := job.ID(uuid.New())
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Collect(gomock.Eq(job.Retention{MaxJobs: 10}), gomock.Any(), gomock.Eq(true)).
+			Times(1).
+			Return([]job.Garbage{
+				{Job: job.Job{ID: id, Finished: true}, Size: 100, Reason: "more than 10 jobs in duck8823/duci"},
+			}, nil)
+
+		// and
+		sut := &jobs.GCHandler{}
+		defer sut.SetService(service)()
+
+		// when
+		sut.ServeHTTP(rec, req)
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+
+		// and
+		got := make(map[string]interface{})
+		if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+			t.Fatalf("error occurred: %+v", err)
+		}
+		want := map[string]interface{}{
+			"dryRun": true,
+			"jobs": []interface{}{
+				map[string]interface{}{
+					"id":       id.String(),
+					"finished": true,
+					"size":     float64(100),
+					"reason":   "more than 10 jobs in duck8823/duci",
+				},
+			},
+			"size": float64(100),
+		}
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	// where
+	for _, tt := range []struct {
+		name       string
+		query      string
+		collectErr error
+		times      int
+		want       int
+	}{
+		{
+			name:  "without dry run",
+			query: "",
+			times: 1,
+			want:  http.StatusOK,
+		},
+		{
+			name:  "with invalid dry run",
+			query: "?dry_run=maybe",
+			times: 0,
+			want:  http.StatusBadRequest,
+		},
+		{
+			name:       "when service returns error",
+			query:      "?dry_run=false",
+			collectErr: errors.New("test error"),
+			times:      1,
+			want:       http.StatusInternalServerError,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/gc"+tt.query, nil)
+
+			// and
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mock_job_service.NewMockService(ctrl)
+			service.EXPECT().
+				Collect(gomock.Any(), gomock.Any(), gomock.Eq(false)).
+				Times(tt.times).
+				Return([]job.Garbage{}, tt.collectErr)
+
+			// and
+			sut := &jobs.GCHandler{}
+			defer sut.SetService(service)()
+
+			// when
+			sut.ServeHTTP(rec, req)
+
+			// then
+			if rec.Code != tt.want {
+				t.Errorf("must be %d, but got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type handler struct {
+	service jobService.Service
+}
+
+// NewHandler returns implement of jobs API
+func NewHandler() (http.Handler, error) {
+	service, err := jobService.GetInstance()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &handler{service: service}, nil
+}
+
+// ServeHTTP responses a job if id is specified, otherwise list of jobs
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if len(chi.URLParam(r, "id")) > 0 {
+		h.Show(w, r)
+		return
+	}
+	h.List(w, r)
+}
+
+// List responses jobs matching the query parameters
+func (h *handler) List(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.service.Search(query)
+	if errors.Cause(err) == job.ErrInvalidCursor {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := &list{Jobs: []*summary{}, Next: page.Next}
+	for i := range page.Jobs {
+		resp.Jobs = append(resp.Jobs, summaryOf(&page.Jobs[i]))
+	}
+	respond(w, resp)
+}
+
+// Show responses metadata of the job without log stream
+func (h *handler) Show(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid job id: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	j, err := h.service.FindBy(job.ID(id))
+	if errors.Cause(err) == job.ErrNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respond(w, summaryOf(j))
+}
+
+// parseQuery returns a query of jobs from request parameters
+func parseQuery(r *http.Request) (job.Query, error) {
+	params := r.URL.Query()
+	query := job.Query{
+		Repository: params.Get("repository"),
+		Ref:        params.Get("ref"),
+		SHA:        params.Get("sha"),
+		State:      job.State(params.Get("state")),
+		Cursor:     params.Get("cursor"),
+		Limit:      defaultLimit,
+	}
+
+	if len(query.State) > 0 && !query.State.IsValid() {
+		return query, errors.Errorf("invalid state: %s", query.State)
+	}
+	if len(query.Ref) > 0 && len(query.Repository) == 0 && len(query.SHA) == 0 {
+		return query, errors.New("ref must be specified with repository or sha")
+	}
+	if val := params.Get("since"); len(val) > 0 {
+		since, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			return query, errors.Errorf("invalid since: %s", val)
+		}
+		query.Since = since
+	}
+	if val := params.Get("until"); len(val) > 0 {
+		until, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			return query, errors.Errorf("invalid until: %s", val)
+		}
+		query.Until = until
+	}
+	if val := params.Get("limit"); len(val) > 0 {
+		limit, err := strconv.Atoi(val)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return query, errors.Errorf("limit must be between 1 and %d, but got %s", maxLimit, val)
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
+
+func respond(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logrus.Errorf("%+v", err)
+	}
+}
@@ -0,0 +1,355 @@
+package jobs_test
+
+import (
+	"context"
+	"encoding/json"
+	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/application/service/job/mock_job"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/internal/container"
+	"github.com/duck8823/duci/presentation/controller/jobs"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewHandler(t *testing.T) {
+	t.Run("when there is service in container", func(t *testing.T) {
+		// given
+		service := new(jobService.Service)
+
+		container.Override(service)
+		defer container.Clear()
+
+		// and
+		want := &jobs.Handler{}
+		defer want.SetService(*service)()
+
+		// when
+		got, err := jobs.NewHandler()
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		opts := cmp.Options{
+			cmp.AllowUnexported(jobs.Handler{}),
+		}
+		if !cmp.Equal(got, want, opts) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want, opts))
+		}
+	})
+
+	t.Run("when there are no service in container", func(t *testing.T) {
+		// given
+		container.Clear()
+
+		// when
+		got, err := jobs.NewHandler()
+
+		// then
+		if err == nil {
+			t.Error("error must not be nil")
+		}
+
+		// and
+		if got != nil {
+			t.Errorf("must be nil, but got %+v", got)
+		}
+	})
+}
+
+func TestHandler_List(t *testing.T) {
+	t.Run("with query parameters", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/jobs?repository=duck8823/duci&ref=refs/heads/master&sha=abc&state=success&since=2020-01-01T00:00:00Z&until=2020-01-02T00:00:00Z&cursor=next&limit=5", nil)
+
+		// and
+		id := job.ID(uuid.New())
+		queuedAt := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Search(gomock.Eq(job.Query{
+				Repository: "duck8823/duci",
+				Ref:        "refs/heads/master",
+				SHA:        "abc",
+				State:      job.SUCCESS,
+				Since:      time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				Until:      time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+				Cursor:     "next",
+				Limit:      5,
+			})).
+			Times(1).
+			Return(&job.Page{
+				Jobs: []job.Job{{
+					ID:       id,
+					Trigger:  &job.Trigger{Repository: "duck8823/duci"},
+					State:    job.SUCCESS,
+					QueuedAt: &queuedAt,
+					Finished: true,
+				}},
+				Next: "cursor",
+			}, nil)
+
+		// and
+		sut := &jobs.Handler{}
+		defer sut.SetService(service)()
+
+		// when
+		sut.ServeHTTP(rec, req)
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+
+		// and
+		got := map[string]interface{}{}
+		if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+
+		want := map[string]interface{}{
+			"jobs": []interface{}{
+				map[string]interface{}{
+					"id":       uuid.UUID(id).String(),
+					"trigger":  map[string]interface{}{"repository": "duck8823/duci", "ref": "", "sha": "", "event": "", "taskName": "", "targetUrl": ""},
+					"state":    "success",
+					"queuedAt": "2020-01-01T12:00:00Z",
+					"finished": true,
+				},
+			},
+			"next": "cursor",
+		}
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("with invalid parameters", func(t *testing.T) {
+		// where
+		for _, tt := range []struct {
+			name string
+			url  string
+		}{
+			{name: "invalid since", url: "/jobs?since=yesterday"},
+			{name: "invalid until", url: "/jobs?until=tomorrow"},
+			{name: "non-numeric limit", url: "/jobs?limit=many"},
+			{name: "too large limit", url: "/jobs?limit=1000"},
+			{name: "unknown state", url: "/jobs?state=bogus"},
+			{name: "ref without repository", url: "/jobs?ref=refs/heads/master"},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				// given
+				rec := httptest.NewRecorder()
+				req := httptest.NewRequest("GET", tt.url, nil)
+
+				// and
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				service := mock_job_service.NewMockService(ctrl)
+				service.EXPECT().
+					Search(gomock.Any()).
+					Times(0)
+
+				// and
+				sut := &jobs.Handler{}
+				defer sut.SetService(service)()
+
+				// when
+				sut.List(rec, req)
+
+				// then
+				if rec.Code != http.StatusBadRequest {
+					t.Errorf("must be %d, but got %d", http.StatusBadRequest, rec.Code)
+				}
+			})
+		}
+	})
+
+	t.Run("when service returns error", func(t *testing.T) {
+		// where
+		for _, tt := range []struct {
+			name string
+			err  error
+			want int
+		}{
+			{name: "invalid cursor", err: errors.WithStack(job.ErrInvalidCursor), want: http.StatusBadRequest},
+			{name: "other error", err: errors.New("test error"), want: http.StatusInternalServerError},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				// given
+				rec := httptest.NewRecorder()
+				req := httptest.NewRequest("GET", "/jobs", nil)
+
+				// and
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				service := mock_job_service.NewMockService(ctrl)
+				service.EXPECT().
+					Search(gomock.Any()).
+					Times(1).
+					Return(nil, tt.err)
+
+				// and
+				sut := &jobs.Handler{}
+				defer sut.SetService(service)()
+
+				// when
+				sut.List(rec, req)
+
+				// then
+				if rec.Code != tt.want {
+					t.Errorf("must be %d, but got %d", tt.want, rec.Code)
+				}
+			})
+		}
+	})
+}
+
+func TestHandler_Show(t *testing.T) {
+	t.Run("when job is found", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+
+		// and
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", uuid.UUID(id).String())
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(&job.Job{
+				ID:       id,
+				State:    job.RUNNING,
+				Finished: false,
+			}, nil)
+
+		// and
+		sut := &jobs.Handler{}
+		defer sut.SetService(service)()
+
+		// when
+		sut.ServeHTTP(rec, req)
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+
+		// and
+		got := map[string]interface{}{}
+		if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+
+		want := map[string]interface{}{
+			"id":       uuid.UUID(id).String(),
+			"state":    "running",
+			"finished": false,
+		}
+		if !cmp.Equal(got, want) {
+			t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+		}
+	})
+
+	t.Run("with invalid id", func(t *testing.T) {
+		// given
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", "invalid")
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			FindBy(gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &jobs.Handler{}
+		defer sut.SetService(service)()
+
+		// when
+		sut.Show(rec, req)
+
+		// then
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("must be %d, but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("when service returns error", func(t *testing.T) {
+		// where
+		for _, tt := range []struct {
+			name string
+			err  error
+			want int
+		}{
+			{name: "not found", err: errors.WithStack(job.ErrNotFound), want: http.StatusNotFound},
+			{name: "other error", err: errors.New("test error"), want: http.StatusInternalServerError},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				// given
+				routeCtx := chi.NewRouteContext()
+				routeCtx.URLParams.Add("id", uuid.New().String())
+				ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+				rec := httptest.NewRecorder()
+				req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+
+				// and
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				service := mock_job_service.NewMockService(ctrl)
+				service.EXPECT().
+					FindBy(gomock.Any()).
+					Times(1).
+					Return(nil, tt.err)
+
+				// and
+				sut := &jobs.Handler{}
+				defer sut.SetService(service)()
+
+				// when
+				sut.Show(rec, req)
+
+				// then
+				if rec.Code != tt.want {
+					t.Errorf("must be %d, but got %d", tt.want, rec.Code)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/application/duci"
+	"github.com/duck8823/duci/application/service/executor"
+	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"net/url"
+)
+
+type rerunHandler struct {
+	service  jobService.Service
+	executor executor.Executor
+}
+
+// NewRerunHandler returns implement of handler to rerun job
+func NewRerunHandler() (http.Handler, error) {
+	service, err := jobService.GetInstance()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	executor, err := duci.New()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &rerunHandler{service: service, executor: executor}, nil
+}
+
+// ServeHTTP rebuilds the finished job under a new job id
+func (h *rerunHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid job id: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	original, err := h.service.FindBy(job.ID(id))
+	if errors.Cause(err) == job.ErrNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	newID := job.ID(uuid.New())
+	targetURL := &url.URL{Scheme: "http", Host: r.Host, Path: fmt.Sprintf("/ui/jobs/%s", newID.ToSlice())}
+	if r.URL.Scheme != "" {
+		targetURL.Scheme = r.URL.Scheme
+	}
+	buildJob, tgt, err := application.RerunJob(newID, original, targetURL)
+	if err == application.ErrNotRerunnable {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx := application.ContextWithJob(context.Background(), buildJob)
+
+	go func() {
+		if err := h.executor.Execute(ctx, tgt, buildJob.Command...); err != nil {
+			logrus.Errorf("%+v", err)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/jobs/%s", newID))
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(&rerun{ID: newID.String(), RerunOf: original.ID.String()}); err != nil {
+		logrus.Errorf("%+v", err)
+	}
+}
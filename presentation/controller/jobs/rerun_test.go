@@ -0,0 +1,243 @@
+package jobs_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/application/service/executor/mock_executor"
+	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/application/service/job/mock_job"
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/duck8823/duci/domain/model/job/target/github"
+	"github.com/duck8823/duci/internal/container"
+	"github.com/duck8823/duci/presentation/controller/jobs"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewRerunHandler(t *testing.T) {
+	t.Run("when there are job service and github in container", func(t *testing.T) {
+		// given
+		container.Override(new(jobService.Service))
+		container.Override(new(github.GitHub))
+		defer container.Clear()
+
+		// when
+		got, err := jobs.NewRerunHandler()
+
+		// then
+		if err != nil {
+			t.Errorf("error must be nil, but got %+v", err)
+		}
+
+		// and
+		if got == nil {
+			t.Error("must not be nil")
+		}
+	})
+
+	t.Run("when there are no service in container", func(t *testing.T) {
+		// given
+		container.Clear()
+
+		// when
+		got, err := jobs.NewRerunHandler()
+
+		// then
+		if err == nil {
+			t.Error("error must not be nil")
+		}
+
+		// and
+		if got != nil {
+			t.Errorf("must be nil, but got %+v", got)
+		}
+	})
+}
+
+func TestRerunHandler_ServeHTTP(t *testing.T) {
+	t.Run("when job is finished", func(t *testing.T) {
+		// given
+		id := job.ID(uuid.New())
+		original := &job.Job{
+			ID: id,
+			Trigger: &job.Trigger{
+				Repository: "duck8823/duci",
+				Ref:        "refs/heads/master",
+				SHA:        "aa218f56b14c9653891f9e74264a383fa43fefbd",
+				Event:      "issue_comment",
+				TaskName:   "duci/pr/test",
+				Source:     &job.Source{FullName: "duck8823/duci"},
+				Command:    []string{"test"},
+			},
+			State:    job.FAILURE,
+			Finished: true,
+		}
+
+		// and
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", id.String())
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/", nil).WithContext(ctx)
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			FindBy(gomock.Eq(id)).
+			Times(1).
+			Return(original, nil)
+
+		executed := make(chan *application.BuildJob, 1)
+		executor := mock_executor.NewMockExecutor(ctrl)
+		executor.EXPECT().
+			Execute(gomock.Any(), gomock.Any(), gomock.Eq("test")).
+			Times(1).
+			Do(func(ctx context.Context, _ job.Target, _ ...string) {
+				buildJob, _ := application.BuildJobFromContext(ctx)
+				executed <- buildJob
+			}).
+			Return(nil)
+
+		// and
+		sut := &jobs.RerunHandler{}
+		defer sut.SetService(service)()
+		defer sut.SetExecutor(executor)()
+
+		// when
+		sut.ServeHTTP(rec, req)
+
+		// then
+		if rec.Code != http.StatusAccepted {
+			t.Errorf("must be %d, but got %d", http.StatusAccepted, rec.Code)
+		}
+
+		// and
+		body := struct {
+			ID      string `json:"id"`
+			RerunOf string `json:"rerunOf"`
+		}{}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("error must not occur, but got %+v", err)
+		}
+
+		if body.RerunOf != id.String() {
+			t.Errorf("must be %s, but got %s", id, body.RerunOf)
+		}
+
+		// and
+		select {
+		case buildJob := <-executed:
+			if buildJob.ID.String() != body.ID {
+				t.Errorf("must be %s, but got %s", body.ID, buildJob.ID)
+			}
+
+			if buildJob.RerunOf == nil || *buildJob.RerunOf != id {
+				t.Errorf("must rerun of %s, but got %+v", id, buildJob.RerunOf)
+			}
+		case <-time.After(3 * time.Second):
+			t.Error("job must be executed")
+		}
+	})
+
+	// where
+	for _, tt := range []struct {
+		name    string
+		found   *job.Job
+		findErr error
+		want    int
+	}{
+		{
+			name:  "when job is running",
+			found: &job.Job{State: job.RUNNING},
+			want:  http.StatusConflict,
+		},
+		{
+			name:  "when job has no metadata",
+			found: &job.Job{Finished: true},
+			want:  http.StatusConflict,
+		},
+		{
+			name:    "when job is not found",
+			findErr: errors.WithStack(job.ErrNotFound),
+			want:    http.StatusNotFound,
+		},
+		{
+			name:    "when failed to find job",
+			findErr: errors.New("test error"),
+			want:    http.StatusInternalServerError,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			id := job.ID(uuid.New())
+
+			// and
+			routeCtx := chi.NewRouteContext()
+			routeCtx.URLParams.Add("id", id.String())
+			ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/", nil).WithContext(ctx)
+
+			// and
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := mock_job_service.NewMockService(ctrl)
+			service.EXPECT().
+				FindBy(gomock.Eq(id)).
+				Times(1).
+				Return(tt.found, tt.findErr)
+
+			executor := mock_executor.NewMockExecutor(ctrl)
+			executor.EXPECT().
+				Execute(gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(0)
+
+			// and
+			sut := &jobs.RerunHandler{}
+			defer sut.SetService(service)()
+			defer sut.SetExecutor(executor)()
+
+			// when
+			sut.ServeHTTP(rec, req)
+
+			// then
+			if rec.Code != tt.want {
+				t.Errorf("must be %d, but got %d", tt.want, rec.Code)
+			}
+		})
+	}
+
+	t.Run("with invalid id", func(t *testing.T) {
+		// given
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", "invalid")
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/", nil).WithContext(ctx)
+
+		// and
+		sut := &jobs.RerunHandler{}
+
+		// when
+		sut.ServeHTTP(rec, req)
+
+		// then
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("must be %d, but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"github.com/duck8823/duci/domain/model/job"
+	"github.com/google/uuid"
+	"time"
+)
+
+// summary represents metadata of job without log stream
+type summary struct {
+	ID         string       `json:"id"`
+	Trigger    *job.Trigger `json:"trigger,omitempty"`
+	State      job.State    `json:"state,omitempty"`
+	ExitCode   *int64       `json:"exitCode,omitempty"`
+	QueuedAt   *time.Time   `json:"queuedAt,omitempty"`
+	StartedAt  *time.Time   `json:"startedAt,omitempty"`
+	FinishedAt *time.Time   `json:"finishedAt,omitempty"`
+	Finished   bool         `json:"finished"`
+	Truncated  bool         `json:"truncated,omitempty"`
+}
+
+// list represents a page of jobs
+type list struct {
+	Jobs []*summary `json:"jobs"`
+	Next string     `json:"next,omitempty"`
+}
+
+func summaryOf(j *job.Job) *summary {
+	return &summary{
+		ID:         uuid.UUID(j.ID).String(),
+		Trigger:    j.Trigger,
+		State:      j.State,
+		ExitCode:   j.ExitCode,
+		QueuedAt:   j.QueuedAt,
+		StartedAt:  j.StartedAt,
+		FinishedAt: j.FinishedAt,
+		Finished:   j.Finished,
+		Truncated:  j.Truncated,
+	}
+}
+
+// rerun represents a job queued to rebuild the original one
+type rerun struct {
+	ID      string `json:"id"`
+	RerunOf string `json:"rerunOf"`
+}
+
+// removed represents a job removed by retention
+type removed struct {
+	*summary
+	Size   int64  `json:"size"`
+	Reason string `json:"reason"`
+}
+
+// collected represents jobs removed by retention and total size of them
+type collected struct {
+	DryRun bool       `json:"dryRun"`
+	Jobs   []*removed `json:"jobs"`
+	Size   int64      `json:"size"`
+}
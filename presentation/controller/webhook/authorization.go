@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/domain/model/job/target/github"
+	go_github "github.com/google/go-github/github"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+// authorizeCommenter returns error describes the reason if the commenter is not allowed to trigger build.
+func authorizeCommenter(ctx context.Context, event *go_github.IssueCommentEvent) error {
+	policy := application.Config.GitHub.Commenters
+	if !policy.IsRestricted() {
+		return nil
+	}
+
+	var reasons []string
+	user := event.GetComment().GetUser().GetLogin()
+	if len(policy.Users) > 0 {
+		for _, allowed := range policy.Users {
+			if strings.EqualFold(allowed, user) {
+				return nil
+			}
+		}
+		reasons = append(reasons, "not in the allow list")
+	}
+
+	gh, err := github.GetInstance()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, team := range policy.Teams {
+		member, err := gh.IsTeamMember(ctx, github.TeamName(team), user)
+		if err != nil {
+			logrus.Warnf("Failed to check membership of %s: %+v", team, err)
+			reasons = append(reasons, fmt.Sprintf("membership of team %s could not be checked", team))
+			continue
+		}
+		if member {
+			return nil
+		}
+		reasons = append(reasons, fmt.Sprintf("not in team %s", team))
+	}
+
+	if len(policy.Permission) > 0 {
+		perm, err := gh.GetPermissionLevel(ctx, event.GetRepo(), user)
+		if err != nil {
+			logrus.Warnf("Failed to get permission of %s: %+v", user, err)
+			reasons = append(reasons, "permission could not be checked")
+		} else if perm.Satisfies(github.Permission(policy.Permission)) {
+			return nil
+		} else {
+			reasons = append(reasons, fmt.Sprintf("not a collaborator with %s permission", policy.Permission))
+		}
+	}
+
+	return errors.Errorf("@%s is not allowed to trigger builds: %s.", user, strings.Join(reasons, ", "))
+}
+
+// replyIgnored tells the commenter that the build phrase was ignored, with a reaction or a comment.
+// The comment includes the reason unless Commenters.Reply is `comment`, not to disclose the policy publicly.
+func replyIgnored(ctx context.Context, event *go_github.IssueCommentEvent, phrase phrase, reason error) {
+	logrus.Infof("Ignored `ci %s` on %s#%d: %s", phrase, event.GetRepo().GetFullName(), event.GetIssue().GetNumber(), reason.Error())
+
+	gh, err := github.GetInstance()
+	if err != nil {
+		logrus.Errorf("%+v", err)
+		return
+	}
+
+	policy := application.Config.GitHub.Commenters
+	if policy.ReactsOnly() {
+		if err := gh.CreateCommentReaction(ctx, event.GetRepo(), event.GetComment().GetID(), "confused"); err != nil {
+			logrus.Warn(err)
+		}
+		return
+	}
+
+	body := fmt.Sprintf("Ignored `ci %s`: you are not allowed to trigger builds.", phrase)
+	if policy.RepliesReason() {
+		body = fmt.Sprintf("Ignored `ci %s`: %s", phrase, reason.Error())
+	}
+	if err := gh.CreateComment(ctx, event.GetRepo(), event.GetIssue().GetNumber(), body); err != nil {
+		logrus.Warn(err)
+	}
+}
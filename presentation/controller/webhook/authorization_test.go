@@ -0,0 +1,132 @@
+package webhook_test
+
+import (
+	"context"
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/domain/model/job/target/github"
+	"github.com/duck8823/duci/domain/model/job/target/github/mock_github"
+	"github.com/duck8823/duci/internal/container"
+	"github.com/duck8823/duci/presentation/controller/webhook"
+	"github.com/golang/mock/gomock"
+	go_github "github.com/google/go-github/github"
+	"github.com/pkg/errors"
+	"testing"
+)
+
+func TestAuthorizeCommenter(t *testing.T) {
+	// given
+	event := &go_github.IssueCommentEvent{
+		Repo: &go_github.Repository{
+			FullName: go_github.String("duck8823/duci"),
+		},
+		Comment: &go_github.IssueComment{
+			User: &go_github.User{
+				Login: go_github.String("octocat"),
+			},
+		},
+	}
+
+	// where
+	for _, tt := range []struct {
+		name    string
+		policy  *application.Commenters
+		given   func(gh *mock_github.MockGitHub)
+		wantErr string
+	}{
+		{
+			name:    "when policy is not configured",
+			policy:  nil,
+			given:   func(gh *mock_github.MockGitHub) {},
+			wantErr: "",
+		},
+		{
+			name:    "when commenter is in allowed users",
+			policy:  &application.Commenters{Users: []string{"OctoCat"}},
+			given:   func(gh *mock_github.MockGitHub) {},
+			wantErr: "",
+		},
+		{
+			name:   "when commenter is a member of allowed team",
+			policy: &application.Commenters{Users: []string{"duck8823"}, Teams: []string{"duck8823/maintainers"}},
+			given: func(gh *mock_github.MockGitHub) {
+				gh.EXPECT().
+					IsTeamMember(gomock.Any(), gomock.Eq(github.TeamName("duck8823/maintainers")), gomock.Eq("octocat")).
+					Times(1).
+					Return(true, nil)
+			},
+			wantErr: "",
+		},
+		{
+			name:    "when commenter is not in allowed users",
+			policy:  &application.Commenters{Users: []string{"duck8823"}},
+			given:   func(gh *mock_github.MockGitHub) {},
+			wantErr: "@octocat is not allowed to trigger builds: not in the allow list.",
+		},
+		{
+			name:   "when commenter has enough permission",
+			policy: &application.Commenters{Permission: "write"},
+			given: func(gh *mock_github.MockGitHub) {
+				gh.EXPECT().
+					GetPermissionLevel(gomock.Any(), gomock.Any(), gomock.Eq("octocat")).
+					Times(1).
+					Return(github.ADMIN, nil)
+			},
+			wantErr: "",
+		},
+		{
+			name:   "when commenter does not have enough permission",
+			policy: &application.Commenters{Teams: []string{"duck8823/maintainers"}, Permission: "write"},
+			given: func(gh *mock_github.MockGitHub) {
+				gh.EXPECT().
+					IsTeamMember(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(false, nil)
+				gh.EXPECT().
+					GetPermissionLevel(gomock.Any(), gomock.Any(), gomock.Eq("octocat")).
+					Times(1).
+					Return(github.READ, nil)
+			},
+			wantErr: "@octocat is not allowed to trigger builds: not in team duck8823/maintainers, not a collaborator with write permission.",
+		},
+		{
+			name:   "when failure to get permission",
+			policy: &application.Commenters{Permission: "write"},
+			given: func(gh *mock_github.MockGitHub) {
+				gh.EXPECT().
+					GetPermissionLevel(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(github.NONE, errors.New("test error"))
+			},
+			wantErr: "@octocat is not allowed to trigger builds: permission could not be checked.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			commenters := application.Config.GitHub.Commenters
+			application.Config.GitHub.Commenters = tt.policy
+			defer func() {
+				application.Config.GitHub.Commenters = commenters
+			}()
+
+			// and
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			gh := mock_github.NewMockGitHub(ctrl)
+			tt.given(gh)
+			container.Override(gh)
+			defer container.Clear()
+
+			// when
+			err := webhook.AuthorizeCommenter(context.Background(), event)
+
+			// then
+			if len(tt.wantErr) == 0 && err != nil {
+				t.Errorf("error must be nil, but got %+v", err)
+			}
+			if len(tt.wantErr) > 0 && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("error must be %s, but got %+v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package webhook
 
 import (
 	"github.com/duck8823/duci/application/service/executor"
+	jobService "github.com/duck8823/duci/application/service/job"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"net/url"
 	"reflect"
+	"time"
 )
 
 type Handler = handler
@@ -18,6 +20,22 @@ func (h *Handler) SetExecutor(executor executor.Executor) (reset func()) {
 	}
 }
 
+func (h *Handler) SetService(service jobService.Service) (reset func()) {
+	tmp := h.service
+	h.service = service
+	return func() {
+		h.service = tmp
+	}
+}
+
+func SetNowFunc(f func() time.Time) (reset func()) {
+	tmp := now
+	now = f
+	return func() {
+		now = tmp
+	}
+}
+
 func URLMust(url *url.URL, err error) *url.URL {
 	if err != nil {
 		panic(err)
@@ -48,3 +66,7 @@ func contains(s []string, e string) bool {
 	}
 	return false
 }
+
+var VerifySignature = verifySignature
+
+var AuthorizeCommenter = authorizeCommenter
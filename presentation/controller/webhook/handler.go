@@ -1,26 +1,43 @@
 package webhook
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/duck8823/duci/application"
 	"github.com/duck8823/duci/application/duci"
 	"github.com/duck8823/duci/application/service/executor"
+	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/domain/model/job"
 	"github.com/duck8823/duci/domain/model/job/target"
 	"github.com/duck8823/duci/domain/model/job/target/github"
+	"github.com/duck8823/duci/domain/model/task"
 	go_github "github.com/google/go-github/github"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/src-d/go-git.v4/plumbing"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"time"
 )
 
 // ErrSkipBuild represents error of skip build
 var ErrSkipBuild = errors.New("Skip build")
 
+// maxPayloadSize is the largest payload accepted, same as the limit of GitHub
+const maxPayloadSize = 25 << 20
+
+// errPayloadTooLarge represents error of payload exceeding maxPayloadSize
+var errPayloadTooLarge = errors.Errorf("payload must be at most %d bytes", maxPayloadSize)
+
+var now = time.Now
+
 type handler struct {
 	executor executor.Executor
+	service  jobService.Service
 }
 
 // NewHandler returns a implement of webhook handler
@@ -30,11 +47,32 @@ func NewHandler() (http.Handler, error) {
 		return nil, errors.WithStack(err)
 	}
 
-	return &handler{executor: executor}, nil
+	service, err := jobService.GetInstance()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &handler{executor: executor, service: service}, nil
 }
 
 // ServeHTTP receives github event
 func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, err := readPayload(r.Body)
+	if err == errPayloadTooLarge {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if secret := application.Config.GitHub.WebhookSecret.String(); len(secret) > 0 {
+		if err := verifySignature(secret, r.Header, payload); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(payload))
+
 	event := r.Header.Get("X-GitHub-Event")
 	switch event {
 	case "push":
@@ -50,6 +88,21 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// readPayload reads the whole body, or returns errPayloadTooLarge if it exceeds maxPayloadSize
+func readPayload(body io.Reader) ([]byte, error) {
+	if body == nil {
+		return []byte{}, nil
+	}
+	payload, err := ioutil.ReadAll(io.LimitReader(body, maxPayloadSize+1))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(payload) > maxPayloadSize {
+		return nil, errPayloadTooLarge
+	}
+	return payload, nil
+}
+
 // PushEvent receives github push event
 func (h *handler) PushEvent(w http.ResponseWriter, r *http.Request) {
 	event := &go_github.PushEvent{}
@@ -58,6 +111,12 @@ func (h *handler) PushEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	repo, err := application.Config.Repository(event.GetRepo().GetFullName())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	reqID, err := reqID(r)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -65,30 +124,27 @@ func (h *handler) PushEvent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	targetURL := targetURL(r)
-	targetURL.Path = fmt.Sprintf("/logs/%s", reqID.ToSlice())
-	ctx := application.ContextWithJob(context.Background(), &application.BuildJob{
+	targetURL.Path = fmt.Sprintf("/ui/jobs/%s", reqID.ToSlice())
+	buildJob := &application.BuildJob{
 		ID: reqID,
 		TargetSource: &github.TargetSource{
 			Repository: event.GetRepo(),
 			Ref:        event.GetRef(),
 			SHA:        plumbing.NewHash(event.GetHeadCommit().GetID()),
 		},
-		TaskName:  fmt.Sprintf("%s/push", application.Name),
+		TaskName:  fmt.Sprintf("%s/push", repo.ContextPrefix),
 		TargetURL: targetURL,
-	})
+		Event:     "push",
+		QueuedAt:  now(),
+		Source:    event.GetRepo(),
+	}
 
 	tgt := &target.GitHub{
 		Repo:  event.GetRepo(),
 		Point: event,
 	}
 
-	go func() {
-		if err := h.executor.Execute(ctx, tgt); err != nil {
-			logrus.Errorf("%+v", err)
-		}
-	}()
-
-	w.WriteHeader(http.StatusOK)
+	h.run(w, buildJob, tgt, repo, task.PushEventOf(event.GetRef()))
 }
 
 // IssueCommentEvent receives github issue comment event
@@ -99,6 +155,12 @@ func (h *handler) IssueCommentEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	repo, err := application.Config.Repository(event.GetRepo().GetFullName())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	if !isValidAction(event.Action) {
 		w.WriteHeader(http.StatusOK)
 		if _, err := w.Write([]byte("{\"message\":\"skip build\"}")); err != nil {
@@ -131,26 +193,35 @@ func (h *handler) IssueCommentEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := authorizeCommenter(context.Background(), event); err != nil {
+		replyIgnored(context.Background(), event, phrase, err)
+		http.Error(w, "commenter is not allowed to trigger builds", http.StatusForbidden)
+		return
+	}
+
+	if phrase == retryPhrase {
+		h.retry(w, r, reqID, event.GetRepo().GetFullName(), tgt.Point.GetHead())
+		return
+	}
+
 	targetURL := targetURL(r)
-	targetURL.Path = fmt.Sprintf("/logs/%s", reqID.ToSlice())
-	ctx := application.ContextWithJob(context.Background(), &application.BuildJob{
+	targetURL.Path = fmt.Sprintf("/ui/jobs/%s", reqID.ToSlice())
+	buildJob := &application.BuildJob{
 		ID: reqID,
 		TargetSource: &github.TargetSource{
 			Repository: event.GetRepo(),
 			Ref:        tgt.Point.GetRef(),
 			SHA:        plumbing.NewHash(tgt.Point.GetHead()),
 		},
-		TaskName:  fmt.Sprintf("%s/pr/%s", application.Name, phrase.Command().Slice()[0]),
+		TaskName:  fmt.Sprintf("%s/pr/%s", repo.ContextPrefix, phrase.Command().Slice()[0]),
 		TargetURL: targetURL,
-	})
-
-	go func() {
-		if err := h.executor.Execute(ctx, tgt, phrase.Command()...); err != nil {
-			logrus.Errorf("%+v", err)
-		}
-	}()
+		Event:     "issue_comment",
+		QueuedAt:  now(),
+		Source:    tgt.Repo,
+		Command:   phrase.Command(),
+	}
 
-	w.WriteHeader(http.StatusOK)
+	h.run(w, buildJob, tgt, repo, task.Event{Type: task.Comment, Name: string(phrase)})
 }
 
 // PullRequestEvent receives github pull request event
@@ -161,6 +232,12 @@ func (h *handler) PullRequestEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	repo, err := application.Config.Repository(event.GetRepo().GetFullName())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	if !(event.GetAction() == "opened" || event.GetAction() == "synchronize") {
 		w.WriteHeader(http.StatusOK)
 		if _, err := w.Write([]byte("{\"message\":\"skip build\"}")); err != nil {
@@ -186,23 +263,135 @@ func (h *handler) PullRequestEvent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	targetURL := targetURL(r)
-	targetURL.Path = fmt.Sprintf("/logs/%s", reqID.ToSlice())
-	ctx := application.ContextWithJob(context.Background(), &application.BuildJob{
+	targetURL.Path = fmt.Sprintf("/ui/jobs/%s", reqID.ToSlice())
+	buildJob := &application.BuildJob{
 		ID: reqID,
 		TargetSource: &github.TargetSource{
 			Repository: event.GetRepo(),
 			Ref:        tgt.Point.GetRef(),
 			SHA:        plumbing.NewHash(tgt.Point.GetHead()),
 		},
-		TaskName:  fmt.Sprintf("%s/pr", application.Name),
+		TaskName:  fmt.Sprintf("%s/pr", repo.ContextPrefix),
 		TargetURL: targetURL,
-	})
+		Event:     "pull_request",
+		QueuedAt:  now(),
+		Source:    tgt.Repo,
+	}
+
+	h.run(w, buildJob, tgt, repo, task.Event{Type: task.PullRequest, Name: event.GetAction()})
+}
+
+// run executes a pipeline of tasks triggered by the event and tasks they need, or the job of event if the repository declares no task.
+// A comment not matching any task runs the phrase as a command, and other events are skipped.
+func (h *handler) run(w http.ResponseWriter, buildJob *application.BuildJob, tgt *target.GitHub, repo *application.Repository, event task.Event) {
+	tasks, err := tasksOf(context.Background(), tgt.Repo, buildJob.TargetSource.GetSHA().String())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	names := tasks.Match(event)
+	if len(names) == 0 && len(tasks) > 0 && event.Type != task.Comment {
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write([]byte("{\"message\":\"skip build\"}")); err != nil {
+			logrus.Errorf("%+v", err)
+		}
+		return
+	}
 
+	if len(names) == 0 {
+		h.execute(buildJob, tgt, buildJob.Command...)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var stages []executor.Stage
+	pipeline := false
+	for _, name := range tasks.WithNeeds(names) {
+		stages = append(stages, stageOf(buildJob, repo, name, tasks[name]))
+		pipeline = pipeline || len(tasks[name].Needs) > 0
+	}
+	if pipeline {
+		shareAggregate(buildJob, repo, "pipeline", stages...)
+	}
+	h.executePipeline(stages, tgt)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// execute runs the job in background
+func (h *handler) execute(buildJob *application.BuildJob, tgt job.Target, cmd ...string) {
+	ctx := application.ContextWithJob(context.Background(), buildJob)
 	go func() {
-		if err := h.executor.Execute(ctx, tgt); err != nil {
+		if err := h.executor.Execute(ctx, tgt, cmd...); err != nil {
 			logrus.Errorf("%+v", err)
 		}
 	}()
+}
+
+// executePipeline runs the stages in background
+func (h *handler) executePipeline(stages []executor.Stage, tgt job.Target) {
+	go executor.ExecutePipeline(h.executor, tgt, stages)
+}
+
+// retry reruns the latest finished job of each task for the commit.
+// Ids of new jobs are derived from the id of event and the task name.
+// All reruns are built before any of them starts, so that a failure does not leave some of them running when the event is redelivered.
+func (h *handler) retry(w http.ResponseWriter, r *http.Request, id job.ID, repository string, sha string) {
+	var originals []*job.Job
+	retried := map[string]bool{}
+	query := job.Query{Repository: repository, SHA: sha, Limit: 100}
+	for {
+		page, err := h.service.Search(query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// jobs are in descending order of queued time, so the first one of each task is the latest
+		for i := range page.Jobs {
+			original := &page.Jobs[i]
+			if !original.IsRerunnable() || retried[original.Trigger.TaskName] {
+				continue
+			}
+			retried[original.Trigger.TaskName] = true
+			originals = append(originals, original)
+		}
+		if len(page.Next) == 0 {
+			break
+		}
+		query.Cursor = page.Next
+	}
+	if len(originals) == 0 {
+		http.Error(w, fmt.Sprintf("no job to retry for %s", sha), http.StatusNotFound)
+		return
+	}
+
+	type rerun struct {
+		buildJob *application.BuildJob
+		target   job.Target
+	}
+	var reruns []rerun
+	for _, original := range originals {
+		rerunID := job.ID(uuid.NewSHA1(uuid.UUID(id), []byte(original.Trigger.TaskName)))
+		targetURL := targetURL(r)
+		targetURL.Path = fmt.Sprintf("/ui/jobs/%s", rerunID.ToSlice())
+		buildJob, tgt, err := application.RerunJob(rerunID, original, targetURL)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		reruns = append(reruns, rerun{buildJob: buildJob, target: tgt})
+	}
+
+	for _, rerun := range reruns {
+		ctx := application.ContextWithJob(context.Background(), rerun.buildJob)
+		tgt, cmd := rerun.target, rerun.buildJob.Command
+		go func() {
+			if err := h.executor.Execute(ctx, tgt, cmd...); err != nil {
+				logrus.Errorf("%+v", err)
+			}
+		}()
+	}
 
 	w.WriteHeader(http.StatusOK)
 }
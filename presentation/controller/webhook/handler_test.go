@@ -1,12 +1,15 @@
 package webhook_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/duck8823/duci/application"
 	"github.com/duck8823/duci/application/service/executor/mock_executor"
 	jobService "github.com/duck8823/duci/application/service/job"
+	"github.com/duck8823/duci/application/service/job/mock_job"
 	"github.com/duck8823/duci/domain/model/job"
 	"github.com/duck8823/duci/domain/model/job/target/github"
 	"github.com/duck8823/duci/domain/model/job/target/github/mock_github"
@@ -14,16 +17,19 @@ import (
 	"github.com/duck8823/duci/presentation/controller/webhook"
 	"github.com/golang/mock/gomock"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	go_github "github.com/google/go-github/github"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"gopkg.in/src-d/go-git.v4/plumbing"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -83,6 +89,8 @@ func TestNewHandler(t *testing.T) {
 	})
 }
 
+var queuedAt = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
 func TestHandler_ServeHTTP(t *testing.T) {
 	for _, tt := range []struct {
 		event   string
@@ -117,6 +125,11 @@ func TestHandler_ServeHTTP(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
+			gh := mock_github.NewMockGitHub(ctrl)
+			expectNoTasks(gh)
+			container.Override(gh)
+			defer container.Clear()
+
 			executor := mock_executor.NewMockExecutor(ctrl)
 			executor.EXPECT().
 				Execute(gomock.Any(), gomock.Any()).
@@ -171,6 +184,7 @@ func TestHandler_ServeHTTP(t *testing.T) {
 					SHA: go_github.String("aa218f56b14c9653891f9e74264a383fa43fefbd"),
 				},
 			}, nil)
+		expectNoTasks(gh)
 		container.Override(gh)
 		defer container.Clear()
 
@@ -213,9 +227,214 @@ func TestHandler_ServeHTTP(t *testing.T) {
 			t.Errorf("response code must be %d, but got %d", http.StatusBadRequest, rec.Code)
 		}
 	})
+
+	t.Run("when payload is too large", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/", bytes.NewReader(bytes.Repeat([]byte(" "), 25<<20+1)))
+
+		// and
+		req.Header.Set("X-GitHub-Event", "push")
+		req.Header.Set("X-GitHub-Delivery", "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+
+		// and
+		sut := &webhook.Handler{}
+
+		// when
+		sut.ServeHTTP(rec, req)
+
+		// then
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("response code must be %d, but got %d", http.StatusRequestEntityTooLarge, rec.Code)
+		}
+	})
+
+	t.Run("when failed to read payload", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/", &errorReader{})
+
+		// and
+		req.Header.Set("X-GitHub-Event", "push")
+		req.Header.Set("X-GitHub-Delivery", "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+
+		// and
+		sut := &webhook.Handler{}
+
+		// when
+		sut.ServeHTTP(rec, req)
+
+		// then
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("response code must be %d, but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("with webhook secret", func(t *testing.T) {
+		// given
+		secret := application.Config.GitHub.WebhookSecret
+		application.Config.GitHub.WebhookSecret = "It's a Secret to Everybody"
+		defer func() {
+			application.Config.GitHub.WebhookSecret = secret
+		}()
+
+		// and
+		payload, err := ioutil.ReadFile("testdata/push.correct.json")
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+
+		t.Run("when signature is correct", func(t *testing.T) {
+			// given
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(payload))
+
+			// and
+			req.Header.Set("X-GitHub-Event", "push")
+			req.Header.Set("X-GitHub-Delivery", "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+			req.Header.Set("X-Hub-Signature-256", sign(sha256.New, "sha256=", "It's a Secret to Everybody", payload))
+
+			// and
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			gh := mock_github.NewMockGitHub(ctrl)
+			expectNoTasks(gh)
+			container.Override(gh)
+			defer container.Clear()
+
+			executor := mock_executor.NewMockExecutor(ctrl)
+			executor.EXPECT().
+				Execute(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return(nil)
+
+			// and
+			sut := &webhook.Handler{}
+			reset := sut.SetExecutor(executor)
+			defer func() {
+				time.Sleep(10 * time.Millisecond) // for goroutine
+				reset()
+			}()
+
+			// when
+			sut.ServeHTTP(rec, req)
+
+			// then
+			if rec.Code != http.StatusOK {
+				t.Errorf("response code must be %d, but got %d", http.StatusOK, rec.Code)
+			}
+		})
+
+		t.Run("when signature is wrong", func(t *testing.T) {
+			// given
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(payload))
+
+			// and
+			req.Header.Set("X-GitHub-Event", "push")
+			req.Header.Set("X-GitHub-Delivery", "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+			req.Header.Set("X-Hub-Signature-256", sign(sha256.New, "sha256=", "wrong secret", payload))
+
+			// and
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			executor := mock_executor.NewMockExecutor(ctrl)
+			executor.EXPECT().
+				Execute(gomock.Any(), gomock.Any()).
+				Times(0)
+
+			// and
+			sut := &webhook.Handler{}
+			defer sut.SetExecutor(executor)()
+
+			// when
+			sut.ServeHTTP(rec, req)
+
+			// then
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("response code must be %d, but got %d", http.StatusUnauthorized, rec.Code)
+			}
+		})
+
+		t.Run("when signature is missing", func(t *testing.T) {
+			// given
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(payload))
+
+			// and
+			req.Header.Set("X-GitHub-Event", "push")
+			req.Header.Set("X-GitHub-Delivery", "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+
+			// and
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			executor := mock_executor.NewMockExecutor(ctrl)
+			executor.EXPECT().
+				Execute(gomock.Any(), gomock.Any()).
+				Times(0)
+
+			// and
+			sut := &webhook.Handler{}
+			defer sut.SetExecutor(executor)()
+
+			// when
+			sut.ServeHTTP(rec, req)
+
+			// then
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("response code must be %d, but got %d", http.StatusUnauthorized, rec.Code)
+			}
+		})
+
+		t.Run("when payload is too large", func(t *testing.T) {
+			// given
+			large := bytes.Repeat([]byte(" "), 25<<20+1)
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(large))
+
+			// and
+			req.Header.Set("X-GitHub-Event", "push")
+			req.Header.Set("X-GitHub-Delivery", "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+			req.Header.Set("X-Hub-Signature-256", sign(sha256.New, "sha256=", "It's a Secret to Everybody", large))
+
+			// and
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			executor := mock_executor.NewMockExecutor(ctrl)
+			executor.EXPECT().
+				Execute(gomock.Any(), gomock.Any()).
+				Times(0)
+
+			// and
+			sut := &webhook.Handler{}
+			defer sut.SetExecutor(executor)()
+
+			// when
+			sut.ServeHTTP(rec, req)
+
+			// then
+			if rec.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("response code must be %d, but got %d", http.StatusRequestEntityTooLarge, rec.Code)
+			}
+		})
+	})
+}
+
+type errorReader struct{}
+
+func (*errorReader) Read([]byte) (int, error) {
+	return 0, errors.New("test error")
 }
 
 func TestHandler_PushEvent(t *testing.T) {
+	defer webhook.SetNowFunc(func() time.Time {
+		return queuedAt
+	})()
+
 	t.Run("with no error", func(t *testing.T) {
 		// given
 		rec := httptest.NewRecorder()
@@ -237,6 +456,11 @@ func TestHandler_PushEvent(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
+		gh := mock_github.NewMockGitHub(ctrl)
+		expectNoTasks(gh)
+		container.Override(gh)
+		defer container.Clear()
+
 		executor := mock_executor.NewMockExecutor(ctrl)
 		executor.EXPECT().
 			Execute(gomock.Any(), gomock.Any()).
@@ -260,7 +484,15 @@ func TestHandler_PushEvent(t *testing.T) {
 						SHA: plumbing.ZeroHash,
 					},
 					TaskName:  "duci/push",
-					TargetURL: webhook.URLMust(url.Parse("http://example.com/logs/72d3162e-cc78-11e3-81ab-4c9367dc0958")),
+					TargetURL: webhook.URLMust(url.Parse("http://example.com/ui/jobs/72d3162e-cc78-11e3-81ab-4c9367dc0958")),
+					Event:     "push",
+					QueuedAt:  queuedAt,
+					Source: &go_github.PushEventRepository{
+						ID:       go_github.Int64(135493233),
+						FullName: go_github.String("Codertocat/Hello-World"),
+						SSHURL:   go_github.String("git@github.com:Codertocat/Hello-World.git"),
+						CloneURL: go_github.String("https://github.com/Codertocat/Hello-World.git"),
+					},
 				}
 
 				opt := cmp.Options{
@@ -296,14 +528,14 @@ func TestHandler_PushEvent(t *testing.T) {
 		}
 	})
 
-	t.Run("when url param is invalid format uuid", func(t *testing.T) {
+	t.Run("with tasks", func(t *testing.T) {
 		// given
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest("GET", "/", nil)
 
 		// and
 		req.Header = http.Header{
-			"X-Github-Delivery": []string{"invalid format"},
+			"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
 		}
 
 		// and
@@ -313,29 +545,101 @@ func TestHandler_PushEvent(t *testing.T) {
 		}
 		req.Body = f
 
+		// and
+		config := `
+tasks:
+  release:
+    command: [make, release]
+    timeout: 60
+    triggers:
+      tag: ["simple-*"]
+  test:
+    command: [make, test]
+    triggers:
+      push: [master]
+`
+
 		// and
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
+		gh := mock_github.NewMockGitHub(ctrl)
+		gh.EXPECT().
+			GetContent(gomock.Any(), gomock.Any(), gomock.Eq(plumbing.ZeroHash.String()), gomock.Eq(".duci/config.yml")).
+			Times(1).
+			Return([]byte(config), nil)
+		container.Override(gh)
+		defer container.Clear()
+
+		// and
+		id := job.ID(uuid.NewSHA1(uuid.Must(uuid.Parse("72d3162e-cc78-11e3-81ab-4c9367dc0958")), []byte("release")))
+
 		executor := mock_executor.NewMockExecutor(ctrl)
 		executor.EXPECT().
-			Execute(gomock.Any(), gomock.Any()).
-			Times(0)
+			Execute(gomock.Any(), gomock.Any(), gomock.Eq("make"), gomock.Eq("release")).
+			Times(1).
+			Do(func(ctx context.Context, target job.Target, cmd ...string) {
+				got, err := application.BuildJobFromContext(ctx)
+				if err != nil {
+					t.Errorf("must not be nil, but got %+v", err)
+				}
+
+				want := &application.BuildJob{
+					ID: id,
+					TargetSource: &github.TargetSource{
+						Repository: &go_github.PushEventRepository{
+							ID:       go_github.Int64(135493233),
+							FullName: go_github.String("Codertocat/Hello-World"),
+							SSHURL:   go_github.String("git@github.com:Codertocat/Hello-World.git"),
+							CloneURL: go_github.String("https://github.com/Codertocat/Hello-World.git"),
+						},
+						Ref: "refs/tags/simple-tag",
+						SHA: plumbing.ZeroHash,
+					},
+					TaskName:  "duci/release",
+					TargetURL: webhook.URLMust(url.Parse(fmt.Sprintf("http://example.com/ui/jobs/%s", id.ToSlice()))),
+					Event:     "push",
+					QueuedAt:  queuedAt,
+					Source: &go_github.PushEventRepository{
+						ID:       go_github.Int64(135493233),
+						FullName: go_github.String("Codertocat/Hello-World"),
+						SSHURL:   go_github.String("git@github.com:Codertocat/Hello-World.git"),
+						CloneURL: go_github.String("https://github.com/Codertocat/Hello-World.git"),
+					},
+					Command: []string{"make", "release"},
+					Task:    "release",
+					Timeout: 60 * time.Second,
+				}
+
+				opt := cmp.Options{
+					webhook.CmpOptsAllowFields(go_github.PushEventRepository{}, "ID", "FullName", "SSHURL", "CloneURL"),
+					cmp.AllowUnexported(application.BuildJob{}),
+				}
+
+				if !cmp.Equal(got, want, opt) {
+					t.Errorf("must be equal but: %+v", cmp.Diff(got, want, opt))
+				}
+			}).
+			Return(nil)
 
 		// and
 		sut := &webhook.Handler{}
-		defer sut.SetExecutor(executor)()
+		reset := sut.SetExecutor(executor)
+		defer func() {
+			time.Sleep(10 * time.Millisecond) // for goroutine
+			reset()
+		}()
 
 		// when
 		sut.PushEvent(rec, req)
 
 		// then
-		if rec.Code != http.StatusBadRequest {
-			t.Errorf("response code must be %d, but got %d", http.StatusBadRequest, rec.Code)
+		if rec.Code != http.StatusOK {
+			t.Errorf("response code must be %d, but got %d", http.StatusOK, rec.Code)
 		}
 	})
 
-	t.Run("with invalid payload", func(t *testing.T) {
+	t.Run("with matrix", func(t *testing.T) {
 		// given
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest("GET", "/", nil)
@@ -346,35 +650,812 @@ func TestHandler_PushEvent(t *testing.T) {
 		}
 
 		// and
-		req.Body = ioutils.NewReadCloserWrapper(strings.NewReader("invalid payload"), func() error {
-			return nil
-		})
+		f, err := os.Open("testdata/push.correct.json")
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		req.Body = f
+
+		// and
+		config := `
+tasks:
+  test:
+    command: [make, test]
+    triggers:
+      tag: ["*"]
+    matrix:
+      environments:
+        GO: ["1.12", "1.13"]
+      aggregate: true
+`
 
 		// and
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
+		gh := mock_github.NewMockGitHub(ctrl)
+		gh.EXPECT().
+			GetContent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return([]byte(config), nil)
+		container.Override(gh)
+		defer container.Clear()
+
+		executed := make(chan *application.BuildJob, 2)
+		executor := mock_executor.NewMockExecutor(ctrl)
+		executor.EXPECT().
+			Execute(gomock.Any(), gomock.Any(), gomock.Eq("make"), gomock.Eq("test")).
+			Times(2).
+			DoAndReturn(func(ctx context.Context, _ job.Target, _ ...string) error {
+				buildJob, err := application.BuildJobFromContext(ctx)
+				if err != nil {
+					t.Errorf("must not be nil, but got %+v", err)
+				}
+				executed <- buildJob
+				return nil
+			})
+
+		// and
+		sut := &webhook.Handler{}
+		defer sut.SetExecutor(executor)()
+
+		// when
+		sut.PushEvent(rec, req)
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("response code must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+
+		// and
+		names := map[string]job.ID{}
+		var aggregates [][]job.Aggregate
+		for i := 0; i < 2; i++ {
+			buildJob := <-executed
+			names[buildJob.TaskName] = buildJob.ID
+			aggregates = append(aggregates, buildJob.Aggregates)
+			if buildJob.Task != "test" {
+				t.Errorf("task must be test, but got %s", buildJob.Task)
+			}
+		}
+		for _, combination := range []string{"GO=1.12", "GO=1.13"} {
+			label := fmt.Sprintf("test (%s)", combination)
+			want := job.ID(uuid.NewSHA1(uuid.Must(uuid.Parse("72d3162e-cc78-11e3-81ab-4c9367dc0958")), []byte(label)))
+			if got, ok := names["duci/"+label]; !ok || got != want {
+				t.Errorf("job of %s must be executed with id %s, but got %+v", label, want, names)
+			}
+		}
+
+		// and
+		want := []job.Aggregate{{
+			Context:   "duci/test",
+			TargetURL: "http://example.com/ui/?repository=Codertocat%2FHello-World",
+			Members:   []string{"duci/test (GO=1.12)", "duci/test (GO=1.13)"},
+		}}
+		for _, got := range aggregates {
+			if !cmp.Equal(got, want) {
+				t.Errorf("must be equal, but %+v", cmp.Diff(got, want))
+			}
+		}
+	})
+
+	t.Run("with pipeline", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		req.Header = http.Header{
+			"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+		}
+
+		// and
+		f, err := os.Open("testdata/push.correct.json")
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		req.Body = f
+
+		// and
+		config := `
+tasks:
+  build:
+    command: [make, build]
+  test:
+    command: [make, test]
+    needs: [build]
+    triggers:
+      tag: ["*"]
+`
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gh := mock_github.NewMockGitHub(ctrl)
+		gh.EXPECT().
+			GetContent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return([]byte(config), nil)
+		container.Override(gh)
+		defer container.Clear()
+
+		executed := make(chan *application.BuildJob, 2)
+		executor := mock_executor.NewMockExecutor(ctrl)
+		executor.EXPECT().
+			Execute(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(2).
+			DoAndReturn(func(ctx context.Context, target job.Target, _ ...string) error {
+				buildJob, err := application.BuildJobFromContext(ctx)
+				if err != nil {
+					t.Errorf("must not be nil, but got %+v", err)
+				}
+				if buildJob.Task == "build" {
+					executed <- buildJob
+					return errors.New("test error")
+				}
+				_, _, err = target.Prepare(ctx)
+				executed <- buildJob
+				return err
+			})
+
+		// and
+		sut := &webhook.Handler{}
+		defer sut.SetExecutor(executor)()
+
+		// when
+		sut.PushEvent(rec, req)
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("response code must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+
+		// and
+		for _, want := range []string{"duci/build", "duci/test"} {
+			got := <-executed
+			if got.TaskName != want {
+				t.Errorf("want: %s, but got: %s", want, got.TaskName)
+			}
+
+			// and
+			wantAggregates := []job.Aggregate{{
+				Context:   "duci/pipeline",
+				TargetURL: "http://example.com/ui/?repository=Codertocat%2FHello-World",
+				Members:   []string{"duci/build", "duci/test"},
+			}}
+			if !cmp.Equal(got.Aggregates, wantAggregates) {
+				t.Errorf("must be equal, but %+v", cmp.Diff(got.Aggregates, wantAggregates))
+			}
+		}
+	})
+
+	t.Run("when no task matches", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		req.Header = http.Header{
+			"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+		}
+
+		// and
+		f, err := os.Open("testdata/push.correct.json")
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		req.Body = f
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gh := mock_github.NewMockGitHub(ctrl)
+		gh.EXPECT().
+			GetContent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return([]byte("tasks:\n  test:\n    triggers:\n      push: [master]\n"), nil)
+		container.Override(gh)
+		defer container.Clear()
+
+		executor := mock_executor.NewMockExecutor(ctrl)
+		executor.EXPECT().
+			Execute(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &webhook.Handler{}
+		defer sut.SetExecutor(executor)()
+
+		// when
+		sut.PushEvent(rec, req)
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("response code must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+
+		// and
+		if rec.Body.String() != `{"message":"skip build"}` {
+			t.Errorf("body must be skip build, but got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("when failure get tasks", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		req.Header = http.Header{
+			"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+		}
+
+		// and
+		f, err := os.Open("testdata/push.correct.json")
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		req.Body = f
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gh := mock_github.NewMockGitHub(ctrl)
+		gh.EXPECT().
+			GetContent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(nil, errors.New("test error"))
+		container.Override(gh)
+		defer container.Clear()
+
+		executor := mock_executor.NewMockExecutor(ctrl)
+		executor.EXPECT().
+			Execute(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &webhook.Handler{}
+		defer sut.SetExecutor(executor)()
+
+		// when
+		sut.PushEvent(rec, req)
+
+		// then
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("response code must be %d, but got %d", http.StatusInternalServerError, rec.Code)
+		}
+	})
+
+	t.Run("when url param is invalid format uuid", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		req.Header = http.Header{
+			"X-Github-Delivery": []string{"invalid format"},
+		}
+
+		// and
+		f, err := os.Open("testdata/push.correct.json")
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		req.Body = f
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		executor := mock_executor.NewMockExecutor(ctrl)
+		executor.EXPECT().
+			Execute(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &webhook.Handler{}
+		defer sut.SetExecutor(executor)()
+
+		// when
+		sut.PushEvent(rec, req)
+
+		// then
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("response code must be %d, but got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("with context prefix of repository", func(t *testing.T) {
+		// given
+		repos := application.Config.Repositories
+		application.Config.Repositories = application.Repositories{
+			"Codertocat/*": &application.Repository{ContextPrefix: "ci"},
+		}
+		defer func() {
+			application.Config.Repositories = repos
+		}()
+
+		// and
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		req.Header = http.Header{
+			"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+		}
+
+		// and
+		f, err := os.Open("testdata/push.correct.json")
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		req.Body = f
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gh := mock_github.NewMockGitHub(ctrl)
+		expectNoTasks(gh)
+		container.Override(gh)
+		defer container.Clear()
+
+		executor := mock_executor.NewMockExecutor(ctrl)
+		executor.EXPECT().
+			Execute(gomock.Any(), gomock.Any()).
+			Times(1).
+			Do(func(ctx context.Context, _ job.Target) {
+				got, err := application.BuildJobFromContext(ctx)
+				if err != nil {
+					t.Errorf("must not be nil, but got %+v", err)
+				}
+
+				if got.TaskName != "ci/push" {
+					t.Errorf("task name must be %s, but got %s", "ci/push", got.TaskName)
+				}
+			}).
+			Return(nil)
+
+		// and
+		sut := &webhook.Handler{}
+		reset := sut.SetExecutor(executor)
+		defer func() {
+			time.Sleep(10 * time.Millisecond) // for goroutine
+			reset()
+		}()
+
+		// when
+		sut.PushEvent(rec, req)
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("response code must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("when repository is not configured", func(t *testing.T) {
+		// given
+		repos := application.Config.Repositories
+		application.Config.Repositories = application.Repositories{
+			"duck8823/*": nil,
+		}
+		defer func() {
+			application.Config.Repositories = repos
+		}()
+
+		// and
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		req.Header = http.Header{
+			"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+		}
+
+		// and
+		f, err := os.Open("testdata/push.correct.json")
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		req.Body = f
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		executor := mock_executor.NewMockExecutor(ctrl)
+		executor.EXPECT().
+			Execute(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &webhook.Handler{}
+		defer sut.SetExecutor(executor)()
+
+		// when
+		sut.PushEvent(rec, req)
+
+		// then
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("response code must be %d, but got %d", http.StatusForbidden, rec.Code)
+		}
+	})
+
+	t.Run("with invalid payload", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		req.Header = http.Header{
+			"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+		}
+
+		// and
+		req.Body = ioutils.NewReadCloserWrapper(strings.NewReader("invalid payload"), func() error {
+			return nil
+		})
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		executor := mock_executor.NewMockExecutor(ctrl)
+		executor.EXPECT().
+			Execute(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &webhook.Handler{}
+		defer sut.SetExecutor(executor)()
+
+		// when
+		sut.PushEvent(rec, req)
+
+		// then
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("response code must be %d, but got %d", http.StatusInternalServerError, rec.Code)
+		}
+	})
+}
+
+func TestHandler_IssueCommentEvent_Normal(t *testing.T) {
+	defer webhook.SetNowFunc(func() time.Time {
+		return queuedAt
+	})()
+
+	t.Run("with no error", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		req.Header = http.Header{
+			"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+		}
+
+		// and
+		f, err := os.Open("testdata/issue_comment.correct.json")
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		req.Body = f
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gh := mock_github.NewMockGitHub(ctrl)
+		gh.EXPECT().
+			GetPullRequest(gomock.Any(), gomock.Any(), gomock.Eq(2)).
+			Times(1).
+			Return(&go_github.PullRequest{
+				Head: &go_github.PullRequestBranch{
+					Ref: go_github.String("dummy"),
+					SHA: go_github.String("aa218f56b14c9653891f9e74264a383fa43fefbd"),
+				},
+			}, nil)
+		expectNoTasks(gh)
+		container.Override(gh)
+		defer container.Clear()
+
+		executor := mock_executor.NewMockExecutor(ctrl)
+		executor.EXPECT().
+			Execute(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(1).
+			Do(func(ctx context.Context, target job.Target, cmd ...string) {
+				got, err := application.BuildJobFromContext(ctx)
+				if err != nil {
+					t.Errorf("must not be nil, but got %+v", err)
+				}
+
+				want := &application.BuildJob{
+					ID: job.ID(uuid.Must(uuid.Parse("72d3162e-cc78-11e3-81ab-4c9367dc0958"))),
+					TargetSource: &github.TargetSource{
+						Repository: &go_github.Repository{
+							ID:       go_github.Int64(135493233),
+							FullName: go_github.String("Codertocat/Hello-World"),
+							SSHURL:   go_github.String("git@github.com:Codertocat/Hello-World.git"),
+							CloneURL: go_github.String("https://github.com/Codertocat/Hello-World.git"),
+						},
+						Ref: "refs/heads/dummy",
+						SHA: plumbing.NewHash("aa218f56b14c9653891f9e74264a383fa43fefbd"),
+					},
+					TaskName:  "duci/pr/build",
+					TargetURL: webhook.URLMust(url.Parse("http://example.com/ui/jobs/72d3162e-cc78-11e3-81ab-4c9367dc0958")),
+					Event:     "issue_comment",
+					QueuedAt:  queuedAt,
+					Source:    (*go_github.Repository)(nil),
+					Command:   []string{"build"},
+				}
+
+				opt := cmp.Options{
+					webhook.CmpOptsAllowFields(go_github.Repository{}, "ID", "FullName", "SSHURL", "CloneURL"),
+					cmp.AllowUnexported(application.BuildJob{}),
+				}
+				if !cmp.Equal(got, want, opt) {
+					t.Errorf("must be equal but: %+v", cmp.Diff(got, want, opt))
+				}
+
+				typ := reflect.TypeOf(target).String()
+				if typ != "*target.GitHub" {
+					t.Errorf("type must be *target.GitHub, but got %s", typ)
+				}
+			}).
+			Return(nil)
+
+		// and
+		sut := &webhook.Handler{}
+		reset := sut.SetExecutor(executor)
+		defer func() {
+			time.Sleep(10 * time.Millisecond) // for goroutine
+			reset()
+		}()
+
+		// when
+		sut.IssueCommentEvent(rec, req)
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("response code must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("with retry phrase", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		req.Header = http.Header{
+			"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+		}
+
+		// and
+		f, err := os.Open("testdata/issue_comment.retry.json")
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		req.Body = f
+
+		// and
+		original := job.Job{
+			ID: job.ID(uuid.Must(uuid.Parse("6b5d2c1e-5f0e-4a6e-9b1a-2f8c6f0c3d4e"))),
+			Trigger: &job.Trigger{
+				Repository: "Codertocat/Hello-World",
+				Ref:        "refs/heads/dummy",
+				SHA:        "aa218f56b14c9653891f9e74264a383fa43fefbd",
+				Event:      "issue_comment",
+				TaskName:   "duci/pr/test",
+				Source:     &job.Source{FullName: "Codertocat/Hello-World"},
+				Command:    []string{"test"},
+			},
+			State:    job.FAILURE,
+			Finished: true,
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gh := mock_github.NewMockGitHub(ctrl)
+		gh.EXPECT().
+			GetPullRequest(gomock.Any(), gomock.Any(), gomock.Eq(2)).
+			Times(1).
+			Return(&go_github.PullRequest{
+				Head: &go_github.PullRequestBranch{
+					Ref: go_github.String("dummy"),
+					SHA: go_github.String("aa218f56b14c9653891f9e74264a383fa43fefbd"),
+				},
+			}, nil)
+		container.Override(gh)
+		defer container.Clear()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Search(gomock.Eq(job.Query{
+				Repository: "Codertocat/Hello-World",
+				SHA:        "aa218f56b14c9653891f9e74264a383fa43fefbd",
+				Limit:      100,
+			})).
+			Times(1).
+			Return(&job.Page{Jobs: []job.Job{original}}, nil)
+
+		executor := mock_executor.NewMockExecutor(ctrl)
+		executor.EXPECT().
+			Execute(gomock.Any(), gomock.Any(), gomock.Eq("test")).
+			Times(1).
+			Do(func(ctx context.Context, target job.Target, cmd ...string) {
+				got, err := application.BuildJobFromContext(ctx)
+				if err != nil {
+					t.Errorf("must not be nil, but got %+v", err)
+				}
+
+				want := job.ID(uuid.NewSHA1(uuid.Must(uuid.Parse("72d3162e-cc78-11e3-81ab-4c9367dc0958")), []byte("duci/pr/test")))
+				if got.ID != want {
+					t.Errorf("id must be %s, but got %s", want, got.ID)
+				}
+
+				if got.RerunOf == nil || *got.RerunOf != original.ID {
+					t.Errorf("must rerun of %s, but got %+v", original.ID, got.RerunOf)
+				}
+
+				if got.TaskName != "duci/pr/test" {
+					t.Errorf("task name must be duci/pr/test, but got %s", got.TaskName)
+				}
+			}).
+			Return(nil)
+
+		// and
+		sut := &webhook.Handler{}
+		defer sut.SetService(service)()
+		reset := sut.SetExecutor(executor)
+		defer func() {
+			time.Sleep(10 * time.Millisecond) // for goroutine
+			reset()
+		}()
+
+		// when
+		sut.IssueCommentEvent(rec, req)
+
+		// then
+		if rec.Code != http.StatusOK {
+			t.Errorf("response code must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("with jobs of multiple tasks in pages", func(t *testing.T) {
+		// given
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		req.Header = http.Header{
+			"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+		}
+
+		// and
+		f, err := os.Open("testdata/issue_comment.retry.json")
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		req.Body = f
+
+		// and
+		original := job.Job{
+			ID: job.ID(uuid.Must(uuid.Parse("6b5d2c1e-5f0e-4a6e-9b1a-2f8c6f0c3d4e"))),
+			Trigger: &job.Trigger{
+				Repository: "Codertocat/Hello-World",
+				Ref:        "refs/heads/dummy",
+				SHA:        "aa218f56b14c9653891f9e74264a383fa43fefbd",
+				Event:      "issue_comment",
+				TaskName:   "duci/pr/test",
+				Source:     &job.Source{FullName: "Codertocat/Hello-World"},
+				Command:    []string{"test"},
+			},
+			State:    job.FAILURE,
+			Finished: true,
+		}
+		older := original
+		older.ID = job.ID(uuid.Must(uuid.Parse("0c6bd4a8-0e4a-4f2f-9c53-6b0f5e6a8d21")))
+		lint := original
+		lint.ID = job.ID(uuid.Must(uuid.Parse("9a1e7c3b-2d4f-4e8a-8b6c-1f2e3d4c5b6a")))
+		lint.Trigger = &job.Trigger{
+			Repository: "Codertocat/Hello-World",
+			Ref:        "refs/heads/dummy",
+			SHA:        "aa218f56b14c9653891f9e74264a383fa43fefbd",
+			Event:      "issue_comment",
+			TaskName:   "duci/pr/lint",
+			Source:     &job.Source{FullName: "Codertocat/Hello-World"},
+			Command:    []string{"lint"},
+		}
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gh := mock_github.NewMockGitHub(ctrl)
+		gh.EXPECT().
+			GetPullRequest(gomock.Any(), gomock.Any(), gomock.Eq(2)).
+			Times(1).
+			Return(&go_github.PullRequest{
+				Head: &go_github.PullRequestBranch{
+					Ref: go_github.String("dummy"),
+					SHA: go_github.String("aa218f56b14c9653891f9e74264a383fa43fefbd"),
+				},
+			}, nil)
+		container.Override(gh)
+		defer container.Clear()
+
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Search(gomock.Eq(job.Query{
+				Repository: "Codertocat/Hello-World",
+				SHA:        "aa218f56b14c9653891f9e74264a383fa43fefbd",
+				Limit:      100,
+			})).
+			Times(1).
+			Return(&job.Page{Jobs: []job.Job{original}, Next: "next"}, nil)
+		service.EXPECT().
+			Search(gomock.Eq(job.Query{
+				Repository: "Codertocat/Hello-World",
+				SHA:        "aa218f56b14c9653891f9e74264a383fa43fefbd",
+				Cursor:     "next",
+				Limit:      100,
+			})).
+			Times(1).
+			Return(&job.Page{Jobs: []job.Job{lint, older}}, nil)
+
+		var mu sync.Mutex
+		var rerunOf []job.ID
 		executor := mock_executor.NewMockExecutor(ctrl)
 		executor.EXPECT().
-			Execute(gomock.Any(), gomock.Any()).
-			Times(0)
+			Execute(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(2).
+			Do(func(ctx context.Context, target job.Target, cmd ...string) {
+				got, err := application.BuildJobFromContext(ctx)
+				if err != nil {
+					t.Errorf("must not be nil, but got %+v", err)
+				}
+
+				mu.Lock()
+				rerunOf = append(rerunOf, *got.RerunOf)
+				mu.Unlock()
+			}).
+			Return(nil)
 
 		// and
 		sut := &webhook.Handler{}
-		defer sut.SetExecutor(executor)()
+		defer sut.SetService(service)()
+		reset := sut.SetExecutor(executor)
+		defer func() {
+			time.Sleep(10 * time.Millisecond) // for goroutine
+			reset()
+		}()
 
 		// when
-		sut.PushEvent(rec, req)
+		sut.IssueCommentEvent(rec, req)
 
 		// then
-		if rec.Code != http.StatusInternalServerError {
-			t.Errorf("response code must be %d, but got %d", http.StatusInternalServerError, rec.Code)
+		if rec.Code != http.StatusOK {
+			t.Errorf("response code must be %d, but got %d", http.StatusOK, rec.Code)
+		}
+
+		// and
+		time.Sleep(10 * time.Millisecond) // for goroutine
+		mu.Lock()
+		defer mu.Unlock()
+		want := []job.ID{original.ID, lint.ID}
+		opt := cmpopts.SortSlices(func(a, b job.ID) bool { return a.String() < b.String() })
+		if !cmp.Equal(rerunOf, want, opt) {
+			t.Errorf("must rerun latest job of each task, but %+v", cmp.Diff(rerunOf, want, opt))
 		}
 	})
-}
 
-func TestHandler_IssueCommentEvent_Normal(t *testing.T) {
-	t.Run("with no error", func(t *testing.T) {
+	t.Run("when there is no job to retry", func(t *testing.T) {
 		// given
 		rec := httptest.NewRecorder()
 		req := httptest.NewRequest("GET", "/", nil)
@@ -385,7 +1466,7 @@ func TestHandler_IssueCommentEvent_Normal(t *testing.T) {
 		}
 
 		// and
-		f, err := os.Open("testdata/issue_comment.correct.json")
+		f, err := os.Open("testdata/issue_comment.retry.json")
 		if err != nil {
 			t.Fatalf("error occur: %+v", err)
 		}
@@ -408,61 +1489,28 @@ func TestHandler_IssueCommentEvent_Normal(t *testing.T) {
 		container.Override(gh)
 		defer container.Clear()
 
+		service := mock_job_service.NewMockService(ctrl)
+		service.EXPECT().
+			Search(gomock.Any()).
+			Times(1).
+			Return(&job.Page{Jobs: []job.Job{{State: job.RUNNING}}}, nil)
+
 		executor := mock_executor.NewMockExecutor(ctrl)
 		executor.EXPECT().
 			Execute(gomock.Any(), gomock.Any(), gomock.Any()).
-			Times(1).
-			Do(func(ctx context.Context, target job.Target, cmd ...string) {
-				got, err := application.BuildJobFromContext(ctx)
-				if err != nil {
-					t.Errorf("must not be nil, but got %+v", err)
-				}
-
-				want := &application.BuildJob{
-					ID: job.ID(uuid.Must(uuid.Parse("72d3162e-cc78-11e3-81ab-4c9367dc0958"))),
-					TargetSource: &github.TargetSource{
-						Repository: &go_github.Repository{
-							ID:       go_github.Int64(135493233),
-							FullName: go_github.String("Codertocat/Hello-World"),
-							SSHURL:   go_github.String("git@github.com:Codertocat/Hello-World.git"),
-							CloneURL: go_github.String("https://github.com/Codertocat/Hello-World.git"),
-						},
-						Ref: "refs/heads/dummy",
-						SHA: plumbing.NewHash("aa218f56b14c9653891f9e74264a383fa43fefbd"),
-					},
-					TaskName:  "duci/pr/build",
-					TargetURL: webhook.URLMust(url.Parse("http://example.com/logs/72d3162e-cc78-11e3-81ab-4c9367dc0958")),
-				}
-
-				opt := cmp.Options{
-					webhook.CmpOptsAllowFields(go_github.Repository{}, "ID", "FullName", "SSHURL", "CloneURL"),
-					cmp.AllowUnexported(application.BuildJob{}),
-				}
-				if !cmp.Equal(got, want, opt) {
-					t.Errorf("must be equal but: %+v", cmp.Diff(got, want, opt))
-				}
-
-				typ := reflect.TypeOf(target).String()
-				if typ != "*target.GitHub" {
-					t.Errorf("type must be *target.GitHub, but got %s", typ)
-				}
-			}).
-			Return(nil)
+			Times(0)
 
 		// and
 		sut := &webhook.Handler{}
-		reset := sut.SetExecutor(executor)
-		defer func() {
-			time.Sleep(10 * time.Millisecond) // for goroutine
-			reset()
-		}()
+		defer sut.SetService(service)()
+		defer sut.SetExecutor(executor)()
 
 		// when
 		sut.IssueCommentEvent(rec, req)
 
 		// then
-		if rec.Code != http.StatusOK {
-			t.Errorf("response code must be %d, but got %d", http.StatusOK, rec.Code)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("response code must be %d, but got %d", http.StatusNotFound, rec.Code)
 		}
 	})
 
@@ -743,9 +1791,205 @@ func TestHandler_IssueCommentEvent_UnNormal(t *testing.T) {
 			t.Errorf("response code must be %d, but got %d", http.StatusBadRequest, rec.Code)
 		}
 	})
+
+	t.Run("when commenter is not allowed", func(t *testing.T) {
+		// given
+		commenters := application.Config.GitHub.Commenters
+		application.Config.GitHub.Commenters = &application.Commenters{Users: []string{"duck8823"}}
+		defer func() {
+			application.Config.GitHub.Commenters = commenters
+		}()
+
+		// and
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		req.Header = http.Header{
+			"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+		}
+
+		// and
+		f, err := os.Open("testdata/issue_comment.correct.json")
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		req.Body = f
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gh := mock_github.NewMockGitHub(ctrl)
+		gh.EXPECT().
+			GetPullRequest(gomock.Any(), gomock.Any(), gomock.Eq(2)).
+			Times(1).
+			Return(&go_github.PullRequest{
+				Head: &go_github.PullRequestBranch{
+					Ref: go_github.String("refs/test/dummy"),
+					SHA: go_github.String("aa218f56b14c9653891f9e74264a383fa43fefbd"),
+				},
+			}, nil)
+		gh.EXPECT().
+			CreateComment(gomock.Any(), gomock.Any(), gomock.Eq(2), gomock.Eq("Ignored `ci build`: @Codertocat is not allowed to trigger builds: not in the allow list.")).
+			Times(1).
+			Return(nil)
+		container.Override(gh)
+		defer container.Clear()
+
+		executor := mock_executor.NewMockExecutor(ctrl)
+		executor.EXPECT().
+			Execute(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &webhook.Handler{}
+		defer sut.SetExecutor(executor)()
+
+		// when
+		sut.IssueCommentEvent(rec, req)
+
+		// then
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("response code must be %d, but got %d", http.StatusForbidden, rec.Code)
+		}
+	})
+
+	t.Run("when commenter is not allowed with comment reply", func(t *testing.T) {
+		// given
+		commenters := application.Config.GitHub.Commenters
+		application.Config.GitHub.Commenters = &application.Commenters{Users: []string{"duck8823"}, Reply: application.ReplyComment}
+		defer func() {
+			application.Config.GitHub.Commenters = commenters
+		}()
+
+		// and
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		req.Header = http.Header{
+			"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+		}
+
+		// and
+		f, err := os.Open("testdata/issue_comment.correct.json")
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		req.Body = f
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gh := mock_github.NewMockGitHub(ctrl)
+		gh.EXPECT().
+			GetPullRequest(gomock.Any(), gomock.Any(), gomock.Eq(2)).
+			Times(1).
+			Return(&go_github.PullRequest{
+				Head: &go_github.PullRequestBranch{
+					Ref: go_github.String("refs/test/dummy"),
+					SHA: go_github.String("aa218f56b14c9653891f9e74264a383fa43fefbd"),
+				},
+			}, nil)
+		gh.EXPECT().
+			CreateComment(gomock.Any(), gomock.Any(), gomock.Eq(2), gomock.Eq("Ignored `ci build`: you are not allowed to trigger builds.")).
+			Times(1).
+			Return(nil)
+		container.Override(gh)
+		defer container.Clear()
+
+		executor := mock_executor.NewMockExecutor(ctrl)
+		executor.EXPECT().
+			Execute(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &webhook.Handler{}
+		defer sut.SetExecutor(executor)()
+
+		// when
+		sut.IssueCommentEvent(rec, req)
+
+		// then
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("response code must be %d, but got %d", http.StatusForbidden, rec.Code)
+		}
+	})
+
+	t.Run("when commenter is not allowed with reaction reply", func(t *testing.T) {
+		// given
+		commenters := application.Config.GitHub.Commenters
+		application.Config.GitHub.Commenters = &application.Commenters{Users: []string{"duck8823"}, Reply: application.ReplyReaction}
+		defer func() {
+			application.Config.GitHub.Commenters = commenters
+		}()
+
+		// and
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+
+		// and
+		req.Header = http.Header{
+			"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"},
+		}
+
+		// and
+		f, err := os.Open("testdata/issue_comment.correct.json")
+		if err != nil {
+			t.Fatalf("error occur: %+v", err)
+		}
+		req.Body = f
+
+		// and
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gh := mock_github.NewMockGitHub(ctrl)
+		gh.EXPECT().
+			GetPullRequest(gomock.Any(), gomock.Any(), gomock.Eq(2)).
+			Times(1).
+			Return(&go_github.PullRequest{
+				Head: &go_github.PullRequestBranch{
+					Ref: go_github.String("refs/test/dummy"),
+					SHA: go_github.String("aa218f56b14c9653891f9e74264a383fa43fefbd"),
+				},
+			}, nil)
+		gh.EXPECT().
+			CreateCommentReaction(gomock.Any(), gomock.Any(), gomock.Eq(int64(393304133)), gomock.Eq("confused")).
+			Times(1).
+			Return(nil)
+		gh.EXPECT().
+			CreateComment(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+		container.Override(gh)
+		defer container.Clear()
+
+		executor := mock_executor.NewMockExecutor(ctrl)
+		executor.EXPECT().
+			Execute(gomock.Any(), gomock.Any(), gomock.Any()).
+			Times(0)
+
+		// and
+		sut := &webhook.Handler{}
+		defer sut.SetExecutor(executor)()
+
+		// when
+		sut.IssueCommentEvent(rec, req)
+
+		// then
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("response code must be %d, but got %d", http.StatusForbidden, rec.Code)
+		}
+	})
 }
 
 func TestHandler_PullRequestEvent(t *testing.T) {
+	defer webhook.SetNowFunc(func() time.Time {
+		return queuedAt
+	})()
+
 	for _, tt := range []struct {
 		action  string
 		payload string
@@ -780,6 +2024,11 @@ func TestHandler_PullRequestEvent(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
+			gh := mock_github.NewMockGitHub(ctrl)
+			expectNoTasks(gh)
+			container.Override(gh)
+			defer container.Clear()
+
 			executor := mock_executor.NewMockExecutor(ctrl)
 			executor.EXPECT().
 				Execute(gomock.Any(), gomock.Any()).
@@ -803,7 +2052,15 @@ func TestHandler_PullRequestEvent(t *testing.T) {
 							SHA: plumbing.NewHash("34c5c7793cb3b279e22454cb6750c80560547b3a"),
 						},
 						TaskName:  "duci/pr",
-						TargetURL: webhook.URLMust(url.Parse("http://example.com/logs/72d3162e-cc78-11e3-81ab-4c9367dc0958")),
+						TargetURL: webhook.URLMust(url.Parse("http://example.com/ui/jobs/72d3162e-cc78-11e3-81ab-4c9367dc0958")),
+						Event:     "pull_request",
+						QueuedAt:  queuedAt,
+						Source: &go_github.Repository{
+							ID:       go_github.Int64(135493233),
+							FullName: go_github.String("Codertocat/Hello-World"),
+							SSHURL:   go_github.String("git@github.com:Codertocat/Hello-World.git"),
+							CloneURL: go_github.String("https://github.com/Codertocat/Hello-World.git"),
+						},
 					}
 
 					opt := cmp.Options{
@@ -958,3 +2215,10 @@ func TestHandler_PullRequestEvent(t *testing.T) {
 		}
 	})
 }
+
+func expectNoTasks(gh *mock_github.MockGitHub) {
+	gh.EXPECT().
+		GetContent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Eq(".duci/config.yml")).
+		AnyTimes().
+		Return(nil, github.ErrContentNotFound)
+}
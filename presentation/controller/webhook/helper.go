@@ -3,14 +3,18 @@ package webhook
 import (
 	"context"
 	"fmt"
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/application/service/executor"
 	"github.com/duck8823/duci/domain/model/job"
 	"github.com/duck8823/duci/domain/model/job/target"
 	"github.com/duck8823/duci/domain/model/job/target/github"
+	"github.com/duck8823/duci/domain/model/task"
 	go_github "github.com/google/go-github/github"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"net/http"
 	"net/url"
+	"os"
 )
 
 func reqID(r *http.Request) (job.ID, error) {
@@ -55,6 +59,96 @@ func targetGitHub(event *go_github.IssueCommentEvent) (*target.GitHub, error) {
 	}, nil
 }
 
+// tasksOf returns tasks declared in `.duci/config.yml` of the repository at the commit, or nil if the file does not exist.
+func tasksOf(ctx context.Context, repo github.Repository, sha string) (task.Tasks, error) {
+	gh, err := github.GetInstance()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	content, err := gh.GetContent(ctx, repo, sha, ".duci/config.yml")
+	if err == github.ErrContentNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	tasks, err := task.Parse([]byte(os.ExpandEnv(string(content))))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return tasks, nil
+}
+
+// taskJobOf returns a job of the task derived from the job of event.
+// The id is unique for each task and combination of matrix in the event.
+func taskJobOf(buildJob *application.BuildJob, repo *application.Repository, name string, t *task.Task, combination task.Combination) *application.BuildJob {
+	label := name
+	if len(combination) > 0 {
+		label = fmt.Sprintf("%s (%s)", name, combination)
+	}
+
+	taskJob := *buildJob
+	taskJob.ID = job.ID(uuid.NewSHA1(uuid.UUID(buildJob.ID), []byte(label)))
+	taskJob.Task = name
+	taskJob.TaskName = fmt.Sprintf("%s/%s", repo.ContextPrefix, label)
+	taskJob.Command = t.Command
+	taskJob.Timeout = t.TimeoutDuration()
+	taskJob.Matrix = combination
+
+	targetURL := *buildJob.TargetURL
+	targetURL.Path = fmt.Sprintf("/ui/jobs/%s", taskJob.ID.ToSlice())
+	taskJob.TargetURL = &targetURL
+	return &taskJob
+}
+
+// stageOf returns a stage of the jobs of task.
+// Jobs of matrix share the aggregate status if required, which is reported from their stored states whenever one of them is queued or ends.
+func stageOf(buildJob *application.BuildJob, repo *application.Repository, name string, t *task.Task) executor.Stage {
+	stage := executor.Stage{Name: name, Needs: t.Needs}
+
+	combinations := t.Matrix.Combinations()
+	if len(combinations) == 0 {
+		stage.Jobs = []*application.BuildJob{taskJobOf(buildJob, repo, name, t, nil)}
+		return stage
+	}
+
+	for _, combination := range combinations {
+		stage.Jobs = append(stage.Jobs, taskJobOf(buildJob, repo, name, t, combination))
+	}
+	stage.FailFast = t.Matrix.FailFast
+	if t.Matrix.Aggregate {
+		shareAggregate(buildJob, repo, name, stage)
+	}
+	return stage
+}
+
+// shareAggregate makes the jobs of stages share the aggregate status of the name, which summarises all of them
+func shareAggregate(buildJob *application.BuildJob, repo *application.Repository, name string, stages ...executor.Stage) {
+	aggregate := aggregateOf(buildJob, repo, name)
+	for _, stage := range stages {
+		for _, taskJob := range stage.Jobs {
+			aggregate.Members = append(aggregate.Members, taskJob.TaskName)
+		}
+	}
+	for _, stage := range stages {
+		for _, taskJob := range stage.Jobs {
+			taskJob.Aggregates = append(taskJob.Aggregates, aggregate)
+		}
+	}
+}
+
+// aggregateOf returns a aggregate status summarising jobs of the matrix or the pipeline
+func aggregateOf(buildJob *application.BuildJob, repo *application.Repository, name string) job.Aggregate {
+	targetURL := *buildJob.TargetURL
+	targetURL.Path = "/ui/"
+	targetURL.RawQuery = url.Values{"repository": []string{buildJob.TargetSource.GetFullName()}}.Encode()
+	return job.Aggregate{
+		Context:   fmt.Sprintf("%s/%s", repo.ContextPrefix, name),
+		TargetURL: targetURL.String(),
+	}
+}
+
 func isValidAction(action *string) bool {
 	if action == nil {
 		return false
@@ -8,6 +8,9 @@ import (
 
 type phrase string
 
+// retryPhrase reruns the latest job for the commit instead of building a new command
+const retryPhrase phrase = "retry"
+
 // Command returns command of docker
 func (p phrase) Command() docker.Command {
 	return strings.Split(string(p), " ")
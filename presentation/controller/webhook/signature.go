@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/pkg/errors"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+// ErrInvalidSignature represents error of invalid webhook signature
+var ErrInvalidSignature = errors.New("Invalid signature")
+
+type signatureHeader struct {
+	name   string
+	prefix string
+	hash   func() hash.Hash
+}
+
+var signatureHeaders = []signatureHeader{
+	{name: "X-Hub-Signature-256", prefix: "sha256=", hash: sha256.New},
+	{name: "X-Hub-Signature", prefix: "sha1=", hash: sha1.New},
+}
+
+// verifySignature checks every signature header in the request against HMAC of payload.
+func verifySignature(secret string, header http.Header, payload []byte) error {
+	var verified bool
+	for _, sig := range signatureHeaders {
+		value := header.Get(sig.name)
+		if len(value) == 0 {
+			continue
+		}
+		if !strings.HasPrefix(value, sig.prefix) {
+			return errors.Wrap(ErrInvalidSignature, fmt.Sprintf("unsupported format of `%s`", sig.name))
+		}
+		got, err := hex.DecodeString(strings.TrimPrefix(value, sig.prefix))
+		if err != nil {
+			return errors.Wrap(ErrInvalidSignature, fmt.Sprintf("malformed `%s`", sig.name))
+		}
+
+		mac := hmac.New(sig.hash, []byte(secret))
+		_, _ = mac.Write(payload)
+		if !hmac.Equal(got, mac.Sum(nil)) {
+			return errors.Wrap(ErrInvalidSignature, fmt.Sprintf("mismatch `%s`", sig.name))
+		}
+		verified = true
+	}
+
+	if !verified {
+		return errors.Wrap(ErrInvalidSignature, "request header `X-Hub-Signature-256` or `X-Hub-Signature` is required")
+	}
+	return nil
+}
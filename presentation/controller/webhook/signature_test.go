@@ -0,0 +1,102 @@
+package webhook_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/duck8823/duci/presentation/controller/webhook"
+	"github.com/pkg/errors"
+	"hash"
+	"net/http"
+	"testing"
+)
+
+func TestVerifySignature(t *testing.T) {
+	// given
+	secret := "It's a Secret to Everybody"
+	payload := []byte("Hello, World!")
+
+	// where
+	for _, tt := range []struct {
+		name    string
+		header  http.Header
+		wantErr bool
+	}{
+		{
+			name: "with correct sha256 signature",
+			header: http.Header{
+				"X-Hub-Signature-256": []string{sign(sha256.New, "sha256=", secret, payload)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "with correct legacy sha1 signature",
+			header: http.Header{
+				"X-Hub-Signature": []string{sign(sha1.New, "sha1=", secret, payload)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "with both correct signatures",
+			header: http.Header{
+				"X-Hub-Signature-256": []string{sign(sha256.New, "sha256=", secret, payload)},
+				"X-Hub-Signature":     []string{sign(sha1.New, "sha1=", secret, payload)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "with correct sha256 signature but wrong sha1 signature",
+			header: http.Header{
+				"X-Hub-Signature-256": []string{sign(sha256.New, "sha256=", secret, payload)},
+				"X-Hub-Signature":     []string{sign(sha1.New, "sha1=", "wrong secret", payload)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "with signature signed by wrong secret",
+			header: http.Header{
+				"X-Hub-Signature-256": []string{sign(sha256.New, "sha256=", "wrong secret", payload)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "with unsupported prefix",
+			header: http.Header{
+				"X-Hub-Signature-256": []string{sign(sha256.New, "sha512=", secret, payload)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "with malformed signature",
+			header: http.Header{
+				"X-Hub-Signature-256": []string{"sha256=invalid"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "without signature",
+			header:  http.Header{},
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			err := webhook.VerifySignature(secret, tt.header, payload)
+
+			// then
+			if tt.wantErr && errors.Cause(err) != webhook.ErrInvalidSignature {
+				t.Errorf("error must be %+v, but got %+v", webhook.ErrInvalidSignature, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("error must be nil, but got %+v", err)
+			}
+		})
+	}
+}
+
+func sign(h func() hash.Hash, prefix string, secret string, payload []byte) string {
+	mac := hmac.New(h, []byte(secret))
+	_, _ = mac.Write(payload)
+	return prefix + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,28 @@
+package router
+
+import (
+	"crypto/subtle"
+	"github.com/duck8823/duci/application"
+	"net/http"
+	"strings"
+)
+
+// adminOnly returns handler which requires the admin token of server configuration as bearer token.
+// Requests are forbidden if the admin token is not configured.
+func adminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := application.Config.Server.AdminToken.String()
+		if len(token) == 0 {
+			http.Error(w, "admin token is not configured", http.StatusForbidden)
+			return
+		}
+
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "invalid admin token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
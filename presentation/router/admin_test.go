@@ -0,0 +1,69 @@
+package router_test
+
+import (
+	"github.com/duck8823/duci/application"
+	"github.com/duck8823/duci/presentation/router"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminOnly(t *testing.T) {
+	// given
+	token := application.Config.Server.AdminToken
+	defer func() {
+		application.Config.Server.AdminToken = token
+	}()
+
+	// and
+	sut := router.AdminOnly(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("when admin token is configured", func(t *testing.T) {
+		// given
+		application.Config.Server.AdminToken = "duci_admin_token"
+
+		// where
+		for _, tt := range []struct {
+			authorization string
+			want          int
+		}{
+			{authorization: "Bearer duci_admin_token", want: http.StatusOK},
+			{authorization: "Bearer wrong", want: http.StatusUnauthorized},
+			{authorization: "duci_admin_token_suffix", want: http.StatusUnauthorized},
+			{authorization: "", want: http.StatusUnauthorized},
+		} {
+			// given
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/gc", nil)
+			req.Header.Set("Authorization", tt.authorization)
+
+			// when
+			sut(rec, req)
+
+			// then
+			if rec.Code != tt.want {
+				t.Errorf("response code must be %d with %s, but got %d", tt.want, tt.authorization, rec.Code)
+			}
+		}
+	})
+
+	t.Run("when admin token is not configured", func(t *testing.T) {
+		// given
+		application.Config.Server.AdminToken = ""
+
+		// and
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/gc", nil)
+		req.Header.Set("Authorization", "Bearer ")
+
+		// when
+		sut(rec, req)
+
+		// then
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("response code must be %d, but got %d", http.StatusForbidden, rec.Code)
+		}
+	})
+}
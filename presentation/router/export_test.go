@@ -0,0 +1,3 @@
+package router
+
+var AdminOnly = adminOnly
@@ -1,8 +1,10 @@
 package router
 
 import (
+	"github.com/duck8823/duci/presentation/controller/dashboard"
 	"github.com/duck8823/duci/presentation/controller/health"
 	"github.com/duck8823/duci/presentation/controller/job"
+	"github.com/duck8823/duci/presentation/controller/jobs"
 	"github.com/duck8823/duci/presentation/controller/webhook"
 	"github.com/go-chi/chi"
 	"github.com/pkg/errors"
@@ -21,15 +23,59 @@ func New() (http.Handler, error) {
 		return nil, errors.WithStack(err)
 	}
 
+	eventsHandler, err := job.NewEventsHandler()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	websocketHandler, err := job.NewWebSocketHandler()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	jobsHandler, err := jobs.NewHandler()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	cancelHandler, err := jobs.NewCancelHandler()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	rerunHandler, err := jobs.NewRerunHandler()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	gcHandler, err := jobs.NewGCHandler()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
 	healthHandler, err := health.NewHandler()
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
+	dashboardHandler, err := dashboard.NewHandler()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
 	rtr := chi.NewRouter()
 	rtr.Post("/", webhookHandler.ServeHTTP)
 	rtr.Get("/logs/{uuid}", jobHandler.ServeHTTP)
+	rtr.Get("/logs/{uuid}/events", eventsHandler.ServeHTTP)
+	rtr.Get("/logs/{uuid}/ws", websocketHandler.ServeHTTP)
+	rtr.Get("/jobs", jobsHandler.ServeHTTP)
+	rtr.Get("/jobs/{id}", jobsHandler.ServeHTTP)
+	rtr.Post("/jobs/{id}/cancel", adminOnly(cancelHandler.ServeHTTP))
+	rtr.Post("/jobs/{id}/rerun", adminOnly(rerunHandler.ServeHTTP))
+	rtr.Post("/gc", adminOnly(gcHandler.ServeHTTP))
 	rtr.Get("/health", healthHandler.ServeHTTP)
+	rtr.Get("/", http.RedirectHandler(dashboard.Prefix, http.StatusFound).ServeHTTP)
+	rtr.Get(dashboard.Prefix+"*", dashboardHandler.ServeHTTP)
 
 	return rtr, nil
 }